@@ -2,12 +2,14 @@ package api
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/guardian"
 )
 
 func ValidateOrgAlert(c *middleware.Context) {
@@ -25,6 +27,25 @@ func ValidateOrgAlert(c *middleware.Context) {
 	}
 }
 
+// checkAlertPermission checks the permission of the user on the dashboard
+// of the alert. Standalone alerts have no dashboard and are not checked.
+func checkAlertPermission(c *middleware.Context, alertId int64, permission models.PermissionType) Response {
+	query := models.GetAlertByIdQuery{Id: alertId}
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(404, "Alert not found", nil)
+	}
+
+	if query.Result.OrgId != c.OrgId {
+		return ApiError(403, "You are not allowed to edit/view alert", nil)
+	}
+
+	if query.Result.DashboardId == 0 {
+		return nil
+	}
+
+	return checkDashboardPermission(c, query.Result.DashboardId, permission)
+}
+
 func GetAlertStatesForDashboard(c *middleware.Context) Response {
 	dashboardId := c.QueryInt64("dashboardId")
 
@@ -32,6 +53,10 @@ func GetAlertStatesForDashboard(c *middleware.Context) Response {
 		return ApiError(400, "Missing query parameter dashboardId", nil)
 	}
 
+	if rsp := checkDashboardPermission(c, dashboardId, models.PERMISSION_VIEW); rsp != nil {
+		return rsp
+	}
+
 	query := models.GetAlertStatesForDashboardQuery{
 		OrgId:       c.OrgId,
 		DashboardId: c.QueryInt64("dashboardId"),
@@ -49,6 +74,7 @@ func GetAlerts(c *middleware.Context) Response {
 	query := models.GetAlertsQuery{
 		OrgId:       c.OrgId,
 		DashboardId: c.QueryInt64("dashboardId"),
+		FolderId:    c.QueryInt64("folderId"),
 		PanelId:     c.QueryInt64("panelId"),
 		Limit:       c.QueryInt64("limit"),
 	}
@@ -66,7 +92,7 @@ func GetAlerts(c *middleware.Context) Response {
 	alertDTOs := make([]*dtos.AlertRule, 0)
 	for _, alert := range query.Result {
 		dashboardIds = append(dashboardIds, alert.DashboardId)
-		alertDTOs = append(alertDTOs, &dtos.AlertRule{
+		alertDTO := &dtos.AlertRule{
 			Id:             alert.Id,
 			DashboardId:    alert.DashboardId,
 			PanelId:        alert.PanelId,
@@ -76,7 +102,16 @@ func GetAlerts(c *middleware.Context) Response {
 			NewStateDate:   alert.NewStateDate,
 			ExecutionError: alert.ExecutionError,
 			EvalData:       alert.EvalData,
-		})
+			Acknowledged:   alert.Acknowledged,
+		}
+
+		if alert.Acknowledged {
+			alertDTO.AckLogin = alert.AckLogin
+			alertDTO.AckComment = alert.AckComment
+			alertDTO.AckDate = &alert.AckDate
+		}
+
+		alertDTOs = append(alertDTOs, alertDTO)
 	}
 
 	dashboardsQuery := models.GetDashboardsQuery{
@@ -89,16 +124,37 @@ func GetAlerts(c *middleware.Context) Response {
 		}
 	}
 
-	//TODO: should be possible to speed this up with lookup table
+	// only list alerts of dashboards the user can view
+	dashboards := make(map[int64]int64)
+	for _, id := range dashboardIds {
+		dashboards[id] = 0
+	}
+	for _, dash := range dashboardsQuery.Result {
+		dashboards[dash.Id] = dash.FolderId
+	}
+
+	viewable, err := guardian.FilterDashboards(c.SignedInUser, models.PERMISSION_VIEW, dashboards)
+	if err != nil {
+		return ApiError(500, "List alerts failed", err)
+	}
+
+	result := make([]*dtos.AlertRule, 0)
 	for _, alert := range alertDTOs {
+		if !viewable[alert.DashboardId] {
+			continue
+		}
+
+		//TODO: should be possible to speed this up with lookup table
 		for _, dash := range dashboardsQuery.Result {
 			if alert.DashboardId == dash.Id {
 				alert.DashbboardUri = "db/" + dash.Slug
 			}
 		}
+
+		result = append(result, alert)
 	}
 
-	return Json(200, alertDTOs)
+	return Json(200, result)
 }
 
 // POST /api/alerts/test
@@ -146,6 +202,10 @@ func AlertTest(c *middleware.Context, dto dtos.AlertTestCommand) Response {
 // GET /api/alerts/:id
 func GetAlert(c *middleware.Context) Response {
 	id := c.ParamsInt64(":alertId")
+	if rsp := checkAlertPermission(c, id, models.PERMISSION_VIEW); rsp != nil {
+		return rsp
+	}
+
 	query := models.GetAlertByIdQuery{Id: id}
 
 	if err := bus.Dispatch(&query); err != nil {
@@ -155,6 +215,49 @@ func GetAlert(c *middleware.Context) Response {
 	return Json(200, &query.Result)
 }
 
+// POST /api/alerts
+func CreateAlert(c *middleware.Context, dto dtos.SaveAlertCommand) Response {
+	alert, err := alerting.NewStandaloneAlert(c.OrgId, dto.Alert)
+	if err != nil {
+		if validationErr, ok := err.(alerting.ValidationError); ok {
+			return ApiError(400, validationErr.Error(), nil)
+		}
+		return ApiError(500, "Failed to create alert", err)
+	}
+
+	cmd := models.CreateAlertCommand{Alert: alert}
+	if err := bus.Dispatch(&cmd); err != nil {
+		return ApiError(500, "Failed to create alert", err)
+	}
+
+	return Json(200, cmd.Result)
+}
+
+// PUT /api/alerts/:id
+func UpdateAlert(c *middleware.Context, dto dtos.SaveAlertCommand) Response {
+	alert, err := alerting.NewStandaloneAlert(c.OrgId, dto.Alert)
+	if err != nil {
+		if validationErr, ok := err.(alerting.ValidationError); ok {
+			return ApiError(400, validationErr.Error(), nil)
+		}
+		return ApiError(500, "Failed to update alert", err)
+	}
+
+	alert.Id = c.ParamsInt64(":alertId")
+	cmd := models.UpdateAlertCommand{Alert: alert}
+	if err := bus.Dispatch(&cmd); err != nil {
+		if err == models.ErrAlertNotFound {
+			return ApiError(404, "Alert not found", err)
+		}
+		if err == models.ErrAlertManagedByDashboard {
+			return ApiError(400, err.Error(), err)
+		}
+		return ApiError(500, "Failed to update alert", err)
+	}
+
+	return Json(200, cmd.Result)
+}
+
 // DEL /api/alerts/:id
 func DelAlert(c *middleware.Context) Response {
 	alertId := c.ParamsInt64(":alertId")
@@ -163,6 +266,15 @@ func DelAlert(c *middleware.Context) Response {
 		return ApiError(401, "Failed to parse alertid", nil)
 	}
 
+	query := models.GetAlertByIdQuery{Id: alertId}
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(404, "Alert not found", err)
+	}
+
+	if !query.Result.IsStandalone() {
+		return ApiError(400, models.ErrAlertManagedByDashboard.Error(), nil)
+	}
+
 	cmd := models.DeleteAlertCommand{AlertId: alertId}
 
 	if err := bus.Dispatch(&cmd); err != nil {
@@ -173,6 +285,73 @@ func DelAlert(c *middleware.Context) Response {
 	return Json(200, resp)
 }
 
+// GET /api/alerts/export
+func ExportAlertRules(c *middleware.Context) Response {
+	query := alerting.ExportAlertRulesQuery{
+		OrgId: c.OrgId,
+		Tags:  parseTagFilters(c.QueryStrings("tag")),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(500, "Failed to export alert rules", err)
+	}
+
+	return Json(200, query.Result)
+}
+
+// POST /api/alerts/import
+func ImportAlertRules(c *middleware.Context, cmd alerting.ImportAlertRulesCommand) Response {
+	cmd.OrgId = c.OrgId
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		switch err.(type) {
+		case alerting.ValidationError:
+			return ApiError(400, err.Error(), nil)
+		case alerting.ImportConflict:
+			return ApiError(409, err.Error(), nil)
+		}
+		return ApiError(500, "Failed to import alert rules", err)
+	}
+
+	return Json(200, cmd.Result)
+}
+
+// POST /api/alerts/bulk
+func BulkAlerts(c *middleware.Context, dto dtos.BulkAlertsCommand) Response {
+	cmd := alerting.BulkAlertsCommand{
+		OrgId:        c.OrgId,
+		SignedInUser: c.SignedInUser,
+		Action:       dto.Action,
+		DashboardId:  dto.DashboardId,
+		FolderId:     dto.FolderId,
+		Tags:         dto.Tags,
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		if validationErr, ok := err.(alerting.ValidationError); ok {
+			return ApiError(400, validationErr.Error(), nil)
+		}
+		return ApiError(500, "Failed to update alerts", err)
+	}
+
+	return Json(200, cmd.Result)
+}
+
+// parseTagFilters reads tag filters given as key:value or key.
+func parseTagFilters(values []string) map[string]string {
+	tags := make(map[string]string)
+	for _, value := range values {
+		parts := strings.SplitN(value, ":", 2)
+		if len(parts) == 2 {
+			tags[parts[0]] = parts[1]
+		} else {
+			tags[parts[0]] = ""
+		}
+	}
+
+	return tags
+}
+
 func GetAlertNotifiers(c *middleware.Context) Response {
 	return Json(200, alerting.GetNotifiers())
 }
@@ -216,6 +395,10 @@ func GetAlertNotificationById(c *middleware.Context) Response {
 func CreateAlertNotification(c *middleware.Context, cmd models.CreateAlertNotificationCommand) Response {
 	cmd.OrgId = c.OrgId
 
+	if err := alerting.ValidateNotificationSettings(cmd.Settings); err != nil {
+		return ApiError(400, err.Error(), err)
+	}
+
 	if err := bus.Dispatch(&cmd); err != nil {
 		return ApiError(500, "Failed to create alert notification", err)
 	}
@@ -226,6 +409,10 @@ func CreateAlertNotification(c *middleware.Context, cmd models.CreateAlertNotifi
 func UpdateAlertNotification(c *middleware.Context, cmd models.UpdateAlertNotificationCommand) Response {
 	cmd.OrgId = c.OrgId
 
+	if err := alerting.ValidateNotificationSettings(cmd.Settings); err != nil {
+		return ApiError(400, err.Error(), err)
+	}
+
 	if err := bus.Dispatch(&cmd); err != nil {
 		return ApiError(500, "Failed to update alert notification", err)
 	}
@@ -246,27 +433,55 @@ func DeleteAlertNotification(c *middleware.Context) Response {
 	return ApiSuccess("Notification deleted")
 }
 
+//GET /api/alert-notifications/:notificationId/deliveries
+func GetAlertNotificationDeliveries(c *middleware.Context) Response {
+	query := &models.GetAlertNotificationDeliveriesQuery{
+		OrgId:      c.OrgId,
+		NotifierId: c.ParamsInt64("notificationId"),
+		AlertId:    c.QueryInt64("alertId"),
+		Limit:      c.QueryInt("limit"),
+	}
+
+	if err := bus.Dispatch(query); err != nil {
+		return ApiError(500, "Failed to get alert notification deliveries", err)
+	}
+
+	return Json(200, query.Result)
+}
+
 //POST /api/alert-notifications/test
 func NotificationTest(c *middleware.Context, dto dtos.NotificationTestCommand) Response {
 	cmd := &alerting.NotificationTestCommand{
 		Name:     dto.Name,
 		Type:     dto.Type,
 		Settings: dto.Settings,
+		Preview:  dto.Preview,
 	}
 
 	if err := bus.Dispatch(cmd); err != nil {
 		if err == models.ErrSmtpNotEnabled {
 			return ApiError(412, err.Error(), err)
 		}
+		if validationErr, ok := err.(alerting.ValidationError); ok {
+			return ApiError(400, validationErr.Error(), err)
+		}
 		return ApiError(500, "Failed to send alert notifications", err)
 	}
 
+	if cmd.Preview {
+		return Json(200, cmd.Result)
+	}
+
 	return ApiSuccess("Test notification sent")
 }
 
 //POST /api/alerts/:alertId/pause
 func PauseAlert(c *middleware.Context, dto dtos.PauseAlertCommand) Response {
 	alertId := c.ParamsInt64("alertId")
+	if rsp := checkAlertPermission(c, alertId, models.PERMISSION_EDIT); rsp != nil {
+		return rsp
+	}
+
 	cmd := models.PauseAlertCommand{
 		OrgId:    c.OrgId,
 		AlertIds: []int64{alertId},
@@ -293,6 +508,56 @@ func PauseAlert(c *middleware.Context, dto dtos.PauseAlertCommand) Response {
 	return Json(200, result)
 }
 
+//POST /api/alerts/:alertId/ack
+func AcknowledgeAlert(c *middleware.Context, dto dtos.AcknowledgeAlertCommand) Response {
+	return setAlertAcknowledgement(c, true, dto.Comment)
+}
+
+//DELETE /api/alerts/:alertId/ack
+func UnacknowledgeAlert(c *middleware.Context) Response {
+	return setAlertAcknowledgement(c, false, "")
+}
+
+func setAlertAcknowledgement(c *middleware.Context, acknowledged bool, comment string) Response {
+	alertId := c.ParamsInt64("alertId")
+	if rsp := checkAlertPermission(c, alertId, models.PERMISSION_EDIT); rsp != nil {
+		return rsp
+	}
+
+	cmd := alerting.AcknowledgeAlertCommand{
+		OrgId:        c.OrgId,
+		AlertId:      alertId,
+		UserId:       c.UserId,
+		Login:        c.Login,
+		Comment:      comment,
+		Acknowledged: acknowledged,
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		if err == models.ErrAlertNotFound {
+			return ApiError(404, "Alert not found", err)
+		}
+		if err == models.ErrCannotAcknowledgeAlert {
+			return ApiError(400, err.Error(), err)
+		}
+		return ApiError(500, "Failed to acknowledge alert", err)
+	}
+
+	message := "alert acknowledged"
+	if !acknowledged {
+		message = "alert acknowledgement removed"
+	}
+
+	result := map[string]interface{}{
+		"alertId":      alertId,
+		"state":        cmd.Result.State,
+		"acknowledged": acknowledged,
+		"message":      message,
+	}
+
+	return Json(200, result)
+}
+
 //POST /api/admin/pause-all-alerts
 func PauseAllAlerts(c *middleware.Context, dto dtos.PauseAllAlertsCommand) Response {
 	updateCmd := models.PauseAllAlertCommand{
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/middleware"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
 )
 
 // Register adds http routes
@@ -157,6 +158,21 @@ func (hs *HttpServer) registerRoutes() {
 			r.Put("/preferences", bind(dtos.UpdatePrefsCmd{}), wrap(UpdateOrgPreferences))
 		}, reqOrgAdmin)
 
+		// teams, listing is available to all users to pick teams in dashboard permissions
+		r.Group("/teams", func() {
+			r.Get("/search", wrap(SearchTeams))
+			r.Get("/:teamId", wrap(GetTeamById))
+		})
+
+		r.Group("/teams", func() {
+			r.Post("/", bind(m.CreateTeamCommand{}), wrap(CreateTeam))
+			r.Put("/:teamId", bind(m.UpdateTeamCommand{}), wrap(UpdateTeam))
+			r.Delete("/:teamId", wrap(DeleteTeamById))
+			r.Get("/:teamId/members", wrap(GetTeamMembers))
+			r.Post("/:teamId/members", bind(m.AddTeamMemberCommand{}), wrap(AddTeamMember))
+			r.Delete("/:teamId/members/:userId", wrap(RemoveTeamMember))
+		}, reqOrgAdmin)
+
 		// create new org
 		r.Post("/orgs", quota("org"), bind(m.CreateOrgCommand{}), wrap(CreateOrg))
 
@@ -223,11 +239,32 @@ func (hs *HttpServer) registerRoutes() {
 		// Dashboard
 		r.Group("/dashboards", func() {
 			r.Combo("/db/:slug").Get(GetDashboard).Delete(DeleteDashboard)
-			r.Post("/db", reqEditorRole, bind(m.SaveDashboardCommand{}), wrap(PostDashboard))
+			r.Get("/db/:slug/export", wrap(GetDashboardExport))
+			r.Combo("/uid/:uid").Get(GetDashboard).Delete(DeleteDashboard)
+			r.Post("/db", bind(m.SaveDashboardCommand{}), wrap(PostDashboard))
 			r.Get("/file/:file", GetDashboardFromJsonFile)
 			r.Get("/home", wrap(GetHomeDashboard))
 			r.Get("/tags", GetDashboardTags)
 			r.Post("/import", bind(dtos.ImportDashboardCommand{}), wrap(ImportDashboard))
+
+			r.Group("/id/:dashboardId", func() {
+				r.Get("/versions", wrap(GetDashboardVersions))
+				r.Get("/versions/:id", wrap(GetDashboardVersion))
+				r.Get("/compare", wrap(CompareDashboardVersions))
+				r.Post("/restore", bind(dtos.RestoreDashboardVersionCommand{}), wrap(RestoreDashboardVersion))
+				r.Post("/move", bind(m.MoveDashboardCommand{}), wrap(MoveDashboard))
+				r.Get("/acl", wrap(GetDashboardAclList))
+				r.Post("/acl", bind(dtos.UpdateDashboardAclCommand{}), wrap(UpdateDashboardAcl))
+			})
+		})
+
+		// Folders
+		r.Group("/folders", func() {
+			r.Get("/", wrap(GetFolders))
+			r.Post("/", reqEditorRole, bind(dtos.CreateFolderCommand{}), wrap(CreateFolder))
+			r.Get("/:id", wrap(GetFolder))
+			r.Put("/:id", bind(dtos.UpdateFolderCommand{}), wrap(UpdateFolder))
+			r.Delete("/:id", wrap(DeleteFolder))
 		})
 
 		// Dashboard snapshots
@@ -260,8 +297,16 @@ func (hs *HttpServer) registerRoutes() {
 		r.Group("/alerts", func() {
 			r.Post("/test", bind(dtos.AlertTestCommand{}), wrap(AlertTest))
 			r.Post("/:alertId/pause", bind(dtos.PauseAlertCommand{}), wrap(PauseAlert), reqEditorRole)
+			r.Post("/:alertId/ack", reqEditorRole, bind(dtos.AcknowledgeAlertCommand{}), wrap(AcknowledgeAlert))
+			r.Delete("/:alertId/ack", reqEditorRole, wrap(UnacknowledgeAlert))
 			r.Get("/:alertId", ValidateOrgAlert, wrap(GetAlert))
+			r.Put("/:alertId", reqEditorRole, bind(dtos.SaveAlertCommand{}), wrap(UpdateAlert))
+			r.Delete("/:alertId", reqEditorRole, ValidateOrgAlert, wrap(DelAlert))
 			r.Get("/", wrap(GetAlerts))
+			r.Post("/", reqEditorRole, bind(dtos.SaveAlertCommand{}), wrap(CreateAlert))
+			r.Get("/export", reqEditorRole, wrap(ExportAlertRules))
+			r.Post("/import", reqEditorRole, bind(alerting.ImportAlertRulesCommand{}), wrap(ImportAlertRules))
+			r.Post("/bulk", reqEditorRole, bind(dtos.BulkAlertsCommand{}), wrap(BulkAlerts))
 			r.Get("/states-for-dashboard", wrap(GetAlertStatesForDashboard))
 		})
 
@@ -273,6 +318,7 @@ func (hs *HttpServer) registerRoutes() {
 			r.Post("/", bind(m.CreateAlertNotificationCommand{}), wrap(CreateAlertNotification))
 			r.Put("/:notificationId", bind(m.UpdateAlertNotificationCommand{}), wrap(UpdateAlertNotification))
 			r.Get("/:notificationId", wrap(GetAlertNotificationById))
+			r.Get("/:notificationId/deliveries", wrap(GetAlertNotificationDeliveries))
 			r.Delete("/:notificationId", wrap(DeleteAlertNotification))
 		}, reqEditorRole)
 
@@ -306,6 +352,9 @@ func (hs *HttpServer) registerRoutes() {
 	// grafana.net proxy
 	r.Any("/api/gnet/*", reqSignedIn, ProxyGnetRequest)
 
+	// internal metrics in the Prometheus format
+	r.Get("/metrics", GetPrometheusMetrics)
+
 	// Gravatar service.
 	avt := avatar.CacheServer()
 	r.Get("/avatar/:hash", avt.ServeHTTP)
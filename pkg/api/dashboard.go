@@ -2,19 +2,22 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/log"
 	"github.com/grafana/grafana/pkg/metrics"
 	"github.com/grafana/grafana/pkg/middleware"
 	m "github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
-	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -33,16 +36,41 @@ func isDashboardStarredByUser(c *middleware.Context, dashId int64) (bool, error)
 	return query.Result, nil
 }
 
+// GetDashboard returns the dashboard with the slug or, for the uid route,
+// the dashboard with the uid.
 func GetDashboard(c *middleware.Context) {
 	slug := strings.ToLower(c.Params(":slug"))
 
-	query := m.GetDashboardQuery{Slug: slug, OrgId: c.OrgId}
+	query := m.GetDashboardQuery{Slug: slug, Uid: c.Params(":uid"), OrgId: c.OrgId}
 	err := bus.Dispatch(&query)
 	if err != nil {
 		c.JsonApiErr(404, "Dashboard not found", nil)
 		return
 	}
 
+	guardian := guardian.New(query.Result.Id, c.OrgId, c.SignedInUser)
+	if canView, err := guardian.CanView(); err != nil {
+		c.JsonApiErr(500, "Error while checking dashboard permissions", err)
+		return
+	} else if !canView {
+		c.JsonApiErr(403, "Access denied to this dashboard", nil)
+		return
+	}
+
+	canSave, _ := guardian.CanSave()
+	canEdit, _ := guardian.CanEdit()
+	canAdmin, _ := guardian.CanAdmin()
+
+	provisioning, err := getProvisionedData(query.Result.Id)
+	if err != nil {
+		c.JsonApiErr(500, "Error while checking if dashboard is provisioned", err)
+		return
+	}
+
+	if provisioning != nil && !provisioning.AllowUiUpdates {
+		canSave = false
+	}
+
 	isStarred, err := isDashboardStarredByUser(c, query.Result.Id)
 	if err != nil {
 		c.JsonApiErr(500, "Error while checking if dashboard was starred by user", err)
@@ -60,27 +88,68 @@ func GetDashboard(c *middleware.Context) {
 		creator = getUserLogin(dash.CreatedBy)
 	}
 
+	folderTitle := ""
+	if dash.FolderId > 0 {
+		folderQuery := m.GetDashboardQuery{Id: dash.FolderId, OrgId: c.OrgId}
+		if err := bus.Dispatch(&folderQuery); err == nil {
+			folderTitle = folderQuery.Result.Title
+		}
+	}
+
 	dto := dtos.DashboardFullWithMeta{
 		Dashboard: dash.Data,
 		Meta: dtos.DashboardMeta{
-			IsStarred: isStarred,
-			Slug:      slug,
-			Type:      m.DashTypeDB,
-			CanStar:   c.IsSignedIn,
-			CanSave:   c.OrgRole == m.ROLE_ADMIN || c.OrgRole == m.ROLE_EDITOR,
-			CanEdit:   canEditDashboard(c.OrgRole),
-			Created:   dash.Created,
-			Updated:   dash.Updated,
-			UpdatedBy: updater,
-			CreatedBy: creator,
-			Version:   dash.Version,
+			IsStarred:   isStarred,
+			Slug:        dash.Slug,
+			Type:        m.DashTypeDB,
+			CanStar:     c.IsSignedIn,
+			CanSave:     canSave,
+			CanEdit:     canEdit,
+			CanAdmin:    canAdmin,
+			Created:     dash.Created,
+			Updated:     dash.Updated,
+			UpdatedBy:   updater,
+			CreatedBy:   creator,
+			Version:     dash.Version,
+			IsFolder:    dash.IsFolder,
+			FolderId:    dash.FolderId,
+			FolderTitle: folderTitle,
+			Provisioned: provisioning != nil,
 		},
 	}
 
+	// count the view, search results can be sorted by views
+	if !dash.IsFolder {
+		viewCmd := m.IncrementDashboardViewsCommand{DashboardId: dash.Id, OrgId: c.OrgId}
+		if err := bus.Dispatch(&viewCmd); err != nil {
+			log.Warn("Failed to count dashboard view, %s", err.Error())
+		}
+	}
+
 	c.TimeRequest(metrics.M_Api_Dashboard_Get)
 	c.JSON(200, dto)
 }
 
+// GetDashboardExport returns the dashboard with its datasources replaced by
+// inputs, so it can be imported into other Grafana instances.
+func GetDashboardExport(c *middleware.Context) Response {
+	query := m.GetDashboardQuery{Slug: strings.ToLower(c.Params(":slug")), OrgId: c.OrgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(404, "Dashboard not found", nil)
+	}
+
+	if rsp := checkDashboardPermission(c, query.Result.Id, m.PERMISSION_VIEW); rsp != nil {
+		return rsp
+	}
+
+	cmd := plugins.ExportDashboardCommand{Dashboard: query.Result.Data, OrgId: c.OrgId}
+	if err := bus.Dispatch(&cmd); err != nil {
+		return ApiError(500, "Failed to export dashboard", err)
+	}
+
+	return Json(200, cmd.Result)
+}
+
 func getUserLogin(userId int64) string {
 	query := m.GetUserByIdQuery{Id: userId}
 	err := bus.Dispatch(&query)
@@ -93,15 +162,30 @@ func getUserLogin(userId int64) string {
 }
 
 func DeleteDashboard(c *middleware.Context) {
-	slug := c.Params(":slug")
-
-	query := m.GetDashboardQuery{Slug: slug, OrgId: c.OrgId}
+	query := m.GetDashboardQuery{Slug: c.Params(":slug"), Uid: c.Params(":uid"), OrgId: c.OrgId}
 	if err := bus.Dispatch(&query); err != nil {
 		c.JsonApiErr(404, "Dashboard not found", nil)
 		return
 	}
 
-	cmd := m.DeleteDashboardCommand{Slug: slug, OrgId: c.OrgId}
+	guardian := guardian.New(query.Result.Id, c.OrgId, c.SignedInUser)
+	if canSave, err := guardian.CanSave(); err != nil {
+		c.JsonApiErr(500, "Error while checking dashboard permissions", err)
+		return
+	} else if !canSave {
+		c.JsonApiErr(403, "Access denied to this dashboard", nil)
+		return
+	}
+
+	if readOnly, err := isProvisionedReadOnly(query.Result.Id); err != nil {
+		c.JsonApiErr(500, "Error while checking if dashboard is provisioned", err)
+		return
+	} else if readOnly {
+		c.JsonApiErr(400, m.ErrDashboardProvisioned.Error(), nil)
+		return
+	}
+
+	cmd := m.DeleteDashboardCommand{Slug: query.Result.Slug, OrgId: c.OrgId}
 	if err := bus.Dispatch(&cmd); err != nil {
 		c.JsonApiErr(500, "Failed to delete dashboard", err)
 		return
@@ -126,6 +210,12 @@ func PostDashboard(c *middleware.Context, cmd m.SaveDashboardCommand) Response {
 	if dash.Title == "" {
 		return ApiError(400, m.ErrDashboardTitleEmpty.Error(), nil)
 	}
+
+	existing := getExistingDashboard(c.OrgId, dash, cmd.Overwrite)
+	if rsp := checkCanSaveDashboard(c, existing, dash.FolderId); rsp != nil {
+		return rsp
+	}
+
 	if dash.Id == 0 {
 		limitReached, err := middleware.QuotaReached(c, "dashboard")
 		if err != nil {
@@ -136,24 +226,23 @@ func PostDashboard(c *middleware.Context, cmd m.SaveDashboardCommand) Response {
 		}
 	}
 
-	validateAlertsCmd := alerting.ValidateDashboardAlertsCommand{
-		OrgId:     c.OrgId,
-		UserId:    c.UserId,
-		Dashboard: dash,
-	}
-
-	if err := bus.Dispatch(&validateAlertsCmd); err != nil {
-		return ApiError(500, "Invalid alert data. Cannot save dashboard", err)
-	}
-
-	err := bus.Dispatch(&cmd)
+	err := dashboards.SaveDashboard(&cmd)
 	if err != nil {
+		if alertsErr, ok := err.(dashboards.AlertsError); ok {
+			return ApiError(500, alertsErr.Reason, alertsErr.Err)
+		}
 		if err == m.ErrDashboardWithSameNameExists {
 			return Json(412, util.DynMap{"status": "name-exists", "message": err.Error()})
 		}
 		if err == m.ErrDashboardVersionMismatch {
 			return Json(412, util.DynMap{"status": "version-mismatch", "message": err.Error()})
 		}
+		if err == m.ErrDashboardWithSameUIDExists {
+			return Json(412, util.DynMap{"status": "uid-exists", "message": err.Error()})
+		}
+		if err == m.ErrDashboardUidInOtherOrg || err == m.ErrDashboardInvalidUid || err == m.ErrDashboardUidToLong {
+			return ApiError(400, err.Error(), nil)
+		}
 		if pluginErr, ok := err.(m.UpdatePluginDashboardError); ok {
 			message := "The dashboard belongs to plugin " + pluginErr.PluginId + "."
 			// look up plugin name
@@ -165,21 +254,109 @@ func PostDashboard(c *middleware.Context, cmd m.SaveDashboardCommand) Response {
 		if err == m.ErrDashboardNotFound {
 			return Json(404, util.DynMap{"status": "not-found", "message": err.Error()})
 		}
+		if err == m.ErrFolderNotFound || err == m.ErrDashboardFolderNesting || err == m.ErrDashboardTypeMismatch {
+			return ApiError(400, err.Error(), nil)
+		}
 		return ApiError(500, "Failed to save dashboard", err)
 	}
 
-	alertCmd := alerting.UpdateDashboardAlertsCommand{
-		OrgId:     c.OrgId,
-		UserId:    c.UserId,
-		Dashboard: cmd.Result,
+	c.TimeRequest(metrics.M_Api_Dashboard_Save)
+	return Json(200, util.DynMap{"status": "success", "slug": cmd.Result.Slug, "uid": cmd.Result.Uid, "version": cmd.Result.Version})
+}
+
+// getExistingDashboard returns the dashboard that will be overwritten by
+// saving dash, or nil when a new dashboard is created.
+func getExistingDashboard(orgId int64, dash *m.Dashboard, overwrite bool) *m.Dashboard {
+	if dash.Id > 0 {
+		existingQuery := m.GetDashboardQuery{Id: dash.Id, OrgId: orgId}
+		if err := bus.Dispatch(&existingQuery); err == nil {
+			return existingQuery.Result
+		}
+		return nil
 	}
 
-	if err := bus.Dispatch(&alertCmd); err != nil {
-		return ApiError(500, "Failed to save alerts", err)
+	if !overwrite {
+		return nil
 	}
 
-	c.TimeRequest(metrics.M_Api_Dashboard_Save)
-	return Json(200, util.DynMap{"status": "success", "slug": cmd.Result.Slug, "version": cmd.Result.Version})
+	// overwrites match the uid first and then the title
+	existingQueries := []m.GetDashboardQuery{{Slug: dash.Slug, OrgId: orgId}}
+	if dash.Uid != "" {
+		existingQueries = append([]m.GetDashboardQuery{{Uid: dash.Uid, OrgId: orgId}}, existingQueries...)
+	}
+
+	for _, existingQuery := range existingQueries {
+		if err := bus.Dispatch(&existingQuery); err == nil {
+			return existingQuery.Result
+		}
+	}
+
+	return nil
+}
+
+// checkCanSaveDashboard returns an error response when the user cannot
+// save the dashboard, either because of its permissions or because it is
+// provisioned.
+func checkCanSaveDashboard(c *middleware.Context, existing *m.Dashboard, folderId int64) Response {
+	if canSave, err := canSaveDashboard(c, existing, folderId); err != nil {
+		return ApiError(500, "Error while checking dashboard permissions", err)
+	} else if !canSave {
+		return ApiError(403, "Access denied to this dashboard", nil)
+	}
+
+	if existing != nil {
+		if readOnly, err := isProvisionedReadOnly(existing.Id); err != nil {
+			return ApiError(500, "Error while checking if dashboard is provisioned", err)
+		} else if readOnly {
+			return ApiError(400, m.ErrDashboardProvisioned.Error(), nil)
+		}
+	}
+
+	return nil
+}
+
+// canSaveDashboard checks that the user can save the existing dashboard into
+// the folder. New dashboards and dashboards moved to another folder need
+// edit permission on the target folder, or the editor role for the root.
+func canSaveDashboard(c *middleware.Context, existing *m.Dashboard, folderId int64) (bool, error) {
+	if existing != nil {
+		canSave, err := guardian.New(existing.Id, c.OrgId, c.SignedInUser).CanSave()
+		if err != nil || !canSave {
+			return false, err
+		}
+
+		if existing.FolderId == folderId {
+			return true, nil
+		}
+	}
+
+	if folderId == 0 {
+		return c.OrgRole == m.ROLE_EDITOR || c.OrgRole == m.ROLE_ADMIN, nil
+	}
+
+	return guardian.New(folderId, c.OrgId, c.SignedInUser).CanSave()
+}
+
+// getProvisionedData returns where the dashboard was provisioned from, or
+// nil when it was not provisioned.
+func getProvisionedData(dashboardId int64) (*m.DashboardProvisioning, error) {
+	query := m.GetProvisionedDashboardDataByIdQuery{DashboardId: dashboardId}
+	if err := bus.Dispatch(&query); err != nil {
+		return nil, err
+	}
+
+	return query.Result, nil
+}
+
+// isProvisionedReadOnly returns true for dashboards provisioned by a
+// provider that does not allow changes from the UI.
+func isProvisionedReadOnly(dashboardId int64) (bool, error) {
+	provisioning, err := getProvisionedData(dashboardId)
+	if err != nil {
+		return false, err
+	}
+
+	return provisioning != nil && !provisioning.AllowUiUpdates, nil
 }
 
 func canEditDashboard(role m.RoleType) bool {
@@ -265,3 +442,168 @@ func GetDashboardTags(c *middleware.Context) {
 
 	c.JSON(200, query.Result)
 }
+
+// GetDashboardVersions returns all dashboard versions as JSON
+func GetDashboardVersions(c *middleware.Context) Response {
+	if rsp := checkDashboardPermission(c, c.ParamsInt64(":dashboardId"), m.PERMISSION_VIEW); rsp != nil {
+		return rsp
+	}
+
+	query := m.GetDashboardVersionsQuery{
+		OrgId:       c.OrgId,
+		DashboardId: c.ParamsInt64(":dashboardId"),
+		Limit:       c.QueryInt("limit"),
+		Start:       c.QueryInt("start"),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(404, fmt.Sprintf("No versions found for dashboardId %d", query.DashboardId), err)
+	}
+
+	return Json(200, query.Result)
+}
+
+// GetDashboardVersion returns the dashboard version with the given ID.
+func GetDashboardVersion(c *middleware.Context) Response {
+	if rsp := checkDashboardPermission(c, c.ParamsInt64(":dashboardId"), m.PERMISSION_VIEW); rsp != nil {
+		return rsp
+	}
+
+	query := m.GetDashboardVersionQuery{
+		OrgId:       c.OrgId,
+		DashboardId: c.ParamsInt64(":dashboardId"),
+		Version:     c.ParamsInt(":id"),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(404, fmt.Sprintf("Dashboard version %d not found for dashboardId %d", query.Version, query.DashboardId), err)
+	}
+
+	version := query.Result
+	creator := "Anonymous"
+	if version.CreatedBy > 0 {
+		creator = getUserLogin(version.CreatedBy)
+	}
+
+	return Json(200, &dtos.DashboardVersionMeta{
+		DashboardId:   version.DashboardId,
+		ParentVersion: version.ParentVersion,
+		RestoredFrom:  version.RestoredFrom,
+		Version:       version.Version,
+		Created:       version.Created,
+		CreatedBy:     creator,
+		Message:       version.Message,
+		Data:          version.Data,
+	})
+}
+
+// CompareDashboardVersions compares two versions of a dashboard. The
+// diffType query parameter selects a basic summary or the full json delta.
+func CompareDashboardVersions(c *middleware.Context) Response {
+	dashboardId := c.ParamsInt64(":dashboardId")
+	if rsp := checkDashboardPermission(c, dashboardId, m.PERMISSION_VIEW); rsp != nil {
+		return rsp
+	}
+
+	baseQuery := m.GetDashboardVersionQuery{OrgId: c.OrgId, DashboardId: dashboardId, Version: c.QueryInt("base")}
+	if err := bus.Dispatch(&baseQuery); err != nil {
+		return ApiError(404, fmt.Sprintf("Dashboard version %d not found for dashboardId %d", baseQuery.Version, dashboardId), err)
+	}
+
+	newQuery := m.GetDashboardVersionQuery{OrgId: c.OrgId, DashboardId: dashboardId, Version: c.QueryInt("new")}
+	if err := bus.Dispatch(&newQuery); err != nil {
+		return ApiError(404, fmt.Sprintf("Dashboard version %d not found for dashboardId %d", newQuery.Version, dashboardId), err)
+	}
+
+	diffType := dashdiffs.ParseDiffType(c.Query("diffType"))
+	result := dashdiffs.CalculateDiff(baseQuery.Result.Data, newQuery.Result.Data, diffType)
+
+	return Json(200, util.DynMap{
+		"base":    baseQuery.Version,
+		"new":     newQuery.Version,
+		"summary": result.Summary,
+		"delta":   result.Delta,
+	})
+}
+
+// RestoreDashboardVersion saves the dashboard of an earlier version as a
+// new version.
+func RestoreDashboardVersion(c *middleware.Context, apiCmd dtos.RestoreDashboardVersionCommand) Response {
+	dashboardId := c.ParamsInt64(":dashboardId")
+
+	dashQuery := m.GetDashboardQuery{Id: dashboardId, OrgId: c.OrgId}
+	if err := bus.Dispatch(&dashQuery); err != nil {
+		return ApiError(404, "Dashboard not found", nil)
+	}
+
+	versionQuery := m.GetDashboardVersionQuery{DashboardId: dashboardId, Version: apiCmd.Version, OrgId: c.OrgId}
+	if err := bus.Dispatch(&versionQuery); err != nil {
+		return ApiError(404, "Dashboard version not found", nil)
+	}
+
+	version := versionQuery.Result
+
+	saveCmd := m.SaveDashboardCommand{}
+	saveCmd.RestoredFrom = version.Version
+	saveCmd.FolderId = dashQuery.Result.FolderId
+	saveCmd.Dashboard = version.Data
+	saveCmd.Dashboard.Set("id", dashboardId)
+	saveCmd.Dashboard.Set("uid", dashQuery.Result.Uid)
+	saveCmd.Dashboard.Set("version", dashQuery.Result.Version)
+	saveCmd.Message = fmt.Sprintf("Restored from version %d", version.Version)
+
+	return PostDashboard(c, saveCmd)
+}
+
+// MoveDashboard moves a dashboard into a folder, or out of its folder when
+// the folder id is 0.
+func MoveDashboard(c *middleware.Context, cmd m.MoveDashboardCommand) Response {
+	cmd.OrgId = c.OrgId
+	cmd.DashboardId = c.ParamsInt64(":dashboardId")
+
+	dashQuery := m.GetDashboardQuery{Id: cmd.DashboardId, OrgId: c.OrgId}
+	if err := bus.Dispatch(&dashQuery); err != nil {
+		return ApiError(404, "Dashboard not found", nil)
+	}
+
+	if canSave, err := canSaveDashboard(c, dashQuery.Result, cmd.FolderId); err != nil {
+		return ApiError(500, "Error while checking dashboard permissions", err)
+	} else if !canSave {
+		return ApiError(403, "Access denied to this dashboard", nil)
+	}
+
+	if readOnly, err := isProvisionedReadOnly(cmd.DashboardId); err != nil {
+		return ApiError(500, "Error while checking if dashboard is provisioned", err)
+	} else if readOnly {
+		return ApiError(400, m.ErrDashboardProvisioned.Error(), nil)
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		switch err {
+		case m.ErrDashboardNotFound:
+			return ApiError(404, "Dashboard not found", nil)
+		case m.ErrFolderNotFound, m.ErrDashboardFolderNesting:
+			return ApiError(400, err.Error(), nil)
+		}
+		return ApiError(500, "Failed to move dashboard", err)
+	}
+
+	return ApiSuccess("Dashboard moved")
+}
+
+// checkDashboardPermission returns an error response when the signed in user
+// lacks the permission on the dashboard.
+func checkDashboardPermission(c *middleware.Context, dashboardId int64, permission m.PermissionType) Response {
+	hasPermission, err := guardian.New(dashboardId, c.OrgId, c.SignedInUser).HasPermission(permission)
+	if err == m.ErrDashboardNotFound {
+		return ApiError(404, "Dashboard not found", nil)
+	}
+	if err != nil {
+		return ApiError(500, "Error while checking dashboard permissions", err)
+	}
+	if !hasPermission {
+		return ApiError(403, "Access denied to this dashboard", nil)
+	}
+
+	return nil
+}
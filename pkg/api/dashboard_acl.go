@@ -0,0 +1,67 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/middleware"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
+)
+
+func GetDashboardAclList(c *middleware.Context) Response {
+	dashboardId := c.ParamsInt64(":dashboardId")
+
+	guardian := guardian.New(dashboardId, c.OrgId, c.SignedInUser)
+	if canAdmin, err := guardian.CanAdmin(); err != nil {
+		return dashboardAclError(err)
+	} else if !canAdmin {
+		return ApiError(403, "Access denied to this dashboard", nil)
+	}
+
+	acl, err := guardian.GetAcl()
+	if err != nil {
+		return dashboardAclError(err)
+	}
+
+	return Json(200, acl)
+}
+
+// UpdateDashboardAcl replaces the ACL of a dashboard or folder. An empty list
+// of items removes all entries so that only org admins have access.
+func UpdateDashboardAcl(c *middleware.Context, apiCmd dtos.UpdateDashboardAclCommand) Response {
+	dashboardId := c.ParamsInt64(":dashboardId")
+
+	guardian := guardian.New(dashboardId, c.OrgId, c.SignedInUser)
+	if canAdmin, err := guardian.CanAdmin(); err != nil {
+		return dashboardAclError(err)
+	} else if !canAdmin {
+		return ApiError(403, "Access denied to this dashboard", nil)
+	}
+
+	cmd := m.UpdateDashboardAclCommand{DashboardId: dashboardId, OrgId: c.OrgId}
+	for _, item := range apiCmd.Items {
+		cmd.Items = append(cmd.Items, &m.DashboardAcl{
+			UserId:     item.UserId,
+			TeamId:     item.TeamId,
+			Role:       item.Role,
+			Permission: item.Permission,
+		})
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		return dashboardAclError(err)
+	}
+
+	return ApiSuccess("Dashboard permissions updated")
+}
+
+func dashboardAclError(err error) Response {
+	switch err {
+	case m.ErrDashboardNotFound:
+		return ApiError(404, "Dashboard not found", nil)
+	case m.ErrDashboardAclInvalid, m.ErrDashboardAclDuplicate, m.ErrOrgUserNotFound, m.ErrTeamNotFound:
+		return ApiError(400, err.Error(), nil)
+	}
+
+	return ApiError(500, "Failed to get or update dashboard permissions", err)
+}
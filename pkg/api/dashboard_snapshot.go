@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana/pkg/metrics"
 	"github.com/grafana/grafana/pkg/middleware"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -25,6 +26,19 @@ func CreateDashboardSnapshot(c *middleware.Context, cmd m.CreateDashboardSnapsho
 		cmd.Name = "Unnamed snapshot"
 	}
 
+	// snapshots of saved dashboards need view permission on the dashboard
+	if dashboardId := cmd.Dashboard.Get("id").MustInt64(); dashboardId > 0 {
+		canView, err := guardian.New(dashboardId, c.OrgId, c.SignedInUser).CanView()
+		if err != nil && err != m.ErrDashboardNotFound {
+			c.JsonApiErr(500, "Error while checking dashboard permissions", err)
+			return
+		}
+		if err == nil && !canView {
+			c.JsonApiErr(403, "Access denied to this dashboard", nil)
+			return
+		}
+	}
+
 	if cmd.External {
 		// external snapshot ref requires key and delete key
 		if cmd.Key == "" || cmd.DeleteKey == "" {
@@ -33,6 +33,7 @@ func GetDataSources(c *middleware.Context) Response {
 			BasicAuth: ds.BasicAuth,
 			IsDefault: ds.IsDefault,
 			JsonData:  ds.JsonData,
+			ReadOnly:  ds.ReadOnly,
 		}
 
 		if plugin, exists := plugins.DataSources[ds.Type]; exists {
@@ -76,9 +77,24 @@ func DeleteDataSourceById(c *middleware.Context) {
 		return
 	}
 
+	ds, err := getRawDataSourceById(id, c.OrgId)
+	if err != nil {
+		if err == m.ErrDataSourceNotFound {
+			c.JsonApiErr(404, "Data source not found", nil)
+			return
+		}
+		c.JsonApiErr(500, "Failed to delete datasource", err)
+		return
+	}
+
+	if ds.ReadOnly {
+		c.JsonApiErr(403, m.ErrDatasourceIsReadOnly.Error(), nil)
+		return
+	}
+
 	cmd := &m.DeleteDataSourceByIdCommand{Id: id, OrgId: c.OrgId}
 
-	err := bus.Dispatch(cmd)
+	err = bus.Dispatch(cmd)
 	if err != nil {
 		c.JsonApiErr(500, "Failed to delete datasource", err)
 		return
@@ -95,6 +111,21 @@ func DeleteDataSourceByName(c *middleware.Context) {
 		return
 	}
 
+	query := m.GetDataSourceByNameQuery{Name: name, OrgId: c.OrgId}
+	if err := bus.Dispatch(&query); err != nil {
+		if err == m.ErrDataSourceNotFound {
+			c.JsonApiErr(404, "Data source not found", nil)
+			return
+		}
+		c.JsonApiErr(500, "Failed to delete datasource", err)
+		return
+	}
+
+	if query.Result.ReadOnly {
+		c.JsonApiErr(403, m.ErrDatasourceIsReadOnly.Error(), nil)
+		return
+	}
+
 	cmd := &m.DeleteDataSourceByNameCommand{Name: name, OrgId: c.OrgId}
 
 	err := bus.Dispatch(cmd)
@@ -126,6 +157,15 @@ func UpdateDataSource(c *middleware.Context, cmd m.UpdateDataSourceCommand) Resp
 	cmd.OrgId = c.OrgId
 	cmd.Id = c.ParamsInt64(":id")
 
+	if ds, err := getRawDataSourceById(cmd.Id, cmd.OrgId); err != nil {
+		if err == m.ErrDataSourceNotFound {
+			return ApiError(404, "Data source not found", nil)
+		}
+		return ApiError(500, "Failed to update datasource", err)
+	} else if ds.ReadOnly {
+		return ApiError(403, m.ErrDatasourceIsReadOnly.Error(), nil)
+	}
+
 	err := fillWithSecureJsonData(&cmd)
 	if err != nil {
 		return ApiError(500, "Failed to update datasource", err)
@@ -226,6 +266,7 @@ func convertModelToDtos(ds *m.DataSource) dtos.DataSource {
 		IsDefault:         ds.IsDefault,
 		JsonData:          ds.JsonData,
 		SecureJsonFields:  map[string]bool{},
+		ReadOnly:          ds.ReadOnly,
 	}
 
 	for k, v := range ds.SecureJsonData {
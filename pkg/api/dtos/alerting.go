@@ -20,6 +20,10 @@ type AlertRule struct {
 	EvalData       *simplejson.Json `json:"evalData"`
 	ExecutionError string           `json:"executionError"`
 	DashbboardUri  string           `json:"dashboardUri"`
+	Acknowledged   bool             `json:"acknowledged"`
+	AckLogin       string           `json:"ackLogin,omitempty"`
+	AckComment     string           `json:"ackComment,omitempty"`
+	AckDate        *time.Time       `json:"ackDate,omitempty"`
 }
 
 type AlertNotification struct {
@@ -61,6 +65,7 @@ type NotificationTestCommand struct {
 	Name     string           `json:"name"`
 	Type     string           `json:"type"`
 	Settings *simplejson.Json `json:"settings"`
+	Preview  bool             `json:"preview"`
 }
 
 type PauseAlertCommand struct {
@@ -68,6 +73,21 @@ type PauseAlertCommand struct {
 	Paused  bool  `json:"paused"`
 }
 
+type SaveAlertCommand struct {
+	Alert *simplejson.Json `json:"alert" binding:"Required"`
+}
+
+type BulkAlertsCommand struct {
+	Action      string            `json:"action" binding:"Required"`
+	DashboardId int64             `json:"dashboardId"`
+	FolderId    int64             `json:"folderId"`
+	Tags        map[string]string `json:"tags"`
+}
+
+type AcknowledgeAlertCommand struct {
+	Comment string `json:"comment"`
+}
+
 type PauseAllAlertsCommand struct {
 	Paused bool `json:"paused"`
 }
@@ -0,0 +1,21 @@
+package dtos
+
+import "time"
+
+type Folder struct {
+	Id      int64     `json:"id"`
+	Title   string    `json:"title"`
+	Slug    string    `json:"slug"`
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+type CreateFolderCommand struct {
+	Title string `json:"title" binding:"Required"`
+}
+
+type UpdateFolderCommand struct {
+	Title   string `json:"title" binding:"Required"`
+	Version int    `json:"version"`
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/middleware"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
+)
+
+func GetFolders(c *middleware.Context) Response {
+	query := m.GetFoldersQuery{OrgId: c.OrgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(500, "Failed to get folders", err)
+	}
+
+	folderIds := make(map[int64]int64)
+	for _, folder := range query.Result {
+		folderIds[folder.Id] = 0
+	}
+
+	viewable, err := guardian.FilterDashboards(c.SignedInUser, m.PERMISSION_VIEW, folderIds)
+	if err != nil {
+		return ApiError(500, "Error while checking folder permissions", err)
+	}
+
+	result := make([]*dtos.Folder, 0)
+	for _, folder := range query.Result {
+		if viewable[folder.Id] {
+			result = append(result, toFolderDto(folder))
+		}
+	}
+
+	return Json(200, result)
+}
+
+func GetFolder(c *middleware.Context) Response {
+	folder, rsp := getFolder(c.OrgId, c.ParamsInt64(":id"))
+	if rsp != nil {
+		return rsp
+	}
+
+	if rsp := checkDashboardPermission(c, folder.Id, m.PERMISSION_VIEW); rsp != nil {
+		return rsp
+	}
+
+	return Json(200, toFolderDto(folder))
+}
+
+func CreateFolder(c *middleware.Context, cmd dtos.CreateFolderCommand) Response {
+	saveCmd := m.SaveDashboardCommand{
+		OrgId:     c.OrgId,
+		UserId:    c.UserId,
+		IsFolder:  true,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": cmd.Title}),
+	}
+
+	if err := bus.Dispatch(&saveCmd); err != nil {
+		return toFolderError(err)
+	}
+
+	return Json(200, toFolderDto(saveCmd.Result))
+}
+
+// UpdateFolder renames a folder. The version can be given to detect
+// changes made by someone else.
+func UpdateFolder(c *middleware.Context, cmd dtos.UpdateFolderCommand) Response {
+	folder, rsp := getFolder(c.OrgId, c.ParamsInt64(":id"))
+	if rsp != nil {
+		return rsp
+	}
+
+	if rsp := checkDashboardPermission(c, folder.Id, m.PERMISSION_EDIT); rsp != nil {
+		return rsp
+	}
+
+	data := folder.Data
+	data.Set("title", cmd.Title)
+	if cmd.Version > 0 {
+		data.Set("version", cmd.Version)
+	}
+
+	saveCmd := m.SaveDashboardCommand{
+		OrgId:     c.OrgId,
+		UserId:    c.UserId,
+		IsFolder:  true,
+		Dashboard: data,
+	}
+
+	if err := bus.Dispatch(&saveCmd); err != nil {
+		return toFolderError(err)
+	}
+
+	return Json(200, toFolderDto(saveCmd.Result))
+}
+
+// DeleteFolder deletes a folder together with its dashboards.
+func DeleteFolder(c *middleware.Context) Response {
+	folder, rsp := getFolder(c.OrgId, c.ParamsInt64(":id"))
+	if rsp != nil {
+		return rsp
+	}
+
+	if rsp := checkDashboardPermission(c, folder.Id, m.PERMISSION_EDIT); rsp != nil {
+		return rsp
+	}
+
+	cmd := m.DeleteDashboardCommand{Slug: folder.Slug, OrgId: c.OrgId}
+	if err := bus.Dispatch(&cmd); err != nil {
+		return ApiError(500, "Failed to delete folder", err)
+	}
+
+	return ApiSuccess("Folder " + folder.Title + " deleted")
+}
+
+func getFolder(orgId int64, id int64) (*m.Dashboard, Response) {
+	query := m.GetDashboardQuery{Id: id, OrgId: orgId}
+	if err := bus.Dispatch(&query); err != nil {
+		if err == m.ErrDashboardNotFound {
+			return nil, ApiError(404, "Folder not found", nil)
+		}
+		return nil, ApiError(500, "Failed to get folder", err)
+	}
+
+	if !query.Result.IsFolder {
+		return nil, ApiError(404, "Folder not found", nil)
+	}
+
+	return query.Result, nil
+}
+
+func toFolderDto(folder *m.Dashboard) *dtos.Folder {
+	return &dtos.Folder{
+		Id:      folder.Id,
+		Title:   folder.Title,
+		Slug:    folder.Slug,
+		Version: folder.Version,
+		Created: folder.Created,
+		Updated: folder.Updated,
+	}
+}
+
+func toFolderError(err error) Response {
+	switch err {
+	case m.ErrDashboardWithSameNameExists:
+		return ApiError(409, "A folder or dashboard with the same name already exists", nil)
+	case m.ErrDashboardVersionMismatch:
+		return ApiError(412, err.Error(), nil)
+	case m.ErrDashboardTypeMismatch:
+		return ApiError(400, err.Error(), nil)
+	}
+
+	return ApiError(500, "Failed to save folder", err)
+}
@@ -144,3 +144,18 @@ func GenerateSqlTestData(c *middleware.Context) Response {
 
 	return Json(200, &util.DynMap{"message": "OK"})
 }
+
+// GET /metrics
+func GetPrometheusMetrics(c *middleware.Context) {
+	if metrics.UseNilMetrics {
+		c.JsonApiErr(404, "Metrics are not enabled", nil)
+		return
+	}
+
+	c.Resp.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	c.Resp.WriteHeader(200)
+
+	if err := metrics.WritePrometheus(c.Resp, metrics.MetricStats.GetSnapshots()); err != nil {
+		c.Logger.Error("Failed to write metrics", "error", err)
+	}
+}
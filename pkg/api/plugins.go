@@ -173,6 +173,16 @@ func ImportDashboard(c *middleware.Context, apiCmd dtos.ImportDashboardCommand)
 		Dashboard: apiCmd.Dashboard,
 	}
 
+	dash, err := plugins.GetImportedDashboard(&cmd)
+	if err != nil {
+		return ApiError(500, "Failed to import dashboard", err)
+	}
+
+	existing := getExistingDashboard(c.OrgId, dash, cmd.Overwrite)
+	if rsp := checkCanSaveDashboard(c, existing, dash.FolderId); rsp != nil {
+		return rsp
+	}
+
 	if err := bus.Dispatch(&cmd); err != nil {
 		return ApiError(500, "Failed to import dashboard", err)
 	}
@@ -14,6 +14,11 @@ func Search(c *middleware.Context) {
 	tags := c.QueryStrings("tag")
 	starred := c.Query("starred")
 	limit := c.QueryInt("limit")
+	page := c.QueryInt("page")
+	perPage := c.QueryInt("perpage")
+	sort := c.Query("sort")
+	datasources := c.QueryStrings("datasource")
+	panelTypes := c.QueryStrings("panelType")
 
 	if limit == 0 {
 		limit = 1000
@@ -27,18 +32,36 @@ func Search(c *middleware.Context) {
 		}
 	}
 
+	folderIds := make([]int64, 0)
+	for _, id := range c.QueryStrings("folderIds") {
+		folderId, err := strconv.ParseInt(id, 10, 64)
+		if err == nil {
+			folderIds = append(folderIds, folderId)
+		}
+	}
+
 	searchQuery := search.Query{
 		Title:        query,
 		Tags:         tags,
 		UserId:       c.UserId,
 		Limit:        limit,
+		Page:         page,
+		PerPage:      perPage,
+		Sort:         sort,
 		IsStarred:    starred == "true",
 		OrgId:        c.OrgId,
 		DashboardIds: dbids,
+		FolderIds:    folderIds,
+		Datasources:  datasources,
+		PanelTypes:   panelTypes,
+		SignedInUser: c.SignedInUser,
 	}
 
 	err := bus.Dispatch(&searchQuery)
-	if err != nil {
+	if err == search.ErrInvalidSort {
+		c.JsonApiErr(400, err.Error(), nil)
+		return
+	} else if err != nil {
 		c.JsonApiErr(500, "Search failed", err)
 		return
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/cleanup"
 	"github.com/grafana/grafana/pkg/services/eventpublisher"
 	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/provisioning"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/social"
@@ -49,12 +50,24 @@ func (g *GrafanaServerImpl) Start() {
 	initRuntime()
 	initSql()
 	metrics.Init()
-	search.Init()
+
+	if err := search.Init(g.context); err != nil {
+		g.log.Error("Failed to initialize search", "error", err)
+		g.Shutdown(1, "Startup failed")
+		return
+	}
+
 	login.Init()
 	social.NewOAuthService()
 	eventpublisher.Init()
 	plugins.Init()
 
+	if err := provisioning.Init(g.context, setting.ProvisioningPath); err != nil {
+		g.log.Error("Failed to provision Grafana from config", "error", err)
+		g.Shutdown(1, "Startup failed")
+		return
+	}
+
 	// init alerting
 	if setting.AlertingEnabled && setting.ExecuteAlerts {
 		engine := alerting.NewEngine()
@@ -89,13 +102,15 @@ func (g *GrafanaServerImpl) startHttpServer() {
 func (g *GrafanaServerImpl) Shutdown(code int, reason string) {
 	g.log.Info("Shutdown started", "code", code, "reason", reason)
 
-	err := g.httpServer.Shutdown(g.context)
-	if err != nil {
-		g.log.Error("Failed to shutdown server", "error", err)
+	// startup can fail before the http server is started
+	if g.httpServer != nil {
+		if err := g.httpServer.Shutdown(g.context); err != nil {
+			g.log.Error("Failed to shutdown server", "error", err)
+		}
 	}
 
 	g.shutdownFn()
-	err = g.childRoutines.Wait()
+	err := g.childRoutines.Wait()
 
 	g.log.Info("Shutdown completed", "reason", err)
 	log.Close()
@@ -0,0 +1,181 @@
+package dashdiffs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+type DiffType int
+
+const (
+	DiffJSON DiffType = iota
+	DiffBasic
+)
+
+const (
+	ChangeAdded   = "added"
+	ChangeDeleted = "deleted"
+	ChangeChanged = "changed"
+)
+
+// ignoredKeys are top level dashboard properties that differ between
+// every two versions.
+var ignoredKeys = map[string]bool{
+	"id":      true,
+	"version": true,
+}
+
+// ParseDiffType returns the diff type for the given name, json is the
+// default.
+func ParseDiffType(diff string) DiffType {
+	if diff == "basic" {
+		return DiffBasic
+	}
+	return DiffJSON
+}
+
+// Change is a single difference between two dashboards. Path is the
+// location of the changed value, e.g. rows[0].panels[1].title.
+type Change struct {
+	Path     string      `json:"path"`
+	Type     string      `json:"type"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+}
+
+// Summary counts the changes per top level dashboard property.
+type Summary struct {
+	Path    string `json:"path"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Changed int    `json:"changed"`
+}
+
+// Result is the difference between a base and a new dashboard. The delta
+// is only included for json diffs.
+type Result struct {
+	Summary []*Summary `json:"summary"`
+	Delta   []*Change  `json:"delta,omitempty"`
+}
+
+// CalculateDiff compares the json of two dashboards.
+func CalculateDiff(base, new *simplejson.Json, diffType DiffType) *Result {
+	changes := Compare(base, new)
+
+	result := &Result{Summary: summarize(changes)}
+	if diffType == DiffJSON {
+		result.Delta = changes
+	}
+
+	return result
+}
+
+// Compare returns the changes needed to turn base into new. Objects are
+// compared by key and arrays by index.
+func Compare(base, new *simplejson.Json) []*Change {
+	changes := make([]*Change, 0)
+	baseMap := base.MustMap()
+	newMap := new.MustMap()
+
+	for _, key := range unionKeys(baseMap, newMap) {
+		if ignoredKeys[key] {
+			continue
+		}
+		changes = compareValues(changes, key, baseMap[key], newMap[key], hasKey(baseMap, key), hasKey(newMap, key))
+	}
+
+	return changes
+}
+
+func compareValues(changes []*Change, path string, base, new interface{}, inBase, inNew bool) []*Change {
+	switch {
+	case !inBase:
+		return append(changes, &Change{Path: path, Type: ChangeAdded, NewValue: new})
+	case !inNew:
+		return append(changes, &Change{Path: path, Type: ChangeDeleted, OldValue: base})
+	}
+
+	switch baseValue := base.(type) {
+	case map[string]interface{}:
+		if newValue, ok := new.(map[string]interface{}); ok {
+			for _, key := range unionKeys(baseValue, newValue) {
+				changes = compareValues(changes, path+"."+key, baseValue[key], newValue[key], hasKey(baseValue, key), hasKey(newValue, key))
+			}
+			return changes
+		}
+	case []interface{}:
+		if newValue, ok := new.([]interface{}); ok {
+			for i := 0; i < len(baseValue) || i < len(newValue); i++ {
+				var baseItem, newItem interface{}
+				if i < len(baseValue) {
+					baseItem = baseValue[i]
+				}
+				if i < len(newValue) {
+					newItem = newValue[i]
+				}
+				changes = compareValues(changes, fmt.Sprintf("%s[%d]", path, i), baseItem, newItem, i < len(baseValue), i < len(newValue))
+			}
+			return changes
+		}
+	}
+
+	if !reflect.DeepEqual(base, new) {
+		changes = append(changes, &Change{Path: path, Type: ChangeChanged, OldValue: base, NewValue: new})
+	}
+
+	return changes
+}
+
+func summarize(changes []*Change) []*Summary {
+	summaries := make([]*Summary, 0)
+	byPath := make(map[string]*Summary)
+
+	for _, change := range changes {
+		path := change.Path
+		if i := strings.IndexAny(path, ".["); i >= 0 {
+			path = path[:i]
+		}
+
+		summary, exists := byPath[path]
+		if !exists {
+			summary = &Summary{Path: path}
+			byPath[path] = summary
+			summaries = append(summaries, summary)
+		}
+
+		switch change.Type {
+		case ChangeAdded:
+			summary.Added++
+		case ChangeDeleted:
+			summary.Deleted++
+		default:
+			summary.Changed++
+		}
+	}
+
+	return summaries
+}
+
+func hasKey(values map[string]interface{}, key string) bool {
+	_, exists := values[key]
+	return exists
+}
+
+func unionKeys(base, new map[string]interface{}) []string {
+	keys := make([]string, 0, len(base)+len(new))
+	for key := range base {
+		keys = append(keys, key)
+	}
+	for key := range new {
+		if !hasKey(base, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
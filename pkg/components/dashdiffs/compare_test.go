@@ -0,0 +1,66 @@
+package dashdiffs
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCompareDashboards(t *testing.T) {
+	Convey("When comparing two dashboards", t, func() {
+		base, _ := simplejson.NewJson([]byte(`{
+			"id": 1,
+			"version": 3,
+			"title": "Servers",
+			"tags": ["prod"],
+			"rows": [{"panels": [{"id": 1, "title": "CPU"}, {"id": 2, "title": "Memory"}]}],
+			"refresh": "1m"
+		}`))
+		new, _ := simplejson.NewJson([]byte(`{
+			"id": 1,
+			"version": 4,
+			"title": "Servers",
+			"tags": ["prod", "web"],
+			"rows": [{"panels": [{"id": 1, "title": "CPU usage"}]}],
+			"timezone": "utc"
+		}`))
+
+		Convey("Should find added, deleted and changed values", func() {
+			changes := Compare(base, new)
+			So(len(changes), ShouldEqual, 5)
+
+			So(changes[0].Path, ShouldEqual, "refresh")
+			So(changes[0].Type, ShouldEqual, ChangeDeleted)
+			So(changes[0].OldValue, ShouldEqual, "1m")
+
+			So(changes[1].Path, ShouldEqual, "rows[0].panels[0].title")
+			So(changes[1].Type, ShouldEqual, ChangeChanged)
+			So(changes[1].NewValue, ShouldEqual, "CPU usage")
+
+			So(changes[2].Path, ShouldEqual, "rows[0].panels[1]")
+			So(changes[2].Type, ShouldEqual, ChangeDeleted)
+
+			So(changes[3].Path, ShouldEqual, "tags[1]")
+			So(changes[3].Type, ShouldEqual, ChangeAdded)
+
+			So(changes[4].Path, ShouldEqual, "timezone")
+			So(changes[4].Type, ShouldEqual, ChangeAdded)
+		})
+
+		Convey("Should summarize changes for a basic diff", func() {
+			result := CalculateDiff(base, new, ParseDiffType("basic"))
+			So(result.Delta, ShouldBeNil)
+			So(len(result.Summary), ShouldEqual, 4)
+			So(result.Summary[1].Path, ShouldEqual, "rows")
+			So(result.Summary[1].Changed, ShouldEqual, 1)
+			So(result.Summary[1].Deleted, ShouldEqual, 1)
+		})
+
+		Convey("Should find no changes between equal dashboards", func() {
+			result := CalculateDiff(base, base, DiffJSON)
+			So(len(result.Summary), ShouldEqual, 0)
+			So(len(result.Delta), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,603 @@
+// Package dashschema upgrades dashboard json to the current schema version.
+// It is a port of the schema upgrades of the DashboardModel in the frontend,
+// so backend code that reads dashboards sees the same model as the browser.
+package dashschema
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// CurrentSchemaVersion is the schema version the frontend upgrades
+// dashboards to, keep it in sync with updateSchema in dashboard/model.ts.
+const CurrentSchemaVersion = 14
+
+type panelUpgrade func(panel map[string]interface{})
+
+type migrator struct {
+	dash map[string]interface{}
+}
+
+// Migrate upgrades the dashboard in place. Dashboards with the current or
+// a newer schema version are not changed.
+func Migrate(dashboard *simplejson.Json) {
+	dash, ok := dashboard.Interface().(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	oldVersion := dashboard.Get("schemaVersion").MustInt(0)
+	if oldVersion >= CurrentSchemaVersion {
+		return
+	}
+
+	m := &migrator{dash: dash}
+	m.upgrade(oldVersion)
+
+	dash["schemaVersion"] = jsonNumber(CurrentSchemaVersion)
+}
+
+func (m *migrator) upgrade(oldVersion int) {
+	var panelUpgrades []panelUpgrade
+
+	if oldVersion < 2 {
+		m.upgradeServices()
+		panelUpgrades = append(panelUpgrades, upgradeGraphiteGraph)
+	}
+
+	if oldVersion < 3 {
+		panelUpgrades = append(panelUpgrades, m.ensurePanelIds())
+	}
+
+	if oldVersion < 4 {
+		panelUpgrades = append(panelUpgrades, upgradeAliasYAxis)
+	}
+
+	if oldVersion < 6 {
+		m.upgradePulldowns()
+		m.upgradeVariableDefaults()
+	}
+
+	if oldVersion < 7 {
+		m.upgradeNav()
+		panelUpgrades = append(panelUpgrades, ensureQueryRefIds)
+	}
+
+	if oldVersion < 8 {
+		panelUpgrades = append(panelUpgrades, upgradeInfluxdbQueries)
+	}
+
+	if oldVersion < 9 {
+		panelUpgrades = append(panelUpgrades, upgradeSinglestatThresholds)
+	}
+
+	if oldVersion < 10 {
+		panelUpgrades = append(panelUpgrades, upgradeTableThresholds)
+	}
+
+	if oldVersion < 12 {
+		m.upgradeVariableRefreshAndHide()
+		panelUpgrades = append(panelUpgrades, upgradeGraphAxes)
+	}
+
+	if oldVersion < 13 {
+		panelUpgrades = append(panelUpgrades, upgradeGraphThresholds)
+	}
+
+	if oldVersion < 14 {
+		m.upgradeSharedCrosshair()
+	}
+
+	for _, panel := range m.panels() {
+		for _, upgrade := range panelUpgrades {
+			upgrade(panel)
+		}
+	}
+}
+
+// schema version 2: time and template variables used to be in services.filter
+func (m *migrator) upgradeServices() {
+	services := object(m.dash["services"])
+	if services == nil {
+		return
+	}
+
+	if filter := object(services["filter"]); filter != nil {
+		m.dash["time"] = filter["time"]
+
+		list := array(filter["list"])
+		if list == nil {
+			list = make([]interface{}, 0)
+		}
+		m.templating()["list"] = list
+	}
+
+	delete(m.dash, "services")
+}
+
+func upgradeGraphiteGraph(panel map[string]interface{}) {
+	if panel["type"] == "graphite" {
+		panel["type"] = "graph"
+	}
+
+	if panel["type"] != "graph" {
+		return
+	}
+
+	if legend, ok := panel["legend"].(bool); ok {
+		panel["legend"] = map[string]interface{}{"show": legend}
+	}
+
+	if grid := object(panel["grid"]); grid != nil {
+		if isTruthy(grid["min"]) {
+			grid["leftMin"] = grid["min"]
+			delete(grid, "min")
+		}
+
+		if isTruthy(grid["max"]) {
+			grid["leftMax"] = grid["max"]
+			delete(grid, "max")
+		}
+	}
+
+	if isTruthy(panel["y_format"]) {
+		setYFormat(panel, 0, panel["y_format"])
+		delete(panel, "y_format")
+	}
+
+	if isTruthy(panel["y2_format"]) {
+		setYFormat(panel, 1, panel["y2_format"])
+		delete(panel, "y2_format")
+	}
+}
+
+func setYFormat(panel map[string]interface{}, index int, format interface{}) {
+	formats := array(panel["y_formats"])
+	for len(formats) <= index {
+		formats = append(formats, nil)
+	}
+
+	formats[index] = format
+	panel["y_formats"] = formats
+}
+
+// schema version 3: panels without id get the next free ids
+func (m *migrator) ensurePanelIds() panelUpgrade {
+	maxId := 0
+	for _, panel := range m.panels() {
+		if id, ok := number(panel["id"]); ok && int(id) > maxId {
+			maxId = int(id)
+		}
+	}
+
+	nextId := maxId + 1
+	return func(panel map[string]interface{}) {
+		if !isTruthy(panel["id"]) {
+			panel["id"] = jsonNumber(nextId)
+			nextId++
+		}
+	}
+}
+
+// schema version 4: aliasYAxis became series overrides. The frontend keeps
+// only the last alias, here every alias gets an override, sorted by alias.
+func upgradeAliasYAxis(panel map[string]interface{}) {
+	if panel["type"] != "graph" {
+		return
+	}
+
+	aliasYAxis := object(panel["aliasYAxis"])
+	if len(aliasYAxis) > 0 {
+		aliases := make([]string, 0, len(aliasYAxis))
+		for alias := range aliasYAxis {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+
+		overrides := make([]interface{}, 0, len(aliases))
+		for _, alias := range aliases {
+			overrides = append(overrides, map[string]interface{}{"alias": alias, "yaxis": aliasYAxis[alias]})
+		}
+		panel["seriesOverrides"] = overrides
+	}
+
+	delete(panel, "aliasYAxis")
+}
+
+// schema version 6: annotations used to be a pulldown
+func (m *migrator) upgradePulldowns() {
+	for _, item := range array(m.dash["pulldowns"]) {
+		pulldown := object(item)
+		if pulldown == nil || pulldown["type"] != "annotations" {
+			continue
+		}
+
+		list := array(pulldown["annotations"])
+		if list == nil {
+			list = make([]interface{}, 0)
+		}
+		m.dash["annotations"] = map[string]interface{}{"list": list}
+		break
+	}
+
+	delete(m.dash, "pulldowns")
+}
+
+func (m *migrator) upgradeVariableDefaults() {
+	for _, variable := range m.variables() {
+		if _, exists := variable["datasource"]; !exists {
+			variable["datasource"] = nil
+		}
+
+		if variable["type"] == "filter" {
+			variable["type"] = "query"
+		}
+
+		if _, exists := variable["type"]; !exists {
+			variable["type"] = "query"
+		}
+
+		if _, exists := variable["allFormat"]; !exists {
+			variable["allFormat"] = "glob"
+		}
+	}
+}
+
+// schema version 7: the time picker used to be in nav
+func (m *migrator) upgradeNav() {
+	if nav := array(m.dash["nav"]); len(nav) > 0 {
+		m.dash["timepicker"] = nav[0]
+	}
+
+	delete(m.dash, "nav")
+}
+
+func ensureQueryRefIds(panel map[string]interface{}) {
+	targets := objects(panel["targets"])
+
+	for _, target := range targets {
+		if isTruthy(target["refId"]) {
+			continue
+		}
+
+		if refId := nextQueryLetter(targets); refId != "" {
+			target["refId"] = refId
+		}
+	}
+}
+
+func nextQueryLetter(targets []map[string]interface{}) string {
+	used := make(map[interface{}]bool)
+	for _, target := range targets {
+		used[target["refId"]] = true
+	}
+
+	for _, letter := range "ABCDEFGHIJKLMNOPQRSTUVWXYZ" {
+		if !used[string(letter)] {
+			return string(letter)
+		}
+	}
+
+	return ""
+}
+
+// schema version 8: influxdb queries got select and group by parts
+func upgradeInfluxdbQueries(panel map[string]interface{}) {
+	for _, target := range objects(panel["targets"]) {
+		if !isTruthy(target["fields"]) || !isTruthy(target["tags"]) || !isTruthy(target["groupBy"]) {
+			continue
+		}
+
+		if isTruthy(target["rawQuery"]) {
+			delete(target, "fields")
+			delete(target, "fill")
+			continue
+		}
+
+		selects := make([]interface{}, 0)
+		for _, field := range objects(target["fields"]) {
+			parts := []interface{}{
+				map[string]interface{}{"type": "field", "params": []interface{}{field["name"]}},
+				map[string]interface{}{"type": field["func"], "params": []interface{}{}},
+			}
+
+			if isTruthy(field["mathExpr"]) {
+				parts = append(parts, map[string]interface{}{"type": "math", "params": []interface{}{field["mathExpr"]}})
+			}
+
+			if isTruthy(field["asExpr"]) {
+				parts = append(parts, map[string]interface{}{"type": "alias", "params": []interface{}{field["asExpr"]}})
+			}
+
+			selects = append(selects, parts)
+		}
+		target["select"] = selects
+		delete(target, "fields")
+
+		for _, part := range objects(target["groupBy"]) {
+			if part["type"] == "time" && isTruthy(part["interval"]) {
+				part["params"] = []interface{}{part["interval"]}
+				delete(part, "interval")
+			}
+
+			if part["type"] == "tag" && isTruthy(part["key"]) {
+				part["params"] = []interface{}{part["key"]}
+				delete(part, "key")
+			}
+		}
+
+		if isTruthy(target["fill"]) {
+			groupBy := array(target["groupBy"])
+			target["groupBy"] = append(groupBy, map[string]interface{}{"type": "fill", "params": []interface{}{target["fill"]}})
+			delete(target, "fill")
+		}
+	}
+}
+
+// schema version 9: singlestat thresholds lost their first value
+func upgradeSinglestatThresholds(panel map[string]interface{}) {
+	if panel["type"] != "singlestat" {
+		return
+	}
+
+	thresholds, ok := panel["thresholds"].(string)
+	if !ok || thresholds == "" {
+		return
+	}
+
+	values := strings.Split(thresholds, ",")
+	if len(values) >= 3 {
+		panel["thresholds"] = strings.Join(values[1:], ",")
+	}
+}
+
+// schema version 10: table style thresholds lost their first value
+func upgradeTableThresholds(panel map[string]interface{}) {
+	if panel["type"] != "table" {
+		return
+	}
+
+	for _, style := range objects(panel["styles"]) {
+		if thresholds := array(style["thresholds"]); len(thresholds) >= 3 {
+			style["thresholds"] = thresholds[1:]
+		}
+	}
+}
+
+// schema version 12: variable refresh became a number and hide replaced
+// hideVariable and hideLabel
+func (m *migrator) upgradeVariableRefreshAndHide() {
+	for _, variable := range m.variables() {
+		if isTruthy(variable["refresh"]) {
+			variable["refresh"] = jsonNumber(1)
+		} else {
+			variable["refresh"] = jsonNumber(0)
+		}
+
+		if isTruthy(variable["hideVariable"]) {
+			variable["hide"] = jsonNumber(2)
+		} else if isTruthy(variable["hideLabel"]) {
+			variable["hide"] = jsonNumber(1)
+		}
+	}
+}
+
+// schema version 12: graph axes moved from grid to yaxes and xaxis
+func upgradeGraphAxes(panel map[string]interface{}) {
+	if panel["type"] != "graph" {
+		return
+	}
+
+	grid := object(panel["grid"])
+	if grid == nil {
+		return
+	}
+
+	if _, exists := panel["yaxes"]; exists {
+		return
+	}
+
+	formats := array(panel["y_formats"])
+	copyFormat := func(axis map[string]interface{}, index int) {
+		if index < len(formats) {
+			axis["format"] = formats[index]
+		}
+	}
+
+	left, right := make(map[string]interface{}), make(map[string]interface{})
+	copyValue(left, "show", panel, "y-axis")
+	copyValue(left, "min", grid, "leftMin")
+	copyValue(left, "max", grid, "leftMax")
+	copyValue(left, "logBase", grid, "leftLogBase")
+	copyFormat(left, 0)
+	copyValue(left, "label", panel, "leftYAxisLabel")
+
+	copyValue(right, "show", panel, "y-axis")
+	copyValue(right, "min", grid, "rightMin")
+	copyValue(right, "max", grid, "rightMax")
+	copyValue(right, "logBase", grid, "rightLogBase")
+	copyFormat(right, 1)
+	copyValue(right, "label", panel, "rightYAxisLabel")
+
+	panel["yaxes"] = []interface{}{left, right}
+
+	xaxis := make(map[string]interface{})
+	copyValue(xaxis, "show", panel, "x-axis")
+	panel["xaxis"] = xaxis
+
+	for _, key := range []string{"leftMin", "leftMax", "leftLogBase", "rightMin", "rightMax", "rightLogBase"} {
+		delete(grid, key)
+	}
+
+	for _, key := range []string{"y_formats", "leftYAxisLabel", "rightYAxisLabel", "y-axis", "x-axis"} {
+		delete(panel, key)
+	}
+}
+
+// schema version 13: graph thresholds moved from grid to thresholds
+func upgradeGraphThresholds(panel map[string]interface{}) {
+	if panel["type"] != "graph" {
+		return
+	}
+
+	grid := object(panel["grid"])
+	if grid == nil {
+		return
+	}
+
+	thresholds := make([]interface{}, 0)
+	t1 := graphThreshold(grid, "threshold1")
+	t2 := graphThreshold(grid, "threshold2")
+
+	if value1, ok := number(t1["value"]); ok {
+		if value2, ok := number(t2["value"]); ok {
+			op := "gt"
+			if value1 > value2 {
+				op = "lt"
+			}
+			t1["op"], t2["op"] = op, op
+			thresholds = append(thresholds, t1, t2)
+		} else {
+			t1["op"] = "gt"
+			thresholds = append(thresholds, t1)
+		}
+	}
+
+	panel["thresholds"] = thresholds
+
+	for _, key := range []string{"threshold1", "threshold1Color", "threshold2", "threshold2Color", "thresholdLine"} {
+		delete(grid, key)
+	}
+}
+
+func graphThreshold(grid map[string]interface{}, name string) map[string]interface{} {
+	threshold := make(map[string]interface{})
+
+	value, exists := grid[name]
+	if !exists || value == nil {
+		return threshold
+	}
+
+	threshold["value"] = value
+	threshold["colorMode"] = "custom"
+
+	if isTruthy(grid["thresholdLine"]) {
+		threshold["line"] = true
+		copyValue(threshold, "lineColor", grid, name+"Color")
+	} else {
+		threshold["fill"] = true
+		copyValue(threshold, "fillColor", grid, name+"Color")
+	}
+
+	return threshold
+}
+
+// schema version 14: sharedCrosshair became graphTooltip
+func (m *migrator) upgradeSharedCrosshair() {
+	if isTruthy(m.dash["sharedCrosshair"]) {
+		m.dash["graphTooltip"] = jsonNumber(1)
+	} else {
+		m.dash["graphTooltip"] = jsonNumber(0)
+	}
+
+	delete(m.dash, "sharedCrosshair")
+}
+
+func (m *migrator) panels() []map[string]interface{} {
+	var panels []map[string]interface{}
+
+	for _, row := range objects(m.dash["rows"]) {
+		panels = append(panels, objects(row["panels"])...)
+	}
+
+	return panels
+}
+
+func (m *migrator) templating() map[string]interface{} {
+	templating := object(m.dash["templating"])
+	if templating == nil {
+		templating = make(map[string]interface{})
+		m.dash["templating"] = templating
+	}
+
+	return templating
+}
+
+func (m *migrator) variables() []map[string]interface{} {
+	return objects(object(m.dash["templating"])["list"])
+}
+
+func object(value interface{}) map[string]interface{} {
+	obj, _ := value.(map[string]interface{})
+	return obj
+}
+
+func array(value interface{}) []interface{} {
+	arr, _ := value.([]interface{})
+	return arr
+}
+
+// objects returns the objects in an array, other items are skipped.
+func objects(value interface{}) []map[string]interface{} {
+	var result []map[string]interface{}
+
+	for _, item := range array(value) {
+		if obj := object(item); obj != nil {
+			result = append(result, obj)
+		}
+	}
+
+	return result
+}
+
+// jsonNumber keeps numbers set by the upgrades the same type as numbers
+// decoded by simplejson, code reading dashboards expects json.Number.
+func jsonNumber(n int) json.Number {
+	return json.Number(strconv.Itoa(n))
+}
+
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+
+	return 0, false
+}
+
+// isTruthy follows the javascript rules the frontend upgrades rely on.
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+
+	if n, ok := number(value); ok {
+		return n != 0
+	}
+
+	return true
+}
+
+// copyValue copies a value between objects, missing values are not
+// copied like undefined values are left out of json by the frontend.
+func copyValue(dst map[string]interface{}, dstKey string, src map[string]interface{}, srcKey string) {
+	if value, exists := src[srcKey]; exists {
+		dst[dstKey] = value
+	}
+}
@@ -0,0 +1,92 @@
+package dashschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// goldenVersions lists the schema versions with upgrades, every version has
+// an input dashboard in the previous version and a golden file.
+var goldenVersions = []int{2, 3, 4, 6, 7, 8, 9, 10, 12, 13, 14}
+
+func TestMigrate(t *testing.T) {
+	Convey("Migrating dashboards", t, func() {
+		for _, version := range goldenVersions {
+			input := filepath.Join("test-dashboards", fmt.Sprintf("schema-%d.json", version))
+			golden := filepath.Join("test-dashboards", fmt.Sprintf("schema-%d.golden.json", version))
+
+			Convey(fmt.Sprintf("Should upgrade %s to the golden file", input), func() {
+				dash := readDashboard(input)
+				Migrate(dash)
+
+				result, err := dash.EncodePretty()
+				So(err, ShouldBeNil)
+				result = append(result, '\n')
+
+				if *update {
+					So(ioutil.WriteFile(golden, result, 0644), ShouldBeNil)
+				}
+
+				expected, err := ioutil.ReadFile(golden)
+				So(err, ShouldBeNil)
+				So(string(result), ShouldEqual, string(expected))
+				So(dash.Get("schemaVersion").MustInt(), ShouldEqual, CurrentSchemaVersion)
+			})
+
+			Convey(fmt.Sprintf("Should not change %s when migrating again", golden), func() {
+				dash := readDashboard(input)
+				Migrate(dash)
+				before, _ := dash.Encode()
+
+				Migrate(dash)
+				after, _ := dash.Encode()
+				So(bytes.Equal(before, after), ShouldBeTrue)
+			})
+		}
+
+		Convey("Should not change dashboards in the current schema version", func() {
+			dash, _ := simplejson.NewJson([]byte(`{
+				"schemaVersion": 14,
+				"sharedCrosshair": true,
+				"rows": [{"panels": [{"type": "graph", "grid": {"threshold1": 10}}]}]
+			}`))
+			Migrate(dash)
+
+			So(dash.Get("sharedCrosshair").MustBool(), ShouldBeTrue)
+			So(dash.Get("graphTooltip").Interface(), ShouldBeNil)
+			So(dash.Get("rows").GetIndex(0).Get("panels").GetIndex(0).Get("grid").Get("threshold1").MustInt(), ShouldEqual, 10)
+		})
+
+		Convey("Should upgrade dashboards without schema version", func() {
+			dash := simplejson.NewFromAny(map[string]interface{}{"title": "Old"})
+			Migrate(dash)
+
+			So(dash.Get("schemaVersion").MustInt(), ShouldEqual, CurrentSchemaVersion)
+			So(dash.Get("graphTooltip").MustInt(-1), ShouldEqual, 0)
+
+			Convey("Should set numbers like the json decoder", func() {
+				So(dash.Get("schemaVersion").Interface(), ShouldHaveSameTypeAs, json.Number(""))
+				So(dash.Get("graphTooltip").Interface(), ShouldHaveSameTypeAs, json.Number(""))
+			})
+		})
+	})
+}
+
+func readDashboard(path string) *simplejson.Json {
+	data, err := ioutil.ReadFile(path)
+	So(err, ShouldBeNil)
+
+	dash, err := simplejson.NewJson(data)
+	So(err, ShouldBeNil)
+	return dash
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"strings"
+	"sync"
+)
+
+// dynamicMetrics holds metrics registered on first use, for tag values
+// that are only known at runtime like notifier types or alert rule ids.
+var dynamicMetrics = struct {
+	sync.Mutex
+	metrics map[string]Metric
+}{metrics: make(map[string]Metric)}
+
+func resetDynamicMetrics() {
+	dynamicMetrics.Lock()
+	defer dynamicMetrics.Unlock()
+	dynamicMetrics.metrics = make(map[string]Metric)
+}
+
+func dynamicMetricKey(name string, tagStrings []string) string {
+	return name + "|" + strings.Join(tagStrings, "|")
+}
+
+func getOrRegister(name string, tagStrings []string, create func() Metric) Metric {
+	key := dynamicMetricKey(name, tagStrings)
+
+	dynamicMetrics.Lock()
+	defer dynamicMetrics.Unlock()
+
+	if metric, exists := dynamicMetrics.metrics[key]; exists {
+		return metric
+	}
+
+	metric := create()
+	dynamicMetrics.metrics[key] = metric
+	return metric
+}
+
+func GetOrRegCounter(name string, tagStrings ...string) Counter {
+	return getOrRegister(name, tagStrings, func() Metric { return RegCounter(name, tagStrings...) }).(Counter)
+}
+
+func GetOrRegGauge(name string, tagStrings ...string) Gauge {
+	return getOrRegister(name, tagStrings, func() Metric { return RegGauge(name, tagStrings...) }).(Gauge)
+}
+
+func GetOrRegTimer(name string, tagStrings ...string) Timer {
+	return getOrRegister(name, tagStrings, func() Metric { return RegTimer(name, tagStrings...) }).(Timer)
+}
+
+// Unregister removes a dynamic metric so that metrics for tag values
+// that no longer exist, like deleted alert rules, stop being published.
+func Unregister(name string, tagStrings ...string) {
+	key := dynamicMetricKey(name, tagStrings)
+
+	dynamicMetrics.Lock()
+	defer dynamicMetrics.Unlock()
+
+	if metric, exists := dynamicMetrics.metrics[key]; exists {
+		MetricStats.Unregister(metric)
+		delete(dynamicMetrics.metrics, key)
+	}
+}
@@ -9,67 +9,80 @@ func init() {
 }
 
 var (
-	M_Instance_Start                       Counter
-	M_Page_Status_200                      Counter
-	M_Page_Status_500                      Counter
-	M_Page_Status_404                      Counter
-	M_Page_Status_Unknown                  Counter
-	M_Api_Status_200                       Counter
-	M_Api_Status_404                       Counter
-	M_Api_Status_500                       Counter
-	M_Api_Status_Unknown                   Counter
-	M_Proxy_Status_200                     Counter
-	M_Proxy_Status_404                     Counter
-	M_Proxy_Status_500                     Counter
-	M_Proxy_Status_Unknown                 Counter
-	M_Api_User_SignUpStarted               Counter
-	M_Api_User_SignUpCompleted             Counter
-	M_Api_User_SignUpInvite                Counter
-	M_Api_Dashboard_Save                   Timer
-	M_Api_Dashboard_Get                    Timer
-	M_Api_Dashboard_Search                 Timer
-	M_Api_Admin_User_Create                Counter
-	M_Api_Login_Post                       Counter
-	M_Api_Login_OAuth                      Counter
-	M_Api_Org_Create                       Counter
-	M_Api_Dashboard_Snapshot_Create        Counter
-	M_Api_Dashboard_Snapshot_External      Counter
-	M_Api_Dashboard_Snapshot_Get           Counter
-	M_Models_Dashboard_Insert              Counter
-	M_Alerting_Result_State_Alerting       Counter
-	M_Alerting_Result_State_Ok             Counter
-	M_Alerting_Result_State_Paused         Counter
-	M_Alerting_Result_State_NoData         Counter
-	M_Alerting_Result_State_Pending        Counter
-	M_Alerting_Notification_Sent_Slack     Counter
-	M_Alerting_Notification_Sent_Email     Counter
-	M_Alerting_Notification_Sent_Webhook   Counter
-	M_Alerting_Notification_Sent_PagerDuty Counter
-	M_Alerting_Notification_Sent_LINE      Counter
-	M_Alerting_Notification_Sent_Victorops Counter
-	M_Alerting_Notification_Sent_OpsGenie  Counter
-	M_Alerting_Notification_Sent_Telegram  Counter
-	M_Alerting_Notification_Sent_Threema   Counter
-	M_Alerting_Notification_Sent_Sensu     Counter
-	M_Alerting_Notification_Sent_Pushover  Counter
-	M_Aws_CloudWatch_GetMetricStatistics   Counter
-	M_Aws_CloudWatch_ListMetrics           Counter
+	M_Instance_Start                          Counter
+	M_Page_Status_200                         Counter
+	M_Page_Status_500                         Counter
+	M_Page_Status_404                         Counter
+	M_Page_Status_Unknown                     Counter
+	M_Api_Status_200                          Counter
+	M_Api_Status_404                          Counter
+	M_Api_Status_500                          Counter
+	M_Api_Status_Unknown                      Counter
+	M_Proxy_Status_200                        Counter
+	M_Proxy_Status_404                        Counter
+	M_Proxy_Status_500                        Counter
+	M_Proxy_Status_Unknown                    Counter
+	M_Api_User_SignUpStarted                  Counter
+	M_Api_User_SignUpCompleted                Counter
+	M_Api_User_SignUpInvite                   Counter
+	M_Api_Dashboard_Save                      Timer
+	M_Api_Dashboard_Get                       Timer
+	M_Api_Dashboard_Search                    Timer
+	M_Api_Admin_User_Create                   Counter
+	M_Api_Login_Post                          Counter
+	M_Api_Login_OAuth                         Counter
+	M_Api_Org_Create                          Counter
+	M_Api_Dashboard_Snapshot_Create           Counter
+	M_Api_Dashboard_Snapshot_External         Counter
+	M_Api_Dashboard_Snapshot_Get              Counter
+	M_Models_Dashboard_Insert                 Counter
+	M_Alerting_Result_State_Alerting          Counter
+	M_Alerting_Result_State_Ok                Counter
+	M_Alerting_Result_State_Paused            Counter
+	M_Alerting_Result_State_NoData            Counter
+	M_Alerting_Result_State_Pending           Counter
+	M_Alerting_Result_State_Inhibited         Counter
+	M_Alerting_Jobs_Skipped                   Counter
+	M_Alerting_Notification_Sent_Slack        Counter
+	M_Alerting_Notification_Sent_Email        Counter
+	M_Alerting_Notification_Sent_Webhook      Counter
+	M_Alerting_Notification_Sent_PagerDuty    Counter
+	M_Alerting_Notification_Sent_LINE         Counter
+	M_Alerting_Notification_Sent_Victorops    Counter
+	M_Alerting_Notification_Sent_OpsGenie     Counter
+	M_Alerting_Notification_Sent_Telegram     Counter
+	M_Alerting_Notification_Sent_Threema      Counter
+	M_Alerting_Notification_Sent_Sensu        Counter
+	M_Alerting_Notification_Sent_Pushover     Counter
+	M_Alerting_Notification_Sent_Teams        Counter
+	M_Alerting_Notification_Sent_Discord      Counter
+	M_Alerting_Notification_Sent_Alertmanager Counter
+	M_Aws_CloudWatch_GetMetricStatistics      Counter
+	M_Aws_CloudWatch_ListMetrics              Counter
 
 	// Timers
 	M_DataSource_ProxyReq_Timer Timer
 	M_Alerting_Execution_Time   Timer
 
 	// StatTotals
-	M_Alerting_Active_Alerts Gauge
-	M_StatTotal_Dashboards   Gauge
-	M_StatTotal_Users        Gauge
-	M_StatTotal_Orgs         Gauge
-	M_StatTotal_Playlists    Gauge
+	M_Alerting_Active_Alerts    Gauge
+	M_Alerting_Execution_Queue  Gauge
+	M_Alerting_Alerts_Alerting  Gauge
+	M_Alerting_Alerts_Ok        Gauge
+	M_Alerting_Alerts_Paused    Gauge
+	M_Alerting_Alerts_NoData    Gauge
+	M_Alerting_Alerts_Pending   Gauge
+	M_Alerting_Alerts_Inhibited Gauge
+	M_StatTotal_Dashboards      Gauge
+	M_StatTotal_Users           Gauge
+	M_StatTotal_Orgs            Gauge
+	M_StatTotal_Playlists       Gauge
 )
 
 func initMetricVars(settings *MetricSettings) {
 	UseNilMetrics = settings.Enabled == false
 	MetricStats = NewRegistry()
+	resetDynamicMetrics()
 
 	M_Instance_Start = RegCounter("instance_start")
 
@@ -112,6 +125,8 @@ func initMetricVars(settings *MetricSettings) {
 	M_Alerting_Result_State_Paused = RegCounter("alerting.result", "state", "paused")
 	M_Alerting_Result_State_NoData = RegCounter("alerting.result", "state", "no_data")
 	M_Alerting_Result_State_Pending = RegCounter("alerting.result", "state", "pending")
+	M_Alerting_Result_State_Inhibited = RegCounter("alerting.result", "state", "inhibited")
+	M_Alerting_Jobs_Skipped = RegCounter("alerting.jobs_skipped")
 
 	M_Alerting_Notification_Sent_Slack = RegCounter("alerting.notifications_sent", "type", "slack")
 	M_Alerting_Notification_Sent_Email = RegCounter("alerting.notifications_sent", "type", "email")
@@ -124,6 +139,9 @@ func initMetricVars(settings *MetricSettings) {
 	M_Alerting_Notification_Sent_Sensu = RegCounter("alerting.notifications_sent", "type", "sensu")
 	M_Alerting_Notification_Sent_LINE = RegCounter("alerting.notifications_sent", "type", "LINE")
 	M_Alerting_Notification_Sent_Pushover = RegCounter("alerting.notifications_sent", "type", "pushover")
+	M_Alerting_Notification_Sent_Teams = RegCounter("alerting.notifications_sent", "type", "teams")
+	M_Alerting_Notification_Sent_Discord = RegCounter("alerting.notifications_sent", "type", "discord")
+	M_Alerting_Notification_Sent_Alertmanager = RegCounter("alerting.notifications_sent", "type", "alertmanager")
 
 	M_Aws_CloudWatch_GetMetricStatistics = RegCounter("aws.cloudwatch.get_metric_statistics")
 	M_Aws_CloudWatch_ListMetrics = RegCounter("aws.cloudwatch.list_metrics")
@@ -134,6 +152,13 @@ func initMetricVars(settings *MetricSettings) {
 
 	// StatTotals
 	M_Alerting_Active_Alerts = RegGauge("alerting.active_alerts")
+	M_Alerting_Execution_Queue = RegGauge("alerting.execution_queue")
+	M_Alerting_Alerts_Alerting = RegGauge("alerting.alerts", "state", "alerting")
+	M_Alerting_Alerts_Ok = RegGauge("alerting.alerts", "state", "ok")
+	M_Alerting_Alerts_Paused = RegGauge("alerting.alerts", "state", "paused")
+	M_Alerting_Alerts_NoData = RegGauge("alerting.alerts", "state", "no_data")
+	M_Alerting_Alerts_Pending = RegGauge("alerting.alerts", "state", "pending")
+	M_Alerting_Alerts_Inhibited = RegGauge("alerting.alerts", "state", "inhibited")
 	M_StatTotal_Dashboards = RegGauge("stat_totals", "stat", "dashboards")
 	M_StatTotal_Users = RegGauge("stat_totals", "stat", "users")
 	M_StatTotal_Orgs = RegGauge("stat_totals", "stat", "orgs")
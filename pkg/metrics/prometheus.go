@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+var prometheusNameReplacer = strings.NewReplacer(".", "_", "-", "_", " ", "_")
+
+// WritePrometheus writes the metrics in the Prometheus text exposition
+// format. Counters become counters, gauges gauges and timers summaries in
+// milliseconds.
+func WritePrometheus(w io.Writer, metrics []Metric) error {
+	buf := bytes.NewBufferString("")
+	typed := make(map[string]bool)
+
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return metrics[i].Name() < metrics[j].Name()
+	})
+
+	for _, m := range metrics {
+		name := "grafana_" + prometheusNameReplacer.Replace(m.Name())
+		labels := m.GetTagsCopy()
+
+		switch metric := m.(type) {
+		case Counter:
+			writePrometheusType(buf, typed, name+"_total", "counter")
+			writePrometheusValue(buf, name+"_total", labels, float64(metric.Count()))
+		case Gauge:
+			writePrometheusType(buf, typed, name, "gauge")
+			writePrometheusValue(buf, name, labels, float64(metric.Value()))
+		case Timer:
+			name += "_ms"
+			writePrometheusType(buf, typed, name, "summary")
+
+			quantiles := []float64{0.5, 0.9, 0.99}
+			for i, value := range metric.Percentiles(quantiles) {
+				quantileLabels := make(map[string]string)
+				for key, label := range labels {
+					quantileLabels[key] = label
+				}
+				quantileLabels["quantile"] = fmt.Sprintf("%g", quantiles[i])
+				writePrometheusValue(buf, name, quantileLabels, value)
+			}
+
+			writePrometheusValue(buf, name+"_sum", labels, float64(metric.Sum()))
+			writePrometheusValue(buf, name+"_count", labels, float64(metric.Count()))
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writePrometheusType(buf *bytes.Buffer, typed map[string]bool, name string, typ string) {
+	if typed[name] {
+		return
+	}
+
+	typed[name] = true
+	buf.WriteString(fmt.Sprintf("# TYPE %s %s\n", name, typ))
+}
+
+func writePrometheusValue(buf *bytes.Buffer, name string, labels map[string]string, value float64) {
+	buf.WriteString(name)
+
+	if len(labels) > 0 {
+		keys := make([]string, 0, len(labels))
+		for key := range labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			pairs = append(pairs, fmt.Sprintf("%s=%q", prometheusNameReplacer.Replace(key), labels[key]))
+		}
+		buf.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+
+	buf.WriteString(fmt.Sprintf(" %g\n", value))
+}
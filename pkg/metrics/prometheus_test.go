@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPrometheusFormat(t *testing.T) {
+	Convey("When writing metrics in Prometheus format", t, func() {
+		UseNilMetrics = false
+		defer func() { UseNilMetrics = true }()
+
+		counter := NewCounter(NewMetricMeta("alerting.result", []string{"state", "ok"}))
+		counter.Inc(3)
+		otherCounter := NewCounter(NewMetricMeta("alerting.result", []string{"state", "no_data"}))
+		gauge := NewGauge(NewMetricMeta("alerting.execution_queue", []string{}))
+		gauge.Update(7)
+		timer := NewTimer(NewMetricMeta("alerting.notification_time", []string{"type", "slack"}))
+		timer.Update(time.Duration(20))
+
+		buf := bytes.NewBufferString("")
+		err := WritePrometheus(buf, []Metric{counter.Snapshot(), gauge.Snapshot(), timer.Snapshot(), otherCounter.Snapshot()})
+		So(err, ShouldBeNil)
+
+		output := buf.String()
+
+		Convey("Should write counters with labels", func() {
+			So(output, ShouldContainSubstring, "# TYPE grafana_alerting_result_total counter\n")
+			So(output, ShouldContainSubstring, "grafana_alerting_result_total{state=\"ok\"} 3\n")
+			So(output, ShouldContainSubstring, "grafana_alerting_result_total{state=\"no_data\"} 0\n")
+		})
+
+		Convey("Should write the type once per metric name", func() {
+			So(bytes.Count(buf.Bytes(), []byte("# TYPE grafana_alerting_result_total")), ShouldEqual, 1)
+		})
+
+		Convey("Should write gauges", func() {
+			So(output, ShouldContainSubstring, "# TYPE grafana_alerting_execution_queue gauge\n")
+			So(output, ShouldContainSubstring, "grafana_alerting_execution_queue 7\n")
+		})
+
+		Convey("Should write timers as summaries", func() {
+			So(output, ShouldContainSubstring, "# TYPE grafana_alerting_notification_time_ms summary\n")
+			So(output, ShouldContainSubstring, "grafana_alerting_notification_time_ms{quantile=\"0.5\",type=\"slack\"} 20\n")
+			So(output, ShouldContainSubstring, "grafana_alerting_notification_time_ms_count{type=\"slack\"} 1\n")
+		})
+	})
+}
+
+func TestDynamicMetrics(t *testing.T) {
+	Convey("When getting dynamic metrics", t, func() {
+		initMetricVars(&MetricSettings{Enabled: true})
+		defer initMetricVars(&MetricSettings{})
+
+		first := GetOrRegCounter("alerting.notification_errors", "type", "slack")
+		second := GetOrRegCounter("alerting.notification_errors", "type", "slack")
+		other := GetOrRegCounter("alerting.notification_errors", "type", "email")
+
+		Convey("Should register each tag combination once", func() {
+			So(first, ShouldEqual, second)
+			So(first, ShouldNotEqual, other)
+
+			count := 0
+			for _, metric := range MetricStats.GetSnapshots() {
+				if metric.Name() == "alerting.notification_errors" {
+					count++
+				}
+			}
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("Should stop publishing unregistered metrics", func() {
+			Unregister("alerting.notification_errors", "type", "email")
+
+			count := 0
+			for _, metric := range MetricStats.GetSnapshots() {
+				if metric.Name() == "alerting.notification_errors" {
+					count++
+				}
+			}
+			So(count, ShouldEqual, 1)
+			So(GetOrRegCounter("alerting.notification_errors", "type", "email"), ShouldNotPointTo, other)
+		})
+	})
+}
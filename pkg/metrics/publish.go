@@ -55,6 +55,7 @@ func sendMetrics(settings *MetricSettings) {
 }
 
 func updateTotalStats() {
+	updateAlertStateStats()
 
 	// every interval also publish totals
 	metricPublishCounter++
@@ -73,6 +74,32 @@ func updateTotalStats() {
 	}
 }
 
+func updateAlertStateStats() {
+	alertStats := m.GetAlertStateStatsQuery{}
+	if err := bus.Dispatch(&alertStats); err != nil {
+		metricsLogger.Error("Failed to get alert state stats", "error", err)
+		return
+	}
+
+	gauges := map[m.AlertStateType]Gauge{
+		m.AlertStateAlerting:  M_Alerting_Alerts_Alerting,
+		m.AlertStateOK:        M_Alerting_Alerts_Ok,
+		m.AlertStatePaused:    M_Alerting_Alerts_Paused,
+		m.AlertStateNoData:    M_Alerting_Alerts_NoData,
+		m.AlertStatePending:   M_Alerting_Alerts_Pending,
+		m.AlertStateInhibited: M_Alerting_Alerts_Inhibited,
+	}
+
+	counts := make(map[m.AlertStateType]int64)
+	for _, stat := range alertStats.Result {
+		counts[stat.State] = stat.Count
+	}
+
+	for state, gauge := range gauges {
+		gauge.Update(counts[state])
+	}
+}
+
 func sendUsageStats() {
 	if !setting.ReportingEnabled {
 		return
@@ -5,6 +5,7 @@ import "sync"
 type Registry interface {
 	GetSnapshots() []Metric
 	Register(metric Metric)
+	Unregister(metric Metric)
 }
 
 // The standard implementation of a Registry is a mutex-protected map
@@ -27,6 +28,17 @@ func (r *StandardRegistry) Register(metric Metric) {
 	r.metrics = append(r.metrics, metric)
 }
 
+func (r *StandardRegistry) Unregister(metric Metric) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i, existing := range r.metrics {
+		if existing == metric {
+			r.metrics = append(r.metrics[:i], r.metrics[i+1:]...)
+			return
+		}
+	}
+}
+
 // Call the given function for each registered metric.
 func (r *StandardRegistry) GetSnapshots() []Metric {
 	metrics := make([]Metric, len(r.metrics))
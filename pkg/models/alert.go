@@ -14,11 +14,12 @@ type NoDataOption string
 type ExecutionErrorOption string
 
 const (
-	AlertStateNoData   AlertStateType = "no_data"
-	AlertStatePaused   AlertStateType = "paused"
-	AlertStateAlerting AlertStateType = "alerting"
-	AlertStateOK       AlertStateType = "ok"
-	AlertStatePending  AlertStateType = "pending"
+	AlertStateNoData    AlertStateType = "no_data"
+	AlertStatePaused    AlertStateType = "paused"
+	AlertStateAlerting  AlertStateType = "alerting"
+	AlertStateOK        AlertStateType = "ok"
+	AlertStatePending   AlertStateType = "pending"
+	AlertStateInhibited AlertStateType = "inhibited"
 )
 
 const (
@@ -36,10 +37,13 @@ const (
 var (
 	ErrCannotChangeStateOnPausedAlert error = fmt.Errorf("Cannot change state on pause alert")
 	ErrRequiresNewState               error = fmt.Errorf("update alert state requires a new state.")
+	ErrAlertNotFound                  error = fmt.Errorf("Alert not found")
+	ErrCannotAcknowledgeAlert         error = fmt.Errorf("Only firing alerts can be acknowledged")
+	ErrAlertManagedByDashboard        error = fmt.Errorf("Alert is managed by a dashboard, edit the dashboard panel instead")
 )
 
 func (s AlertStateType) IsValid() bool {
-	return s == AlertStateOK || s == AlertStateNoData || s == AlertStatePaused || s == AlertStatePending
+	return s == AlertStateOK || s == AlertStateNoData || s == AlertStatePaused || s == AlertStatePending || s == AlertStateInhibited
 }
 
 func (s NoDataOption) IsValid() bool {
@@ -77,6 +81,11 @@ type Alert struct {
 	NewStateDate time.Time
 	StateChanges int
 
+	Acknowledged bool
+	AckLogin     string
+	AckComment   string
+	AckDate      time.Time
+
 	Created time.Time
 	Updated time.Time
 
@@ -87,6 +96,12 @@ func (alert *Alert) ValidToSave() bool {
 	return alert.DashboardId != 0 && alert.OrgId != 0 && alert.PanelId != 0
 }
 
+// IsStandalone returns true for alerts managed through the alerts API
+// instead of being part of a dashboard panel.
+func (alert *Alert) IsStandalone() bool {
+	return alert.DashboardId == 0
+}
+
 func (alert *Alert) ShouldUpdateState(newState AlertStateType) bool {
 	return alert.State != newState
 }
@@ -124,8 +139,11 @@ type HeartBeat struct {
 	Created  time.Time
 }
 
+// HeartBeatCommand registers the server as alive and removes servers that
+// have not sent a heartbeat within Timeout.
 type HeartBeatCommand struct {
 	ServerId string
+	Timeout  time.Duration
 	Result   AlertingClusterInfo
 }
 
@@ -157,17 +175,53 @@ type SetAlertStateCommand struct {
 	EvalData *simplejson.Json
 
 	Timestamp time.Time
+	Result    *Alert
+}
+
+type CreateAlertCommand struct {
+	Alert *Alert
+
+	Result *Alert
+}
+
+type UpdateAlertCommand struct {
+	Alert *Alert
+
+	Result *Alert
+}
+
+// SaveImportedAlertRulesCommand saves imported notification channels and
+// standalone alert rules in one transaction. Channels are saved first, so
+// alerts can reference channels created by the same command by name.
+type SaveImportedAlertRulesCommand struct {
+	CreateNotifications []*CreateAlertNotificationCommand
+	UpdateNotifications []*UpdateAlertNotificationCommand
+	CreateAlerts        []*CreateAlertCommand
+	UpdateAlerts        []*UpdateAlertCommand
+}
+
+type SetAlertAcknowledgementCommand struct {
+	OrgId        int64
+	AlertId      int64
+	Acknowledged bool
+	Login        string
+	Comment      string
+
+	Result *Alert
+	// Changed is false when removing an acknowledgement the alert did not have
+	Changed bool
 }
 
 type DeleteAlertCommand struct {
 	AlertId int64
 }
 
-//Queries
+// Queries
 type GetAlertsQuery struct {
 	OrgId       int64
 	State       []string
 	DashboardId int64
+	FolderId    int64
 	PanelId     int64
 	Limit       int64
 
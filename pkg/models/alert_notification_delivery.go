@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+var ErrNotificationAlreadySent = errors.New("Notification for this alert state change already sent")
+
+type AlertNotificationDeliveryStatus string
+
+const (
+	DeliveryStatusPending    AlertNotificationDeliveryStatus = "pending"
+	DeliveryStatusDelivered  AlertNotificationDeliveryStatus = "delivered"
+	DeliveryStatusFailed     AlertNotificationDeliveryStatus = "failed"
+	DeliveryStatusSuperseded AlertNotificationDeliveryStatus = "superseded"
+)
+
+// AlertNotificationDelivery is an outbox entry for one notification
+// sent for an alert state change to a notification channel.
+type AlertNotificationDelivery struct {
+	Id          int64                           `json:"id"`
+	OrgId       int64                           `json:"-"`
+	AlertId     int64                           `json:"alertId"`
+	NotifierId  int64                           `json:"notifierId"`
+	State       AlertStateType                  `json:"state"`
+	Status      AlertNotificationDeliveryStatus `json:"status"`
+	Attempts    int                             `json:"attempts"`
+	LastError   string                          `json:"lastError"`
+	Payload     *simplejson.Json                `json:"payload"`
+	NextAttempt time.Time                       `json:"nextAttempt"`
+	DedupKey    string                          `json:"-"`
+	Created     time.Time                       `json:"created"`
+	Updated     time.Time                       `json:"updated"`
+}
+
+// CreateAlertNotificationDeliveryCommand supersedes the pending deliveries
+// of older state changes of the alert to the same notifier.
+type CreateAlertNotificationDeliveryCommand struct {
+	OrgId       int64
+	AlertId     int64
+	NotifierId  int64
+	State       AlertStateType
+	Payload     *simplejson.Json
+	NextAttempt time.Time
+	// DedupKey identifies the alert state change, only one delivery per
+	// notifier is created for each key.
+	DedupKey string
+
+	Result *AlertNotificationDelivery
+}
+
+type UpdateAlertNotificationDeliveryCommand struct {
+	Id          int64
+	Status      AlertNotificationDeliveryStatus
+	Attempts    int
+	LastError   string
+	NextAttempt time.Time
+}
+
+// DeleteOldAlertNotificationDeliveriesCommand deletes the deliveries that
+// are no longer pending and were last updated before OlderThan.
+type DeleteOldAlertNotificationDeliveriesCommand struct {
+	OlderThan time.Time
+
+	DeletedRows int64
+}
+
+type GetPendingAlertNotificationDeliveriesQuery struct {
+	Before time.Time
+	Limit  int
+
+	Result []*AlertNotificationDelivery
+}
+
+type GetAlertNotificationDeliveriesQuery struct {
+	OrgId      int64
+	NotifierId int64
+	AlertId    int64
+	Limit      int
+
+	Result []*AlertNotificationDelivery
+}
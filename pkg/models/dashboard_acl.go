@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type PermissionType int
+
+const (
+	PERMISSION_VIEW PermissionType = 1 << iota
+	PERMISSION_EDIT
+	PERMISSION_ADMIN
+)
+
+func (p PermissionType) IsValid() bool {
+	return p == PERMISSION_VIEW || p == PERMISSION_EDIT || p == PERMISSION_ADMIN
+}
+
+func (p PermissionType) String() string {
+	names := map[PermissionType]string{
+		PERMISSION_VIEW:  "View",
+		PERMISSION_EDIT:  "Edit",
+		PERMISSION_ADMIN: "Admin",
+	}
+	return names[p]
+}
+
+// Typed errors
+var (
+	ErrDashboardAclInvalid   = errors.New("An ACL entry needs a valid permission and either a user, a team or a role")
+	ErrDashboardAclDuplicate = errors.New("A user, team or role can only have one ACL entry per dashboard")
+	ErrDashboardAccessDenied = errors.New("Access denied to this dashboard")
+)
+
+// DashboardAcl grants a permission on a dashboard or folder to a user, to
+// the members of a team or to all users with an org role. Dashboards without an ACL inherit the
+// ACL of their folder, the org role defaults apply if neither has one.
+type DashboardAcl struct {
+	Id          int64
+	OrgId       int64
+	DashboardId int64
+
+	UserId     int64
+	TeamId     int64
+	Role       *RoleType // pointer to be nullable
+	Permission PermissionType
+
+	Created time.Time
+	Updated time.Time
+}
+
+type DashboardAclInfoDTO struct {
+	Id          int64     `json:"id"`
+	OrgId       int64     `json:"-"`
+	DashboardId int64     `json:"dashboardId"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+
+	UserId         int64          `json:"userId"`
+	UserLogin      string         `json:"userLogin"`
+	UserEmail      string         `json:"userEmail"`
+	TeamId         int64          `json:"teamId"`
+	Team           string         `json:"team"`
+	Role           *RoleType      `json:"role,omitempty"`
+	Permission     PermissionType `json:"permission"`
+	PermissionName string         `json:"permissionName"`
+	Inherited      bool           `json:"inherited"`
+}
+
+//
+// COMMANDS
+//
+
+// UpdateDashboardAclCommand replaces all ACL entries of a dashboard. The
+// dashboard keeps an ACL when all entries are removed, so that only org
+// admins have access to it.
+type UpdateDashboardAclCommand struct {
+	DashboardId int64
+	OrgId       int64
+	Items       []*DashboardAcl
+}
+
+//
+// QUERIES
+//
+
+// GetDashboardAclInfoListQuery returns the ACL entries that apply to a
+// dashboard, which are the entries of its folder if it has no ACL.
+// ResultHasAcl is false if neither has an ACL.
+type GetDashboardAclInfoListQuery struct {
+	DashboardId int64
+	OrgId       int64
+
+	Result       []*DashboardAclInfoDTO
+	ResultHasAcl bool
+}
+
+// GetOrgDashboardAclsQuery returns all ACL entries of an org without user
+// details, together with the ids of the dashboards and folders that have
+// an ACL.
+type GetOrgDashboardAclsQuery struct {
+	OrgId int64
+
+	Result       []*DashboardAclInfoDTO
+	ResultHasAcl map[int64]bool
+}
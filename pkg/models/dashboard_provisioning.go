@@ -0,0 +1,52 @@
+package models
+
+import "errors"
+
+var (
+	ErrDashboardProvisioned    = errors.New("Cannot save or delete a provisioned dashboard")
+	ErrDashboardNotProvisioned = errors.New("A dashboard with the same uid or title exists that was not provisioned")
+)
+
+// DashboardProvisioning records the file a provisioned dashboard was
+// read from. Updated is the modification time of the file as unix timestamp.
+type DashboardProvisioning struct {
+	Id             int64
+	DashboardId    int64
+	Name           string
+	ExternalId     string
+	AllowUiUpdates bool
+	Updated        int64
+}
+
+//
+// COMMANDS
+//
+
+// SaveProvisionedDashboardCommand saves a dashboard together with the
+// information about where it was provisioned from.
+type SaveProvisionedDashboardCommand struct {
+	DashboardCmd          *SaveDashboardCommand
+	DashboardProvisioning *DashboardProvisioning
+
+	Result *Dashboard
+}
+
+//
+// QUERIES
+//
+
+// GetProvisionedDashboardDataQuery returns the provisioning entries of a
+// provisioning config by its name.
+type GetProvisionedDashboardDataQuery struct {
+	Name string
+
+	Result []*DashboardProvisioning
+}
+
+// GetProvisionedDashboardDataByIdQuery returns the provisioning entry of a
+// dashboard, Result is nil when the dashboard is not provisioned.
+type GetProvisionedDashboardDataByIdQuery struct {
+	DashboardId int64
+
+	Result *DashboardProvisioning
+}
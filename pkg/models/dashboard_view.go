@@ -0,0 +1,28 @@
+package models
+
+// DashboardView counts how many times a dashboard has been viewed.
+type DashboardView struct {
+	Id          int64
+	DashboardId int64
+	OrgId       int64
+	Views       int64
+}
+
+//
+// COMMANDS
+//
+
+type IncrementDashboardViewsCommand struct {
+	DashboardId int64
+	OrgId       int64
+}
+
+//
+// QUERIES
+//
+
+// GetDashboardViewsQuery returns the view count of all dashboards, keyed by
+// dashboard id.
+type GetDashboardViewsQuery struct {
+	Result map[int64]int64
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gosimple/slug"
+	"github.com/grafana/grafana/pkg/components/dashschema"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -16,6 +17,13 @@ var (
 	ErrDashboardWithSameNameExists = errors.New("A dashboard with the same name already exists")
 	ErrDashboardVersionMismatch    = errors.New("The dashboard has been changed by someone else")
 	ErrDashboardTitleEmpty         = errors.New("Dashboard title cannot be empty")
+	ErrFolderNotFound              = errors.New("Folder not found")
+	ErrDashboardFolderNesting      = errors.New("A folder cannot be placed inside another folder")
+	ErrDashboardTypeMismatch       = errors.New("A dashboard cannot be changed to a folder or back")
+	ErrDashboardWithSameUIDExists  = errors.New("A dashboard with the same uid already exists")
+	ErrDashboardUidInOtherOrg      = errors.New("The dashboard uid is used by a dashboard in another organization")
+	ErrDashboardInvalidUid         = errors.New("uid contains illegal characters")
+	ErrDashboardUidToLong          = errors.New("uid to long. max 40 characters")
 )
 
 type UpdatePluginDashboardError struct {
@@ -36,11 +44,15 @@ var (
 // Dashboard model
 type Dashboard struct {
 	Id       int64
+	Uid      string
 	Slug     string
 	OrgId    int64
 	GnetId   int64
 	Version  int
 	PluginId string
+	FolderId int64
+	IsFolder bool
+	HasAcl   bool
 
 	Created time.Time
 	Updated time.Time
@@ -69,10 +81,15 @@ func (dash *Dashboard) GetTags() []string {
 	return dash.Data.Get("tags").MustStringArray()
 }
 
+// NewDashboardFromJson creates a dashboard from its json model, the json
+// is upgraded to the current schema version.
 func NewDashboardFromJson(data *simplejson.Json) *Dashboard {
+	dashschema.Migrate(data)
+
 	dash := &Dashboard{}
 	dash.Data = data
 	dash.Title = dash.Data.Get("title").MustString()
+	dash.Uid = dash.Data.Get("uid").MustString()
 	dash.UpdateSlug()
 
 	if id, err := dash.Data.Get("id").Float64(); err == nil {
@@ -106,6 +123,8 @@ func (cmd *SaveDashboardCommand) GetDashboardModel() *Dashboard {
 	dash.UpdatedBy = cmd.UserId
 	dash.OrgId = cmd.OrgId
 	dash.PluginId = cmd.PluginId
+	dash.FolderId = cmd.FolderId
+	dash.IsFolder = cmd.IsFolder
 	dash.UpdateSlug()
 	return dash
 }
@@ -130,8 +149,13 @@ type SaveDashboardCommand struct {
 	UserId    int64            `json:"userId"`
 	OrgId     int64            `json:"-"`
 	Overwrite bool             `json:"overwrite"`
+	Message   string           `json:"message"`
+	FolderId  int64            `json:"folderId"`
+	IsFolder  bool             `json:"isFolder"`
 	PluginId  string           `json:"-"`
 
+	RestoredFrom int `json:"-"`
+
 	Result *Dashboard
 }
 
@@ -140,12 +164,22 @@ type DeleteDashboardCommand struct {
 	OrgId int64
 }
 
+// MoveDashboardCommand moves a dashboard into a folder, or out of its
+// folder when FolderId is 0.
+type MoveDashboardCommand struct {
+	DashboardId int64 `json:"-"`
+	FolderId    int64 `json:"folderId"`
+	OrgId       int64 `json:"-"`
+}
+
 //
 // QUERIES
 //
 
 type GetDashboardQuery struct {
-	Slug  string
+	Slug  string // required if no Id or Uid is specified
+	Id    int64
+	Uid   string
 	OrgId int64
 
 	Result *Dashboard
@@ -166,13 +200,48 @@ type GetDashboardsQuery struct {
 	Result       []*Dashboard
 }
 
+// GetAllDashboardsQuery returns the dashboards and folders of all orgs.
+type GetAllDashboardsQuery struct {
+	Result []*Dashboard
+}
+
+// DashboardsState is the number of dashboards and the last time one of them
+// was updated, it changes whenever a dashboard is saved or deleted.
+type DashboardsState struct {
+	Count       int64
+	LastUpdated time.Time
+}
+
+// GetDashboardsStateQuery returns the state of the dashboards of all orgs.
+type GetDashboardsStateQuery struct {
+	Result *DashboardsState
+}
+
 type GetDashboardsByPluginIdQuery struct {
 	OrgId    int64
 	PluginId string
 	Result   []*Dashboard
 }
 
+type GetFoldersQuery struct {
+	OrgId  int64
+	Result []*Dashboard
+}
+
 type GetDashboardSlugByIdQuery struct {
 	Id     int64
 	Result string
 }
+
+// GetDashboardRefByIdQuery returns the uid and slug of a dashboard, used to
+// build links to it.
+type GetDashboardRefByIdQuery struct {
+	Id     int64
+	Result *DashboardRef
+}
+
+type DashboardRef struct {
+	Uid   string
+	Slug  string
+	Title string
+}
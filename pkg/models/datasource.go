@@ -25,6 +25,7 @@ const (
 var (
 	ErrDataSourceNotFound   = errors.New("Data source not found")
 	ErrDataSourceNameExists = errors.New("Data source with same name already exists")
+	ErrDatasourceIsReadOnly = errors.New("Data source is readonly. Can only be updated from configuration.")
 )
 
 type DsAccess string
@@ -48,6 +49,8 @@ type DataSource struct {
 	IsDefault         bool
 	JsonData          *simplejson.Json
 	SecureJsonData    securejsondata.SecureJsonData
+	ReadOnly          bool
+	Provisioned       bool
 
 	Created time.Time
 	Updated time.Time
@@ -94,7 +97,9 @@ type AddDataSourceCommand struct {
 	JsonData          *simplejson.Json  `json:"jsonData"`
 	SecureJsonData    map[string]string `json:"secureJsonData"`
 
-	OrgId int64 `json:"-"`
+	OrgId       int64 `json:"-"`
+	ReadOnly    bool  `json:"-"`
+	Provisioned bool  `json:"-"`
 
 	Result *DataSource
 }
@@ -116,8 +121,9 @@ type UpdateDataSourceCommand struct {
 	JsonData          *simplejson.Json  `json:"jsonData"`
 	SecureJsonData    map[string]string `json:"secureJsonData"`
 
-	OrgId int64 `json:"-"`
-	Id    int64 `json:"-"`
+	OrgId    int64 `json:"-"`
+	Id       int64 `json:"-"`
+	ReadOnly bool  `json:"-"`
 }
 
 type DeleteDataSourceByIdCommand struct {
@@ -130,6 +136,13 @@ type DeleteDataSourceByNameCommand struct {
 	OrgId int64
 }
 
+// ReleaseProvisionedDataSourceCommand turns a provisioned datasource that
+// was removed from the provisioning config into a regular one.
+type ReleaseProvisionedDataSourceCommand struct {
+	Id    int64
+	OrgId int64
+}
+
 // ---------------------
 // QUERIES
 
@@ -150,6 +163,12 @@ type GetDataSourceByNameQuery struct {
 	Result *DataSource
 }
 
+// GetProvisionedDataSourcesQuery returns the provisioned datasources of
+// all orgs.
+type GetProvisionedDataSourcesQuery struct {
+	Result []*DataSource
+}
+
 // ---------------------
 // EVENTS
 type DataSourceCreatedEvent struct {
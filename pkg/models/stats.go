@@ -21,6 +21,15 @@ type GetDataSourceStatsQuery struct {
 	Result []*DataSourceStats
 }
 
+type AlertStateStats struct {
+	Count int64
+	State AlertStateType
+}
+
+type GetAlertStateStatsQuery struct {
+	Result []*AlertStateStats
+}
+
 type AdminStats struct {
 	UserCount       int `json:"user_count"`
 	OrgCount        int `json:"org_count"`
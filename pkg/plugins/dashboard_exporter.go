@@ -0,0 +1,245 @@
+package plugins
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ExportDashboardCommand turns a dashboard into the format read by
+// ImportDashboard. The datasources it uses are replaced by __inputs and the
+// plugins it needs are listed in __requires.
+type ExportDashboardCommand struct {
+	Dashboard *simplejson.Json
+	OrgId     int64
+
+	Result *simplejson.Json
+}
+
+func init() {
+	bus.AddHandler("plugins", ExportDashboard)
+}
+
+func ExportDashboard(cmd *ExportDashboardCommand) error {
+	query := m.GetDataSourcesQuery{OrgId: cmd.OrgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return err
+	}
+
+	exporter := newDashTemplateExporter(query.Result)
+
+	result, err := exporter.Export(cmd.Dashboard)
+	if err != nil {
+		return err
+	}
+
+	cmd.Result = result
+	return nil
+}
+
+// DashTemplateExporter is the inverse of DashTemplateEvaluator, it produces
+// the same output as the dashboard export in the frontend.
+type DashTemplateExporter struct {
+	datasources       map[string]*m.DataSource
+	defaultDatasource *m.DataSource
+	variables         map[string]bool
+	inputs            []interface{}
+	inputNames        map[string]bool
+	requires          map[string]map[string]interface{}
+}
+
+func newDashTemplateExporter(datasources []*m.DataSource) *DashTemplateExporter {
+	exporter := &DashTemplateExporter{
+		datasources: make(map[string]*m.DataSource),
+		variables:   make(map[string]bool),
+		inputs:      make([]interface{}, 0),
+		inputNames:  make(map[string]bool),
+		requires:    make(map[string]map[string]interface{}),
+	}
+
+	for _, ds := range datasources {
+		exporter.datasources[ds.Name] = ds
+		if ds.IsDefault {
+			exporter.defaultDatasource = ds
+		}
+	}
+
+	return exporter
+}
+
+func (this *DashTemplateExporter) Export(dashboard *simplejson.Json) (*simplejson.Json, error) {
+	// work on a copy, the dashboard is owned by the caller
+	data, err := dashboard.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := simplejson.NewJson(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Set("id", nil)
+
+	variables := result.Get("templating").Get("list").MustArray()
+	for _, variable := range variables {
+		this.variables[simplejson.NewFromAny(variable).Get("name").MustString()] = true
+	}
+
+	for _, row := range result.Get("rows").MustArray() {
+		for _, item := range simplejson.NewFromAny(row).Get("panels").MustArray() {
+			panel := simplejson.NewFromAny(item)
+			if _, exists := panel.CheckGet("datasource"); exists {
+				this.templateizeDatasourceUsage(panel)
+			}
+
+			for _, item := range panel.Get("targets").MustArray() {
+				target := simplejson.NewFromAny(item)
+				if _, exists := target.CheckGet("datasource"); exists {
+					this.templateizeDatasourceUsage(target)
+				}
+			}
+
+			if panelDef, exists := Panels[panel.Get("type").MustString()]; exists {
+				this.addRequire("panel", panelDef.Id, panelDef.Name, panelDef.Info.Version)
+			}
+		}
+	}
+
+	for _, item := range variables {
+		variable := simplejson.NewFromAny(item)
+		if variable.Get("type").MustString() == "query" {
+			this.templateizeDatasourceUsage(variable)
+			variable.Set("options", make([]interface{}, 0))
+			variable.Set("current", make(map[string]interface{}))
+			variable.Set("refresh", 1)
+		}
+	}
+
+	for _, item := range result.Get("annotations").Get("list").MustArray() {
+		this.templateizeDatasourceUsage(simplejson.NewFromAny(item))
+	}
+
+	this.addRequire("grafana", "grafana", "Grafana", setting.BuildVersion)
+
+	// constants are inputs too, after the datasources
+	for _, item := range variables {
+		variable := simplejson.NewFromAny(item)
+		if variable.Get("type").MustString() == "constant" {
+			this.templateizeConstant(variable)
+		}
+	}
+
+	result.Set("__inputs", this.inputs)
+	result.Set("__requires", this.sortedRequires())
+
+	return result, nil
+}
+
+// templateizeDatasourceUsage replaces the datasource of a panel, target,
+// variable or annotation with an input. Datasources that are template
+// variables, built in or unknown are kept.
+func (this *DashTemplateExporter) templateizeDatasourceUsage(obj *simplejson.Json) {
+	var ds *m.DataSource
+
+	if name, ok := obj.Get("datasource").Interface().(string); ok {
+		if strings.HasPrefix(name, "$") && this.variables[name[1:]] {
+			return
+		}
+		ds = this.datasources[name]
+	} else {
+		ds = this.defaultDatasource
+	}
+
+	if ds == nil {
+		return
+	}
+
+	pluginName, version := ds.Type, "1.0.0"
+	if plugin, exists := DataSources[ds.Type]; exists {
+		pluginName = plugin.Name
+		if plugin.Info.Version != "" {
+			version = plugin.Info.Version
+		}
+	}
+
+	// only the first space is replaced, like the frontend export does
+	refName := "DS_" + strings.ToUpper(strings.Replace(ds.Name, " ", "_", 1))
+	if !this.inputNames[refName] {
+		this.inputNames[refName] = true
+		this.inputs = append(this.inputs, map[string]interface{}{
+			"name":        refName,
+			"label":       ds.Name,
+			"description": "",
+			"type":        "datasource",
+			"pluginId":    ds.Type,
+			"pluginName":  pluginName,
+		})
+	}
+
+	obj.Set("datasource", "${"+refName+"}")
+	this.addRequire("datasource", ds.Type, pluginName, version)
+}
+
+func (this *DashTemplateExporter) templateizeConstant(variable *simplejson.Json) {
+	name := variable.Get("name").MustString()
+	refName := "VAR_" + strings.ToUpper(strings.Replace(name, " ", "_", 1))
+
+	this.inputs = append(this.inputs, map[string]interface{}{
+		"name":        refName,
+		"type":        "constant",
+		"label":       variable.Get("label").MustString(name),
+		"value":       variable.Get("current").Get("value").Interface(),
+		"description": "",
+	})
+
+	query := "${" + refName + "}"
+	current := map[string]interface{}{"value": query, "text": query}
+
+	options := variable.Get("options").MustArray()
+	if len(options) == 0 {
+		options = append(options, current)
+	} else {
+		options[0] = current
+	}
+
+	variable.Set("query", query)
+	variable.Set("current", current)
+	variable.Set("options", options)
+}
+
+func (this *DashTemplateExporter) addRequire(requireType string, id string, name string, version string) {
+	this.requires[requireType+id] = map[string]interface{}{
+		"type":    requireType,
+		"id":      id,
+		"name":    name,
+		"version": version,
+	}
+}
+
+func (this *DashTemplateExporter) sortedRequires() []interface{} {
+	keys := make([]string, 0, len(this.requires))
+	for key := range this.requires {
+		keys = append(keys, key)
+	}
+
+	// sorted by id, requires of different types with the same id by type
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := this.requires[keys[i]], this.requires[keys[j]]
+		if a["id"] != b["id"] {
+			return a["id"].(string) < b["id"].(string)
+		}
+		return keys[i] < keys[j]
+	})
+
+	result := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, this.requires[key])
+	}
+
+	return result
+}
@@ -0,0 +1,149 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDashboardExport(t *testing.T) {
+
+	Convey("When exporting a dashboard", t, func() {
+		setting.BuildVersion = "4.6.0"
+		DataSources = map[string]*DataSourcePlugin{
+			"graphite":   {FrontendPluginBase: FrontendPluginBase{PluginBase: PluginBase{Id: "graphite", Name: "Graphite", Info: PluginInfo{Version: "1.0.0"}}}},
+			"prometheus": {FrontendPluginBase: FrontendPluginBase{PluginBase: PluginBase{Id: "prometheus", Name: "Prometheus"}}},
+		}
+		Panels = map[string]*PanelPlugin{
+			"graph": {FrontendPluginBase: FrontendPluginBase{PluginBase: PluginBase{Id: "graph", Name: "Graph", Info: PluginInfo{Version: "5.0.0"}}}},
+		}
+
+		bus.AddHandler("test", func(query *m.GetDataSourcesQuery) error {
+			query.Result = []*m.DataSource{
+				{Name: "graphite prod", Type: "graphite", IsDefault: true},
+				{Name: "prom", Type: "prometheus"},
+			}
+			return nil
+		})
+
+		dashboard, _ := simplejson.NewJson([]byte(`{
+			"id": 12,
+			"uid": "abc",
+			"title": "Exported",
+			"rows": [
+				{
+					"panels": [
+						{"type": "graph", "datasource": null, "targets": [{"target": "a"}]},
+						{"type": "graph", "datasource": "-- Mixed --", "targets": [{"datasource": "prom", "expr": "up"}]},
+						{"type": "singlestat", "datasource": "$ds"}
+					]
+				}
+			],
+			"templating": {
+				"list": [
+					{"name": "ds", "type": "datasource", "query": "graphite"},
+					{"name": "host", "type": "query", "datasource": "graphite prod", "options": [{"text": "a"}], "current": {"text": "a"}, "refresh": 2},
+					{"name": "env", "type": "constant", "query": "prod", "current": {"value": "prod", "text": "prod"}, "options": [{"value": "prod", "text": "prod"}]}
+				]
+			},
+			"annotations": {
+				"list": [
+					{"name": "Annotations & Alerts", "datasource": "-- Grafana --"},
+					{"name": "Deploys", "datasource": "prom"}
+				]
+			}
+		}`))
+
+		cmd := ExportDashboardCommand{Dashboard: dashboard, OrgId: 1}
+		err := ExportDashboard(&cmd)
+		So(err, ShouldBeNil)
+
+		result := cmd.Result
+
+		Convey("should replace the datasources with inputs", func() {
+			So(result.Get("id").Interface(), ShouldBeNil)
+			So(result.Get("uid").MustString(), ShouldEqual, "abc")
+
+			panels := result.Get("rows").GetIndex(0).Get("panels")
+			So(panels.GetIndex(0).Get("datasource").MustString(), ShouldEqual, "${DS_GRAPHITE_PROD}")
+			So(panels.GetIndex(1).Get("datasource").MustString(), ShouldEqual, "-- Mixed --")
+			So(panels.GetIndex(1).Get("targets").GetIndex(0).Get("datasource").MustString(), ShouldEqual, "${DS_PROM}")
+			So(panels.GetIndex(2).Get("datasource").MustString(), ShouldEqual, "$ds")
+
+			annotations := result.Get("annotations").Get("list")
+			So(annotations.GetIndex(0).Get("datasource").MustString(), ShouldEqual, "-- Grafana --")
+			So(annotations.GetIndex(1).Get("datasource").MustString(), ShouldEqual, "${DS_PROM}")
+		})
+
+		Convey("should reset query variables", func() {
+			variable := result.Get("templating").Get("list").GetIndex(1)
+			So(variable.Get("datasource").MustString(), ShouldEqual, "${DS_GRAPHITE_PROD}")
+			So(len(variable.Get("options").MustArray()), ShouldEqual, 0)
+			So(len(variable.Get("current").MustMap()), ShouldEqual, 0)
+			So(variable.Get("refresh").MustInt(), ShouldEqual, 1)
+		})
+
+		Convey("should add inputs for datasources and constants", func() {
+			inputs := result.Get("__inputs")
+			So(len(inputs.MustArray()), ShouldEqual, 3)
+
+			So(inputs.GetIndex(0).Get("name").MustString(), ShouldEqual, "DS_GRAPHITE_PROD")
+			So(inputs.GetIndex(0).Get("label").MustString(), ShouldEqual, "graphite prod")
+			So(inputs.GetIndex(0).Get("type").MustString(), ShouldEqual, "datasource")
+			So(inputs.GetIndex(0).Get("pluginId").MustString(), ShouldEqual, "graphite")
+			So(inputs.GetIndex(0).Get("pluginName").MustString(), ShouldEqual, "Graphite")
+			So(inputs.GetIndex(1).Get("name").MustString(), ShouldEqual, "DS_PROM")
+
+			So(inputs.GetIndex(2).Get("name").MustString(), ShouldEqual, "VAR_ENV")
+			So(inputs.GetIndex(2).Get("type").MustString(), ShouldEqual, "constant")
+			So(inputs.GetIndex(2).Get("value").MustString(), ShouldEqual, "prod")
+
+			constant := result.Get("templating").Get("list").GetIndex(2)
+			So(constant.Get("query").MustString(), ShouldEqual, "${VAR_ENV}")
+			So(constant.Get("current").Get("value").MustString(), ShouldEqual, "${VAR_ENV}")
+			So(constant.Get("options").GetIndex(0).Get("text").MustString(), ShouldEqual, "${VAR_ENV}")
+		})
+
+		Convey("should add requires sorted by id", func() {
+			requires := result.Get("__requires")
+			So(len(requires.MustArray()), ShouldEqual, 4)
+
+			So(requires.GetIndex(0).Get("id").MustString(), ShouldEqual, "grafana")
+			So(requires.GetIndex(0).Get("version").MustString(), ShouldEqual, "4.6.0")
+			So(requires.GetIndex(1).Get("id").MustString(), ShouldEqual, "graph")
+			So(requires.GetIndex(1).Get("type").MustString(), ShouldEqual, "panel")
+			So(requires.GetIndex(1).Get("version").MustString(), ShouldEqual, "5.0.0")
+			So(requires.GetIndex(2).Get("id").MustString(), ShouldEqual, "graphite")
+			So(requires.GetIndex(3).Get("id").MustString(), ShouldEqual, "prometheus")
+			So(requires.GetIndex(3).Get("version").MustString(), ShouldEqual, "1.0.0")
+		})
+
+		Convey("should not change the dashboard of the caller", func() {
+			So(dashboard.Get("id").MustInt(), ShouldEqual, 12)
+			So(dashboard.Get("rows").GetIndex(0).Get("panels").GetIndex(0).Get("datasource").Interface(), ShouldBeNil)
+		})
+
+		Convey("should import again with the inputs", func() {
+			evaluator := &DashTemplateEvaluator{
+				template: result,
+				inputs: []ImportDashboardInput{
+					{Name: "DS_GRAPHITE_PROD", Type: "datasource", Value: "graphite"},
+					{Name: "DS_PROM", Type: "datasource", Value: "prometheus"},
+					{Name: "VAR_ENV", Type: "constant", Value: "staging"},
+				},
+			}
+
+			imported, err := evaluator.Eval()
+			So(err, ShouldBeNil)
+
+			panels := imported.Get("rows").GetIndex(0).Get("panels")
+			So(panels.GetIndex(0).Get("datasource").MustString(), ShouldEqual, "graphite")
+			So(panels.GetIndex(1).Get("targets").GetIndex(0).Get("datasource").MustString(), ShouldEqual, "prometheus")
+			So(imported.Get("templating").Get("list").GetIndex(2).Get("query").MustString(), ShouldEqual, "staging")
+		})
+	})
+}
@@ -42,12 +42,48 @@ func init() {
 }
 
 func ImportDashboard(cmd *ImportDashboardCommand) error {
+	dashboard, saveCmd, err := getImportSaveCommand(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := bus.Dispatch(saveCmd); err != nil {
+		return err
+	}
+
+	cmd.Result = &PluginDashboardInfoDTO{
+		PluginId:         cmd.PluginId,
+		Title:            dashboard.Title,
+		Path:             cmd.Path,
+		Revision:         dashboard.Data.Get("revision").MustInt64(1),
+		ImportedUri:      "db/" + saveCmd.Result.Slug,
+		ImportedRevision: dashboard.Data.Get("revision").MustInt64(1),
+		Imported:         true,
+	}
+
+	return nil
+}
+
+// GetImportedDashboard returns the dashboard that the import would save,
+// so that permissions can be checked before importing it.
+func GetImportedDashboard(cmd *ImportDashboardCommand) (*m.Dashboard, error) {
+	_, saveCmd, err := getImportSaveCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return saveCmd.GetDashboardModel(), nil
+}
+
+// getImportSaveCommand returns the dashboard template and the command that
+// saves it with the inputs filled in.
+func getImportSaveCommand(cmd *ImportDashboardCommand) (*m.Dashboard, *m.SaveDashboardCommand, error) {
 	var dashboard *m.Dashboard
 	var err error
 
 	if cmd.PluginId != "" {
 		if dashboard, err = loadPluginDashboard(cmd.PluginId, cmd.Path); err != nil {
-			return err
+			return nil, nil, err
 		}
 	} else {
 		dashboard = m.NewDashboardFromJson(cmd.Dashboard)
@@ -60,10 +96,10 @@ func ImportDashboard(cmd *ImportDashboardCommand) error {
 
 	generatedDash, err := evaluator.Eval()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	saveCmd := m.SaveDashboardCommand{
+	saveCmd := &m.SaveDashboardCommand{
 		Dashboard: generatedDash,
 		OrgId:     cmd.OrgId,
 		UserId:    cmd.UserId,
@@ -71,21 +107,7 @@ func ImportDashboard(cmd *ImportDashboardCommand) error {
 		PluginId:  cmd.PluginId,
 	}
 
-	if err := bus.Dispatch(&saveCmd); err != nil {
-		return err
-	}
-
-	cmd.Result = &PluginDashboardInfoDTO{
-		PluginId:         cmd.PluginId,
-		Title:            dashboard.Title,
-		Path:             cmd.Path,
-		Revision:         dashboard.Data.Get("revision").MustInt64(1),
-		ImportedUri:      "db/" + saveCmd.Result.Slug,
-		ImportedRevision: dashboard.Data.Get("revision").MustInt64(1),
-		Imported:         true,
-	}
-
-	return nil
+	return dashboard, saveCmd, nil
 }
 
 type DashTemplateEvaluator struct {
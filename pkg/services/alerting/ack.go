@@ -0,0 +1,132 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/annotations"
+)
+
+// AcknowledgeAlertCommand acknowledges a firing alert, or removes the
+// acknowledgement when Acknowledged is false.
+type AcknowledgeAlertCommand struct {
+	OrgId        int64
+	AlertId      int64
+	UserId       int64
+	Login        string
+	Comment      string
+	Acknowledged bool
+
+	Result *m.Alert
+}
+
+// Acknowledgement describes who acknowledged an alert and why.
+type Acknowledgement struct {
+	Acknowledged bool
+	Login        string
+	Comment      string
+	Date         time.Time
+}
+
+func init() {
+	bus.AddHandler("alerting", handleAcknowledgeAlertCommand)
+}
+
+func handleAcknowledgeAlertCommand(cmd *AcknowledgeAlertCommand) error {
+	logger := log.New("alerting.ack")
+
+	setCmd := &m.SetAlertAcknowledgementCommand{
+		OrgId:        cmd.OrgId,
+		AlertId:      cmd.AlertId,
+		Acknowledged: cmd.Acknowledged,
+		Login:        cmd.Login,
+		Comment:      cmd.Comment,
+	}
+
+	if err := bus.Dispatch(setCmd); err != nil {
+		return err
+	}
+
+	alert := setCmd.Result
+	cmd.Result = alert
+
+	// removing an acknowledgement the alert never had changes nothing
+	if !setCmd.Changed {
+		return nil
+	}
+
+	ack := &Acknowledgement{
+		Acknowledged: cmd.Acknowledged,
+		Login:        cmd.Login,
+		Comment:      cmd.Comment,
+		Date:         time.Now(),
+	}
+
+	// acknowledgements are kept apart from the alert state history
+	item := annotations.Item{
+		OrgId:       alert.OrgId,
+		DashboardId: alert.DashboardId,
+		PanelId:     alert.PanelId,
+		Type:        annotations.AlertAckType,
+		AlertId:     alert.Id,
+		UserId:      cmd.UserId,
+		Title:       alert.Name,
+		Text:        ack.Text(),
+		Epoch:       ack.Date.Unix(),
+		Data: simplejson.NewFromAny(map[string]interface{}{
+			"acknowledged": cmd.Acknowledged,
+			"login":        cmd.Login,
+			"comment":      cmd.Comment,
+		}),
+	}
+
+	if err := annotations.GetRepository().Save(&item); err != nil {
+		logger.Error("Failed to save annotation for acknowledgement", "error", err)
+	}
+
+	rule, err := NewRuleFromDBAlert(alert)
+	if err != nil {
+		logger.Error("Failed to load rule for acknowledgement notification", "alertId", alert.Id, "error", err)
+		return nil
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), alertTimeout)
+	defer cancelFn()
+
+	evalContext := NewEvalContext(ctx, rule)
+	evalContext.Firing = alert.State == m.AlertStateAlerting
+	evalContext.Acknowledgement = ack
+
+	// the alert state change stored the matches that made the alert fire
+	if alert.EvalData != nil {
+		if matches, err := alert.EvalData.MarshalJSON(); err == nil {
+			json.Unmarshal(matches, &evalContext.EvalMatches)
+		}
+	}
+
+	if err := NewNotificationService().SendAcknowledgement(evalContext); err != nil {
+		logger.Error("Failed to send acknowledgement notifications", "alertId", alert.Id, "error", err)
+	}
+
+	return nil
+}
+
+// Text describes the acknowledgement for annotations and notifications.
+func (a *Acknowledgement) Text() string {
+	text := fmt.Sprintf("Acknowledged by %s", a.Login)
+	if !a.Acknowledged {
+		text = fmt.Sprintf("Acknowledgement removed by %s", a.Login)
+	}
+
+	if a.Comment != "" {
+		text += ": " + a.Comment
+	}
+
+	return text
+}
@@ -0,0 +1,110 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeAnnotationsRepo struct {
+	items []*annotations.Item
+}
+
+func (r *fakeAnnotationsRepo) Save(item *annotations.Item) error {
+	r.items = append(r.items, item)
+	return nil
+}
+func (r *fakeAnnotationsRepo) Update(item *annotations.Item) error { return nil }
+func (r *fakeAnnotationsRepo) Find(query *annotations.ItemQuery) ([]*annotations.Item, error) {
+	return r.items, nil
+}
+func (r *fakeAnnotationsRepo) Delete(params *annotations.DeleteParams) error { return nil }
+
+type ackTestNotifier struct {
+	FakeNotifier
+	acks []*Acknowledgement
+}
+
+func (n *ackTestNotifier) NotifyAcknowledgement(evalContext *EvalContext) error {
+	n.acks = append(n.acks, evalContext.Acknowledgement)
+	return nil
+}
+
+func TestAlertAcknowledgement(t *testing.T) {
+	Convey("Alert acknowledgement", t, func() {
+		repo := &fakeAnnotationsRepo{}
+		annotations.SetRepository(repo)
+
+		notifier := &ackTestNotifier{FakeNotifier: FakeNotifier{FakeMatchResult: true}}
+		RegisterNotifier(&NotifierPlugin{
+			Type: "ack-test",
+			Factory: func(model *m.AlertNotification) (Notifier, error) {
+				return notifier, nil
+			},
+		})
+
+		RegisterCondition("ack-test", func(model *simplejson.Json, index int) (Condition, error) {
+			return &conditionStub{}, nil
+		})
+
+		bus.AddHandler("test", func(cmd *m.SetAlertAcknowledgementCommand) error {
+			// the alert in this test has no acknowledgement to remove
+			cmd.Changed = cmd.Acknowledged
+			cmd.Result = &m.Alert{
+				Id:           cmd.AlertId,
+				OrgId:        cmd.OrgId,
+				Name:         "cpu",
+				State:        m.AlertStateAlerting,
+				Acknowledged: cmd.Acknowledged,
+				AckLogin:     cmd.Login,
+				AckComment:   cmd.Comment,
+				EvalData:     simplejson.NewFromAny([]interface{}{map[string]interface{}{"metric": "cpu", "value": 99}}),
+				Settings: simplejson.NewFromAny(map[string]interface{}{
+					"conditions":    []interface{}{map[string]interface{}{"type": "ack-test"}},
+					"notifications": []interface{}{map[string]interface{}{"id": 1}},
+				}),
+			}
+			return nil
+		})
+
+		bus.AddHandler("test", func(query *m.GetAlertNotificationsToSendQuery) error {
+			query.Result = []*m.AlertNotification{{Id: 1, OrgId: query.OrgId, Type: "ack-test"}}
+			return nil
+		})
+
+		Convey("Should record annotation and notify", func() {
+			cmd := &AcknowledgeAlertCommand{OrgId: 1, AlertId: 2, UserId: 3, Login: "admin", Comment: "on it", Acknowledged: true}
+			err := bus.Dispatch(cmd)
+			So(err, ShouldBeNil)
+			So(cmd.Result.Acknowledged, ShouldBeTrue)
+
+			So(len(repo.items), ShouldEqual, 1)
+			So(repo.items[0].AlertId, ShouldEqual, 2)
+			So(repo.items[0].UserId, ShouldEqual, 3)
+			So(repo.items[0].Text, ShouldEqual, "Acknowledged by admin: on it")
+			So(repo.items[0].Type, ShouldEqual, annotations.AlertAckType)
+			So(repo.items[0].NewState, ShouldEqual, "")
+
+			So(len(notifier.acks), ShouldEqual, 1)
+			So(notifier.acks[0].Login, ShouldEqual, "admin")
+		})
+
+		Convey("Should do nothing when removing a missing acknowledgement", func() {
+			cmd := &AcknowledgeAlertCommand{OrgId: 1, AlertId: 2, UserId: 3, Login: "admin", Acknowledged: false}
+			err := bus.Dispatch(cmd)
+			So(err, ShouldBeNil)
+
+			So(len(repo.items), ShouldEqual, 0)
+			So(len(notifier.acks), ShouldEqual, 0)
+		})
+
+		Convey("Should describe removed acknowledgement", func() {
+			ack := &Acknowledgement{Acknowledged: false, Login: "admin"}
+			So(ack.Text(), ShouldEqual, "Acknowledgement removed by admin")
+		})
+	})
+}
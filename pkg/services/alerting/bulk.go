@@ -0,0 +1,138 @@
+package alerting
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
+)
+
+const (
+	BulkActionPause  = "pause"
+	BulkActionResume = "resume"
+	BulkActionDelete = "delete"
+)
+
+// BulkAlertsCommand pauses, resumes or deletes all alerts of a dashboard,
+// of the dashboards in a folder or with the given tags. Only standalone
+// alerts can be deleted, alerts of dashboards are skipped. When a user is
+// given, alerts of dashboards the user cannot edit are not selected.
+type BulkAlertsCommand struct {
+	OrgId        int64
+	SignedInUser *m.SignedInUser
+	Action       string
+	DashboardId  int64
+	FolderId     int64
+	Tags         map[string]string
+
+	Result *BulkAlertsResult
+}
+
+type BulkAlertsResult struct {
+	AlertIds []int64 `json:"alertIds"`
+	Skipped  []int64 `json:"skipped"`
+}
+
+func init() {
+	bus.AddHandler("alerting", handleBulkAlertsCommand)
+}
+
+func handleBulkAlertsCommand(cmd *BulkAlertsCommand) error {
+	if cmd.Action != BulkActionPause && cmd.Action != BulkActionResume && cmd.Action != BulkActionDelete {
+		return ValidationError{Reason: "Invalid bulk action " + cmd.Action}
+	}
+
+	if cmd.DashboardId == 0 && cmd.FolderId == 0 && len(cmd.Tags) == 0 {
+		return ValidationError{Reason: "Bulk actions require a dashboard, folder or tags to select alerts"}
+	}
+
+	query := &m.GetAlertsQuery{OrgId: cmd.OrgId, DashboardId: cmd.DashboardId, FolderId: cmd.FolderId}
+	if err := bus.Dispatch(query); err != nil {
+		return err
+	}
+
+	alerts, err := filterEditableAlerts(cmd.SignedInUser, query.Result)
+	if err != nil {
+		return err
+	}
+
+	result := &BulkAlertsResult{
+		AlertIds: make([]int64, 0),
+		Skipped:  make([]int64, 0),
+	}
+
+	for _, alert := range alerts {
+		if !TagsMatch(cmd.Tags, alertTags(alert)) {
+			continue
+		}
+
+		if cmd.Action == BulkActionDelete && !alert.IsStandalone() {
+			result.Skipped = append(result.Skipped, alert.Id)
+			continue
+		}
+
+		result.AlertIds = append(result.AlertIds, alert.Id)
+	}
+
+	cmd.Result = result
+	if len(result.AlertIds) == 0 {
+		return nil
+	}
+
+	if cmd.Action == BulkActionDelete {
+		for _, id := range result.AlertIds {
+			if err := bus.Dispatch(&m.DeleteAlertCommand{AlertId: id}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return bus.Dispatch(&m.PauseAlertCommand{
+		OrgId:    cmd.OrgId,
+		AlertIds: result.AlertIds,
+		Paused:   cmd.Action == BulkActionPause,
+	})
+}
+
+// filterEditableAlerts removes the alerts of dashboards the user is not
+// allowed to edit. Standalone alerts have no dashboard and are kept.
+func filterEditableAlerts(user *m.SignedInUser, alerts []*m.Alert) ([]*m.Alert, error) {
+	if user == nil {
+		return alerts, nil
+	}
+
+	dashboardIds := make([]int64, 0)
+	for _, alert := range alerts {
+		if alert.DashboardId != 0 {
+			dashboardIds = append(dashboardIds, alert.DashboardId)
+		}
+	}
+
+	if len(dashboardIds) == 0 {
+		return alerts, nil
+	}
+
+	dashQuery := &m.GetDashboardsQuery{DashboardIds: dashboardIds}
+	if err := bus.Dispatch(dashQuery); err != nil {
+		return nil, err
+	}
+
+	dashboards := make(map[int64]int64)
+	for _, dash := range dashQuery.Result {
+		dashboards[dash.Id] = dash.FolderId
+	}
+
+	editable, err := guardian.FilterDashboards(user, m.PERMISSION_EDIT, dashboards)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*m.Alert, 0)
+	for _, alert := range alerts {
+		if alert.DashboardId == 0 || editable[alert.DashboardId] {
+			filtered = append(filtered, alert)
+		}
+	}
+
+	return filtered, nil
+}
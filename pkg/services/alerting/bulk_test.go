@@ -0,0 +1,102 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBulkAlerts(t *testing.T) {
+	Convey("Bulk alert actions", t, func() {
+		opsTags, _ := simplejson.NewJson([]byte(`{"tags": {"team": "ops"}}`))
+		alerts := []*m.Alert{
+			{Id: 1, OrgId: 1, Settings: opsTags},
+			{Id: 2, OrgId: 1, DashboardId: 7, Settings: opsTags},
+			{Id: 3, OrgId: 1, Settings: simplejson.New()},
+		}
+
+		var alertsQuery *m.GetAlertsQuery
+		var paused *m.PauseAlertCommand
+		var deleted []int64
+
+		bus.AddHandler("test", func(query *m.GetAlertsQuery) error {
+			alertsQuery = query
+			query.Result = alerts
+			return nil
+		})
+		bus.AddHandler("test", func(cmd *m.PauseAlertCommand) error {
+			paused = cmd
+			return nil
+		})
+		bus.AddHandler("test", func(cmd *m.DeleteAlertCommand) error {
+			deleted = append(deleted, cmd.AlertId)
+			return nil
+		})
+
+		Convey("Should pause alerts with matching tags", func() {
+			cmd := &BulkAlertsCommand{OrgId: 1, Action: BulkActionPause, Tags: map[string]string{"team": "ops"}}
+			err := bus.Dispatch(cmd)
+			So(err, ShouldBeNil)
+			So(cmd.Result.AlertIds, ShouldResemble, []int64{1, 2})
+			So(paused.AlertIds, ShouldResemble, []int64{1, 2})
+			So(paused.Paused, ShouldBeTrue)
+		})
+
+		Convey("Should only delete standalone alerts", func() {
+			cmd := &BulkAlertsCommand{OrgId: 1, Action: BulkActionDelete, Tags: map[string]string{"team": "ops"}}
+			err := bus.Dispatch(cmd)
+			So(err, ShouldBeNil)
+			So(deleted, ShouldResemble, []int64{1})
+			So(cmd.Result.Skipped, ShouldResemble, []int64{2})
+		})
+
+		Convey("Should not dispatch when nothing matches", func() {
+			cmd := &BulkAlertsCommand{OrgId: 1, Action: BulkActionResume, Tags: map[string]string{"team": "dev"}}
+			err := bus.Dispatch(cmd)
+			So(err, ShouldBeNil)
+			So(paused, ShouldBeNil)
+		})
+
+		Convey("Should select alerts of dashboards in a folder", func() {
+			cmd := &BulkAlertsCommand{OrgId: 1, Action: BulkActionResume, FolderId: 5}
+			err := bus.Dispatch(cmd)
+			So(err, ShouldBeNil)
+			So(alertsQuery.FolderId, ShouldEqual, 5)
+			So(paused.Paused, ShouldBeFalse)
+		})
+
+		Convey("Should skip alerts of dashboards the user cannot edit", func() {
+			bus.AddHandler("test", func(query *m.GetDashboardsQuery) error {
+				query.Result = []*m.Dashboard{{Id: 7, OrgId: 1}}
+				return nil
+			})
+			bus.AddHandler("test", func(query *m.GetOrgDashboardAclsQuery) error {
+				query.Result = []*m.DashboardAclInfoDTO{
+					{OrgId: 1, DashboardId: 7, UserId: 3, Permission: m.PERMISSION_VIEW},
+				}
+				query.ResultHasAcl = map[int64]bool{7: true}
+				return nil
+			})
+
+			user := &m.SignedInUser{UserId: 2, OrgId: 1, OrgRole: m.ROLE_EDITOR}
+			cmd := &BulkAlertsCommand{OrgId: 1, SignedInUser: user, Action: BulkActionPause, Tags: map[string]string{"team": "ops"}}
+			err := bus.Dispatch(cmd)
+			So(err, ShouldBeNil)
+			So(cmd.Result.AlertIds, ShouldResemble, []int64{1})
+			So(paused.AlertIds, ShouldResemble, []int64{1})
+		})
+
+		Convey("Should require a selection", func() {
+			err := bus.Dispatch(&BulkAlertsCommand{OrgId: 1, Action: BulkActionPause})
+			So(err, ShouldHaveSameTypeAs, ValidationError{})
+		})
+
+		Convey("Should reject unknown actions", func() {
+			err := bus.Dispatch(&BulkAlertsCommand{OrgId: 1, Action: "archive", DashboardId: 7})
+			So(err, ShouldHaveSameTypeAs, ValidationError{})
+		})
+	})
+}
@@ -0,0 +1,370 @@
+package conditions
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/tsdb"
+)
+
+var (
+	directions []string = []string{"above", "below", "outside"}
+)
+
+// QueryEvaluator is implemented by evaluators that compare the reduced
+// value against data fetched with additional queries.
+type QueryEvaluator interface {
+	AlertEvaluator
+	// Prepare executes the additional queries for one evaluation of the
+	// condition and returns the function used to evaluate each series.
+	Prepare(c *QueryCondition, context *alerting.EvalContext, timeRange *tsdb.TimeRange) (SeriesEvaluator, error)
+}
+
+// SeriesEvaluator evaluates the reduced value of a single series.
+type SeriesEvaluator func(series *tsdb.TimeSeries, reducedValue null.Float) bool
+
+// BaselineEvaluator compares the reduced value against the same query
+// shifted back in time, e.g. the same hour last week.
+type BaselineEvaluator struct {
+	Shift     time.Duration
+	Percent   float64
+	Direction string
+}
+
+// StdDevEvaluator compares the reduced value against the mean of a longer
+// window, firing when it is more than N standard deviations away from it.
+type StdDevEvaluator struct {
+	Window    time.Duration
+	Deviation float64
+	Direction string
+}
+
+// ForecastEvaluator fits a linear regression over a window and fires when
+// the value predicted some time ahead crosses the threshold.
+type ForecastEvaluator struct {
+	Window    time.Duration
+	Ahead     time.Duration
+	Threshold float64
+	Direction string
+}
+
+func newBaselineEvaluator(model *simplejson.Json) (*BaselineEvaluator, error) {
+	params := model.Get("params").MustArray()
+	if len(params) < 2 {
+		return nil, alerting.ValidationError{Reason: "Baseline evaluator requires time shift and percent parameters"}
+	}
+
+	shift, err := parseEvaluatorDuration(params[0])
+	if err != nil {
+		return nil, err
+	}
+
+	percent, err := parseEvaluatorNumber(params[1])
+	if err != nil {
+		return nil, err
+	}
+
+	direction, err := parseEvaluatorDirection(params, 2, "outside")
+	if err != nil {
+		return nil, err
+	}
+
+	return &BaselineEvaluator{Shift: shift, Percent: percent, Direction: direction}, nil
+}
+
+func (e *BaselineEvaluator) Eval(reducedValue null.Float) bool {
+	return false
+}
+
+func (e *BaselineEvaluator) Prepare(c *QueryCondition, context *alerting.EvalContext, timeRange *tsdb.TimeRange) (SeriesEvaluator, error) {
+	// datasources only read Now of relative ranges from the time range
+	// itself, so the shifted range is sent as absolute times
+	shiftedRange := tsdb.NewAbsoluteTimeRange(
+		timeRange.MustGetFrom().Add(-e.Shift),
+		timeRange.MustGetTo().Add(-e.Shift),
+	)
+
+	seriesList, err := c.executeQuery(context, shiftedRange)
+	if err != nil {
+		return nil, err
+	}
+
+	baselines := make(map[string]null.Float)
+	for _, series := range seriesList {
+		baselines[series.Name] = c.Reducer.Reduce(series)
+	}
+
+	return func(series *tsdb.TimeSeries, reducedValue null.Float) bool {
+		baseline, exists := baselines[series.Name]
+		if !exists || !baseline.Valid || !reducedValue.Valid || baseline.Float64 == 0 {
+			return false
+		}
+
+		change := (reducedValue.Float64 - baseline.Float64) / math.Abs(baseline.Float64) * 100
+		return exceeds(e.Direction, change, e.Percent)
+	}, nil
+}
+
+func newStdDevEvaluator(model *simplejson.Json) (*StdDevEvaluator, error) {
+	params := model.Get("params").MustArray()
+	if len(params) < 2 {
+		return nil, alerting.ValidationError{Reason: "Standard deviation evaluator requires window and deviation parameters"}
+	}
+
+	window, err := parseEvaluatorDuration(params[0])
+	if err != nil {
+		return nil, err
+	}
+
+	deviation, err := parseEvaluatorNumber(params[1])
+	if err != nil {
+		return nil, err
+	}
+
+	direction, err := parseEvaluatorDirection(params, 2, "outside")
+	if err != nil {
+		return nil, err
+	}
+
+	return &StdDevEvaluator{Window: window, Deviation: deviation, Direction: direction}, nil
+}
+
+func (e *StdDevEvaluator) Eval(reducedValue null.Float) bool {
+	return false
+}
+
+func (e *StdDevEvaluator) Prepare(c *QueryCondition, context *alerting.EvalContext, timeRange *tsdb.TimeRange) (SeriesEvaluator, error) {
+	windowRange := tsdb.NewAbsoluteTimeRange(timeRange.Now.Add(-e.Window), timeRange.MustGetTo())
+
+	seriesList, err := c.executeQuery(context, windowRange)
+	if err != nil {
+		return nil, err
+	}
+
+	type band struct {
+		mean   float64
+		stddev float64
+	}
+
+	bands := make(map[string]band)
+	for _, series := range seriesList {
+		values := validValues(series)
+		if len(values) == 0 {
+			continue
+		}
+
+		mean, variance := 0.0, 0.0
+		for _, value := range values {
+			mean += value
+		}
+		mean /= float64(len(values))
+
+		for _, value := range values {
+			variance += (value - mean) * (value - mean)
+		}
+		variance /= float64(len(values))
+
+		bands[series.Name] = band{mean: mean, stddev: math.Sqrt(variance)}
+	}
+
+	return func(series *tsdb.TimeSeries, reducedValue null.Float) bool {
+		b, exists := bands[series.Name]
+		if !exists || !reducedValue.Valid {
+			return false
+		}
+
+		return exceeds(e.Direction, reducedValue.Float64-b.mean, e.Deviation*b.stddev)
+	}, nil
+}
+
+func newForecastEvaluator(model *simplejson.Json) (*ForecastEvaluator, error) {
+	params := model.Get("params").MustArray()
+	if len(params) < 3 {
+		return nil, alerting.ValidationError{Reason: "Forecast evaluator requires window, time ahead and threshold parameters"}
+	}
+
+	window, err := parseEvaluatorDuration(params[0])
+	if err != nil {
+		return nil, err
+	}
+
+	ahead, err := parseEvaluatorDuration(params[1])
+	if err != nil {
+		return nil, err
+	}
+
+	threshold, err := parseEvaluatorNumber(params[2])
+	if err != nil {
+		return nil, err
+	}
+
+	direction, err := parseEvaluatorDirection(params, 3, "above")
+	if err != nil {
+		return nil, err
+	}
+
+	if direction == "outside" {
+		return nil, alerting.ValidationError{Reason: "Forecast evaluator direction has to be above or below"}
+	}
+
+	return &ForecastEvaluator{Window: window, Ahead: ahead, Threshold: threshold, Direction: direction}, nil
+}
+
+func (e *ForecastEvaluator) Eval(reducedValue null.Float) bool {
+	return false
+}
+
+func (e *ForecastEvaluator) Prepare(c *QueryCondition, context *alerting.EvalContext, timeRange *tsdb.TimeRange) (SeriesEvaluator, error) {
+	windowRange := tsdb.NewAbsoluteTimeRange(timeRange.Now.Add(-e.Window), timeRange.MustGetTo())
+
+	seriesList, err := c.executeQuery(context, windowRange)
+	if err != nil {
+		return nil, err
+	}
+
+	at := float64(timeRange.Now.Add(e.Ahead).UnixNano() / int64(time.Millisecond))
+	forecasts := make(map[string]float64)
+	for _, series := range seriesList {
+		if forecast, ok := linearForecast(series.Points, at); ok {
+			forecasts[series.Name] = forecast
+
+			if context.IsTestRun {
+				context.Logs = append(context.Logs, &alerting.ResultLogEntry{
+					Message: fmt.Sprintf("Condition[%d]: Forecast: %s, Value: %v", c.Index, series.Name, forecast),
+				})
+			}
+		}
+	}
+
+	return func(series *tsdb.TimeSeries, reducedValue null.Float) bool {
+		forecast, exists := forecasts[series.Name]
+		if !exists {
+			return false
+		}
+
+		if e.Direction == "below" {
+			return forecast < e.Threshold
+		}
+		return forecast > e.Threshold
+	}, nil
+}
+
+// linearForecast fits a least squares line through the points and returns
+// the value it predicts at the given ms timestamp.
+func linearForecast(points tsdb.TimeSeriesPoints, at float64) (float64, bool) {
+	var n, sumX, sumY, sumXY, sumXX float64
+	var origin float64
+
+	for _, point := range points {
+		if !point[0].Valid || !point[1].Valid {
+			continue
+		}
+
+		// timestamps are relative to the first point to keep the sums small
+		if n == 0 {
+			origin = point[1].Float64
+		}
+		x := point[1].Float64 - origin
+		y := point[0].Float64
+
+		n++
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if n < 2 || denominator == 0 {
+		return 0, false
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+
+	return intercept + slope*(at-origin), true
+}
+
+func exceeds(direction string, delta float64, limit float64) bool {
+	switch direction {
+	case "above":
+		return delta > limit
+	case "below":
+		return delta < -limit
+	}
+
+	return math.Abs(delta) > limit
+}
+
+// parseEvaluatorNumber accepts numbers and numeric strings as the params
+// of these evaluators are edited as text.
+func parseEvaluatorNumber(param interface{}) (float64, error) {
+	var value float64
+	var err error
+
+	switch number := param.(type) {
+	case json.Number:
+		value, err = number.Float64()
+	case string:
+		value, err = strconv.ParseFloat(number, 64)
+	default:
+		return 0, alerting.ValidationError{Reason: "Evaluator has invalid parameter"}
+	}
+
+	if err != nil {
+		return 0, alerting.ValidationError{Reason: "Evaluator has invalid parameter", Err: err}
+	}
+
+	return value, nil
+}
+
+func parseEvaluatorDirection(params []interface{}, index int, defaultDirection string) (string, error) {
+	if len(params) <= index {
+		return defaultDirection, nil
+	}
+
+	direction, ok := params[index].(string)
+	if !ok || !inSlice(direction, directions) {
+		return "", alerting.ValidationError{Reason: fmt.Sprintf("Evaluator direction has to be one of %s", strings.Join(directions, ", "))}
+	}
+
+	return direction, nil
+}
+
+// parseEvaluatorDuration parses durations like 30m, 1d or 1w.
+func parseEvaluatorDuration(param interface{}) (time.Duration, error) {
+	value, ok := param.(string)
+	if !ok || value == "" {
+		return 0, alerting.ValidationError{Reason: fmt.Sprintf("Evaluator has invalid duration %v", param)}
+	}
+
+	unit := time.Duration(0)
+	switch value[len(value)-1] {
+	case 'd':
+		unit = time.Hour * 24
+	case 'w':
+		unit = time.Hour * 24 * 7
+	}
+
+	if unit != 0 {
+		count, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || count <= 0 {
+			return 0, alerting.ValidationError{Reason: "Evaluator has invalid duration " + value}
+		}
+		return time.Duration(count) * unit, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, alerting.ValidationError{Reason: "Evaluator has invalid duration " + value}
+	}
+
+	return duration, nil
+}
@@ -118,6 +118,15 @@ func NewAlertEvaluator(model *simplejson.Json) (AlertEvaluator, error) {
 		return newRangedEvaluator(typ, model)
 	}
 
+	switch typ {
+	case "baseline":
+		return newBaselineEvaluator(model)
+	case "stddev":
+		return newStdDevEvaluator(model)
+	case "forecast":
+		return newForecastEvaluator(model)
+	}
+
 	if typ == "no_value" {
 		return &NoValueEvaluator{}, nil
 	}
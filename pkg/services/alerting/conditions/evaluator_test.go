@@ -2,6 +2,7 @@ package conditions
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 
@@ -60,4 +61,69 @@ func TestEvalutors(t *testing.T) {
 
 		})
 	})
+
+	Convey("query evaluators", t, func() {
+		parse := func(json string) (AlertEvaluator, error) {
+			jsonModel, err := simplejson.NewJson([]byte(json))
+			So(err, ShouldBeNil)
+			return NewAlertEvaluator(jsonModel)
+		}
+
+		Convey("should read baseline params", func() {
+			evaluator, err := parse(`{"type": "baseline", "params": ["1w", 20, "above"] }`)
+			So(err, ShouldBeNil)
+
+			baseline := evaluator.(*BaselineEvaluator)
+			So(baseline.Shift, ShouldEqual, time.Hour*24*7)
+			So(baseline.Percent, ShouldEqual, 20)
+			So(baseline.Direction, ShouldEqual, "above")
+		})
+
+		Convey("should read numeric params given as text", func() {
+			evaluator, err := parse(`{"type": "stddev", "params": ["6h", "2.5"] }`)
+			So(err, ShouldBeNil)
+			So(evaluator.(*StdDevEvaluator).Deviation, ShouldEqual, 2.5)
+		})
+
+		Convey("should read day and week windows", func() {
+			evaluator, err := parse(`{"type": "stddev", "params": ["1d", 3] }`)
+			So(err, ShouldBeNil)
+			So(evaluator.(*StdDevEvaluator).Window, ShouldEqual, time.Hour*24)
+
+			evaluator, err = parse(`{"type": "forecast", "params": ["1w", "1d", 90] }`)
+			So(err, ShouldBeNil)
+			So(evaluator.(*ForecastEvaluator).Window, ShouldEqual, time.Hour*24*7)
+		})
+
+		Convey("should default stddev direction to outside", func() {
+			evaluator, err := parse(`{"type": "stddev", "params": ["6h", 3] }`)
+			So(err, ShouldBeNil)
+			So(evaluator.(*StdDevEvaluator).Direction, ShouldEqual, "outside")
+		})
+
+		Convey("should read forecast params", func() {
+			evaluator, err := parse(`{"type": "forecast", "params": ["1h", "30m", 90] }`)
+			So(err, ShouldBeNil)
+
+			forecast := evaluator.(*ForecastEvaluator)
+			So(forecast.Window, ShouldEqual, time.Hour)
+			So(forecast.Ahead, ShouldEqual, time.Minute*30)
+			So(forecast.Threshold, ShouldEqual, 90)
+			So(forecast.Direction, ShouldEqual, "above")
+		})
+
+		Convey("should reject invalid params", func() {
+			_, err := parse(`{"type": "baseline", "params": ["1w"] }`)
+			So(err, ShouldNotBeNil)
+
+			_, err = parse(`{"type": "baseline", "params": ["lastweek", 20] }`)
+			So(err, ShouldNotBeNil)
+
+			_, err = parse(`{"type": "stddev", "params": ["6h", 3, "sideways"] }`)
+			So(err, ShouldNotBeNil)
+
+			_, err = parse(`{"type": "forecast", "params": ["1h", "30m", 90, "outside"] }`)
+			So(err, ShouldNotBeNil)
+		})
+	})
 }
@@ -33,6 +33,7 @@ type AlertQuery struct {
 	DatasourceId int64
 	From         string
 	To           string
+	Table        TableOptions
 }
 
 func (c *QueryCondition) Eval(context *alerting.EvalContext) (*alerting.ConditionResult, error) {
@@ -43,13 +44,24 @@ func (c *QueryCondition) Eval(context *alerting.EvalContext) (*alerting.Conditio
 		return nil, err
 	}
 
+	evalSeries := func(series *tsdb.TimeSeries, reducedValue null.Float) bool {
+		return c.Evaluator.Eval(reducedValue)
+	}
+
+	if queryEvaluator, ok := c.Evaluator.(QueryEvaluator); ok {
+		evalSeries, err = queryEvaluator.Prepare(c, context, timeRange)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	emptySerieCount := 0
 	evalMatchCount := 0
 	var matches []*alerting.EvalMatch
 
 	for _, series := range seriesList {
 		reducedValue := c.Reducer.Reduce(series)
-		evalMatch := c.Evaluator.Eval(reducedValue)
+		evalMatch := evalSeries(series, reducedValue)
 
 		if reducedValue.Valid == false {
 			emptySerieCount++
@@ -120,12 +132,21 @@ func (c *QueryCondition) executeQuery(context *alerting.EvalContext, timeRange *
 			return nil, fmt.Errorf("tsdb.HandleRequest() response error %v", v)
 		}
 
-		result = append(result, v.Series...)
+		series := v.Series
+		for _, table := range v.Tables {
+			tableSeries, err := tableToSeries(table, c.Query.Table, timeRange)
+			if err != nil {
+				return nil, err
+			}
+			series = append(series, tableSeries...)
+		}
+
+		result = append(result, series...)
 
 		if context.IsTestRun {
 			context.Logs = append(context.Logs, &alerting.ResultLogEntry{
 				Message: fmt.Sprintf("Condition[%d]: Query Result", c.Index),
-				Data:    v.Series,
+				Data:    series,
 			})
 		}
 	}
@@ -168,9 +189,17 @@ func NewQueryCondition(model *simplejson.Json, index int) (*QueryCondition, erro
 	}
 
 	condition.Query.DatasourceId = queryJson.Get("datasourceId").MustInt64()
+	condition.Query.Table = TableOptions{
+		ValueColumn:  queryJson.Get("valueColumn").MustString(),
+		TimeColumn:   queryJson.Get("timeColumn").MustString(),
+		LabelColumns: queryJson.Get("labelColumns").MustStringArray(),
+	}
 
-	reducerJson := model.Get("reducer")
-	condition.Reducer = NewSimpleReducer(reducerJson.Get("type").MustString())
+	reducer, err := NewSimpleReducerFromJson(model.Get("reducer"))
+	if err != nil {
+		return nil, err
+	}
+	condition.Reducer = reducer
 
 	evaluatorJson := model.Get("evaluator")
 	evaluator, err := NewAlertEvaluator(evaluatorJson)
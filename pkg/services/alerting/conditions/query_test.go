@@ -2,7 +2,9 @@ package conditions
 
 import (
 	"context"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/null"
@@ -71,6 +73,25 @@ func TestQueryCondition(t *testing.T) {
 				So(cr.Firing, ShouldBeTrue)
 			})
 
+			Convey("Should fire for table rows above 100", func() {
+				ctx.tables = []*tsdb.Table{
+					{
+						Columns: []tsdb.TableColumn{{Text: "shop"}, {Text: "orders"}},
+						Rows: []tsdb.RowValues{
+							{"north", 120.0},
+							{"south", 80.0},
+						},
+					},
+				}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeTrue)
+				So(len(cr.EvalMatches), ShouldEqual, 1)
+				So(cr.EvalMatches[0].Metric, ShouldEqual, "north")
+				So(cr.EvalMatches[0].Tags["shop"], ShouldEqual, "north")
+			})
+
 			Convey("No series", func() {
 				Convey("Should set NoDataFound when condition is gt", func() {
 					ctx.series = tsdb.TimeSeriesSlice{}
@@ -140,12 +161,123 @@ func TestQueryCondition(t *testing.T) {
 	})
 }
 
+func TestQueryConditionWithQueryEvaluators(t *testing.T) {
+
+	Convey("when evaluating query condition with query evaluators", t, func() {
+
+		queryConditionScenario("Given avg() compared to last week", func(ctx *queryConditionTestContext) {
+			ctx.reducer = `{"type": "avg"}`
+			ctx.evaluator = `{"type": "baseline", "params": ["1w", 50, "above"]}`
+			ctx.series = tsdb.TimeSeriesSlice{
+				tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(200, 0)),
+				tsdb.NewTimeSeries("test2", tsdb.NewTimeSeriesPointsFromArgs(120, 0)),
+			}
+			ctx.extraSeries = tsdb.TimeSeriesSlice{
+				tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(100, 0)),
+				tsdb.NewTimeSeries("test2", tsdb.NewTimeSeriesPointsFromArgs(100, 0)),
+			}
+
+			cr, err := ctx.exec()
+			So(err, ShouldBeNil)
+
+			Convey("should query the shifted time range", func() {
+				So(len(ctx.requests), ShouldEqual, 2)
+
+				now := ctx.requests[0].TimeRange.Now
+				shifted := ctx.requests[1].TimeRange
+				So(shifted.From, ShouldEqual, msEpoch(now.Add(-time.Minute*5-time.Hour*24*7)))
+				So(shifted.To, ShouldEqual, msEpoch(now.Add(-time.Hour*24*7)))
+			})
+
+			Convey("should only match series above the baseline", func() {
+				So(cr.Firing, ShouldBeTrue)
+				So(len(cr.EvalMatches), ShouldEqual, 1)
+				So(cr.EvalMatches[0].Metric, ShouldEqual, "test1")
+			})
+		})
+
+		queryConditionScenario("Given last() outside 2 standard deviations", func(ctx *queryConditionTestContext) {
+			ctx.reducer = `{"type": "last"}`
+			ctx.evaluator = `{"type": "stddev", "params": ["6h", 2]}`
+			ctx.extraSeries = tsdb.TimeSeriesSlice{
+				tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(8, 0, 12, 1, 8, 2, 12, 3)),
+			}
+
+			Convey("should query the window", func() {
+				ctx.series = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(11, 0))}
+				_, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+
+				now := ctx.requests[0].TimeRange.Now
+				So(ctx.requests[1].TimeRange.From, ShouldEqual, msEpoch(now.Add(-time.Hour*6)))
+				So(ctx.requests[1].TimeRange.To, ShouldEqual, msEpoch(now))
+			})
+
+			Convey("should not fire within the band", func() {
+				ctx.series = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(11, 0))}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeFalse)
+			})
+
+			Convey("should fire outside the band", func() {
+				ctx.series = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(5, 0))}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeTrue)
+			})
+		})
+
+		queryConditionScenario("Given a forecast 1h ahead", func(ctx *queryConditionTestContext) {
+			ctx.reducer = `{"type": "last"}`
+			ctx.evaluator = `{"type": "forecast", "params": ["1h", "1h", 90]}`
+			ctx.series = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(60, 0))}
+
+			now := time.Now()
+			ms := func(t time.Time) float64 {
+				return float64(t.UnixNano() / int64(time.Millisecond))
+			}
+
+			Convey("should fire when trend crosses threshold", func() {
+				// grows 20 per 30 minutes, reaching 100 an hour from now
+				ctx.extraSeries = tsdb.TimeSeriesSlice{
+					tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(20, ms(now.Add(-time.Hour)), 40, ms(now.Add(-time.Minute*30)), 60, ms(now))),
+				}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeTrue)
+			})
+
+			Convey("should not fire when trend is flat", func() {
+				ctx.extraSeries = tsdb.TimeSeriesSlice{
+					tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(60, ms(now.Add(-time.Hour)), 60, ms(now))),
+				}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func msEpoch(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
+
 type queryConditionTestContext struct {
-	reducer   string
-	evaluator string
-	series    tsdb.TimeSeriesSlice
-	result    *alerting.EvalContext
-	condition *QueryCondition
+	reducer     string
+	evaluator   string
+	series      tsdb.TimeSeriesSlice
+	extraSeries tsdb.TimeSeriesSlice
+	tables      []*tsdb.Table
+	requests    []*tsdb.Request
+	result      *alerting.EvalContext
+	condition   *QueryCondition
 }
 
 type queryConditionScenarioFunc func(c *queryConditionTestContext)
@@ -169,9 +301,15 @@ func (ctx *queryConditionTestContext) exec() (*alerting.ConditionResult, error)
 	ctx.condition = condition
 
 	condition.HandleRequest = func(context context.Context, req *tsdb.Request) (*tsdb.Response, error) {
+		series := ctx.series
+		if len(ctx.requests) > 0 {
+			series = ctx.extraSeries
+		}
+		ctx.requests = append(ctx.requests, req)
+
 		return &tsdb.Response{
 			Results: map[string]*tsdb.QueryResult{
-				"A": {Series: ctx.series},
+				"A": {Series: series, Tables: ctx.tables},
 			},
 		}, nil
 	}
@@ -1,11 +1,14 @@
 package conditions
 
 import (
+	"fmt"
 	"math"
+	"strconv"
 
 	"sort"
 
 	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/tsdb"
 )
 
@@ -14,7 +17,8 @@ type QueryReducer interface {
 }
 
 type SimpleReducer struct {
-	Type string
+	Type   string
+	Params []float64
 }
 
 func (s *SimpleReducer) Reduce(series *tsdb.TimeSeries) null.Float {
@@ -94,6 +98,57 @@ func (s *SimpleReducer) Reduce(series *tsdb.TimeSeries) null.Float {
 				value = (values[(length/2)-1] + values[length/2]) / 2
 			}
 		}
+	case "percentile":
+		values := validValues(series)
+		if len(values) > 0 && len(s.Params) > 0 {
+			allNull = false
+			value = percentile(values, s.Params[0])
+		}
+	case "diff":
+		values := validValues(series)
+		if len(values) > 0 {
+			allNull = false
+			value = values[len(values)-1] - values[0]
+		}
+	case "percent_diff":
+		values := validValues(series)
+		// the change from zero cannot be expressed in percent
+		if len(values) > 0 && values[0] != 0 {
+			allNull = false
+			value = (values[len(values)-1] - values[0]) / math.Abs(values[0]) * 100
+		}
+	case "count_non_null":
+		for _, point := range series.Points {
+			if point[0].Valid {
+				value++
+			}
+		}
+		allNull = value == 0
+	case "stddev":
+		values := validValues(series)
+		if len(values) > 0 {
+			allNull = false
+			mean := float64(0)
+			for _, v := range values {
+				mean += v
+			}
+			mean = mean / float64(len(values))
+
+			for _, v := range values {
+				value += math.Pow(v-mean, 2)
+			}
+			value = math.Sqrt(value / float64(len(values)))
+		}
+	case "delta":
+		if increase, _, ok := counterIncrease(series); ok {
+			allNull = false
+			value = increase
+		}
+	case "rate":
+		if increase, seconds, ok := counterIncrease(series); ok && seconds > 0 {
+			allNull = false
+			value = increase / seconds
+		}
 	}
 
 	if allNull {
@@ -103,6 +158,82 @@ func (s *SimpleReducer) Reduce(series *tsdb.TimeSeries) null.Float {
 	return null.FloatFrom(value)
 }
 
-func NewSimpleReducer(typ string) *SimpleReducer {
-	return &SimpleReducer{Type: typ}
+func validValues(series *tsdb.TimeSeries) []float64 {
+	values := make([]float64, 0, len(series.Points))
+	for _, point := range series.Points {
+		if point[0].Valid {
+			values = append(values, point[0].Float64)
+		}
+	}
+
+	return values
+}
+
+// percentile interpolates between the closest ranks of the values.
+func percentile(values []float64, n float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := n / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// counterIncrease returns how much a counter increased between the first
+// and last non null point and the number of seconds between them. A value
+// lower than the previous one is treated as a counter reset.
+func counterIncrease(series *tsdb.TimeSeries) (float64, float64, bool) {
+	var prev *tsdb.TimePoint
+	var first *tsdb.TimePoint
+	increase := float64(0)
+
+	for i := range series.Points {
+		point := &series.Points[i]
+		if !point[0].Valid {
+			continue
+		}
+
+		if prev == nil {
+			first = point
+		} else if point[0].Float64 >= prev[0].Float64 {
+			increase += point[0].Float64 - prev[0].Float64
+		} else {
+			increase += point[0].Float64
+		}
+
+		prev = point
+	}
+
+	if first == nil || first == prev {
+		return 0, 0, false
+	}
+
+	return increase, (prev[1].Float64 - first[1].Float64) / 1000, true
+}
+
+func NewSimpleReducer(typ string, params ...float64) *SimpleReducer {
+	return &SimpleReducer{Type: typ, Params: params}
+}
+
+func NewSimpleReducerFromJson(model *simplejson.Json) (*SimpleReducer, error) {
+	reducer := NewSimpleReducer(model.Get("type").MustString())
+
+	for _, param := range model.Get("params").MustArray() {
+		value, err := strconv.ParseFloat(fmt.Sprintf("%v", param), 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid reducer parameter %v", param)
+		}
+		reducer.Params = append(reducer.Params, value)
+	}
+
+	if reducer.Type == "percentile" {
+		if len(reducer.Params) == 0 || reducer.Params[0] < 0 || reducer.Params[0] > 100 {
+			return nil, fmt.Errorf("Percentile reducer requires a percentile between 0 and 100")
+		}
+	}
+
+	return reducer, nil
 }
@@ -6,6 +6,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/tsdb"
 )
 
@@ -95,3 +96,79 @@ func testReducer(typ string, datapoints ...float64) float64 {
 
 	return reducer.Reduce(series).Float64
 }
+
+func TestReducersWithNulls(t *testing.T) {
+	Convey("Test reducers with null values", t, func() {
+		nullValue := null.FloatFromPtr(nil)
+
+		tests := []struct {
+			name     string
+			reducer  *SimpleReducer
+			points   []null.Float
+			expected null.Float
+		}{
+			{"percentile 50", NewSimpleReducer("percentile", 50), floats(1, 2, 3, 4, 5), null.FloatFrom(3)},
+			{"percentile 95 interpolates", NewSimpleReducer("percentile", 95), floats(1, 2, 3, 4, 5), null.FloatFrom(4.8)},
+			{"percentile 100 is max", NewSimpleReducer("percentile", 100), floats(5, 1, 3), null.FloatFrom(5)},
+			{"percentile ignores nulls", NewSimpleReducer("percentile", 0), []null.Float{nullValue, null.FloatFrom(2), null.FloatFrom(7)}, null.FloatFrom(2)},
+			{"percentile of nulls", NewSimpleReducer("percentile", 90), []null.Float{nullValue, nullValue}, nullValue},
+			{"diff", NewSimpleReducer("diff"), floats(10, 30, 25), null.FloatFrom(15)},
+			{"diff ignores nulls", NewSimpleReducer("diff"), []null.Float{nullValue, null.FloatFrom(10), null.FloatFrom(4), nullValue}, null.FloatFrom(-6)},
+			{"diff of nulls", NewSimpleReducer("diff"), []null.Float{nullValue}, nullValue},
+			{"percent_diff", NewSimpleReducer("percent_diff"), floats(50, 70, 60), null.FloatFrom(20)},
+			{"percent_diff from negative", NewSimpleReducer("percent_diff"), floats(-50, -25), null.FloatFrom(50)},
+			{"percent_diff from zero", NewSimpleReducer("percent_diff"), floats(0, 10), nullValue},
+			{"count_non_null", NewSimpleReducer("count_non_null"), []null.Float{nullValue, null.FloatFrom(1), null.FloatFrom(0), nullValue}, null.FloatFrom(2)},
+			{"count_non_null of nulls", NewSimpleReducer("count_non_null"), []null.Float{nullValue, nullValue}, nullValue},
+			{"stddev", NewSimpleReducer("stddev"), floats(2, 4, 4, 4, 5, 5, 7, 9), null.FloatFrom(2)},
+			{"stddev ignores nulls", NewSimpleReducer("stddev"), []null.Float{null.FloatFrom(3), nullValue, null.FloatFrom(3)}, null.FloatFrom(0)},
+			{"delta", NewSimpleReducer("delta"), floats(10, 15, 22), null.FloatFrom(12)},
+			{"delta with counter reset", NewSimpleReducer("delta"), floats(10, 15, 3, 8), null.FloatFrom(13)},
+			{"delta with single value", NewSimpleReducer("delta"), floats(10), nullValue},
+			{"rate", NewSimpleReducer("rate"), floats(0, 10, 20, 30), null.FloatFrom(1)},
+			{"rate with counter reset and nulls", NewSimpleReducer("rate"), []null.Float{null.FloatFrom(10), nullValue, null.FloatFrom(30), null.FloatFrom(10)}, null.FloatFrom(1)},
+			{"rate of nulls", NewSimpleReducer("rate"), []null.Float{nullValue, nullValue}, nullValue},
+		}
+
+		for _, test := range tests {
+			series := &tsdb.TimeSeries{Name: "test time serie"}
+			for i, value := range test.points {
+				// one point every ten seconds
+				series.Points = append(series.Points, tsdb.NewTimePoint(value, float64(i*10000)))
+			}
+
+			result := test.reducer.Reduce(series)
+			So(result.Valid, ShouldEqual, test.expected.Valid)
+			if test.expected.Valid {
+				So(result.Float64, ShouldAlmostEqual, test.expected.Float64, 0.0001)
+			}
+		}
+	})
+
+	Convey("Test reducer from json", t, func() {
+		Convey("should parse percentile param", func() {
+			model, _ := simplejson.NewJson([]byte(`{"type": "percentile", "params": ["95"]}`))
+			reducer, err := NewSimpleReducerFromJson(model)
+			So(err, ShouldBeNil)
+			So(reducer.Params, ShouldResemble, []float64{95})
+		})
+
+		Convey("should require valid percentile", func() {
+			model, _ := simplejson.NewJson([]byte(`{"type": "percentile", "params": [101]}`))
+			_, err := NewSimpleReducerFromJson(model)
+			So(err, ShouldNotBeNil)
+
+			model, _ = simplejson.NewJson([]byte(`{"type": "percentile", "params": []}`))
+			_, err = NewSimpleReducerFromJson(model)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func floats(values ...float64) []null.Float {
+	result := make([]null.Float, 0, len(values))
+	for _, value := range values {
+		result = append(result, null.FloatFrom(value))
+	}
+	return result
+}
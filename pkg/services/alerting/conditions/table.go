@@ -0,0 +1,258 @@
+package conditions
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/tsdb"
+)
+
+// TableOptions decides how rows of a table result are turned into series.
+// Empty options pick the value, time and label columns automatically.
+type TableOptions struct {
+	ValueColumn  string
+	TimeColumn   string
+	LabelColumns []string
+}
+
+type tableColumns struct {
+	value  int
+	time   int
+	labels []int
+}
+
+// tableToSeries groups the rows of a table by their label columns into
+// series so they can be reduced and evaluated like any time series. Rows
+// without a time column get the end of the time range as timestamp.
+func tableToSeries(table *tsdb.Table, options TableOptions, timeRange *tsdb.TimeRange) (tsdb.TimeSeriesSlice, error) {
+	result := make(tsdb.TimeSeriesSlice, 0)
+	if len(table.Rows) == 0 {
+		return result, nil
+	}
+
+	columns, err := findTableColumns(table, options)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultTime := null.FloatFrom(float64(timeRange.GetToAsMsEpoch()))
+	seriesByKey := make(map[string]*tsdb.TimeSeries)
+
+	for _, row := range table.Rows {
+		if len(row) != len(table.Columns) {
+			return nil, fmt.Errorf("Table row has %d values, expected %d", len(row), len(table.Columns))
+		}
+
+		tags := make(map[string]string)
+		values := make([]string, 0, len(columns.labels))
+		for _, index := range columns.labels {
+			label := tableValueToString(row[index])
+			tags[table.Columns[index].Text] = label
+			values = append(values, label)
+		}
+
+		key := strings.Join(values, " ")
+		series, exists := seriesByKey[key]
+		if !exists {
+			name := key
+			if name == "" {
+				name = table.Columns[columns.value].Text
+			}
+
+			series = tsdb.NewTimeSeries(name, make(tsdb.TimeSeriesPoints, 0))
+			if len(tags) > 0 {
+				series.Tags = tags
+			}
+
+			seriesByKey[key] = series
+			result = append(result, series)
+		}
+
+		timestamp := defaultTime
+		if columns.time >= 0 {
+			timestamp = tableValueToTime(row[columns.time], table.Columns[columns.time].Text)
+		}
+
+		series.Points = append(series.Points, tsdb.TimePoint{tableValueToFloat(row[columns.value]), timestamp})
+	}
+
+	for _, series := range result {
+		sort.SliceStable(series.Points, func(i, j int) bool {
+			return series.Points[i][1].Float64 < series.Points[j][1].Float64
+		})
+	}
+
+	return result, nil
+}
+
+func findTableColumns(table *tsdb.Table, options TableOptions) (*tableColumns, error) {
+	columns := &tableColumns{value: -1, time: -1}
+	firstRow := table.Rows[0]
+
+	for i, column := range table.Columns {
+		switch {
+		case options.TimeColumn != "" && column.Text == options.TimeColumn:
+			columns.time = i
+		case options.TimeColumn == "" && columns.time < 0 && (column.Text == "time" || column.Text == "time_sec"):
+			columns.time = i
+		}
+	}
+
+	if options.TimeColumn != "" && columns.time < 0 {
+		return nil, fmt.Errorf("Table has no time column named %s", options.TimeColumn)
+	}
+
+	for i, column := range table.Columns {
+		if i == columns.time {
+			continue
+		}
+
+		if options.ValueColumn != "" {
+			if column.Text == options.ValueColumn {
+				columns.value = i
+			}
+			continue
+		}
+
+		// the last numeric column is used as value
+		if isNumericTableValue(firstRow[i]) {
+			columns.value = i
+		}
+	}
+
+	if columns.value < 0 {
+		if options.ValueColumn != "" {
+			return nil, fmt.Errorf("Table has no value column named %s", options.ValueColumn)
+		}
+		return nil, fmt.Errorf("Table has no numeric value column")
+	}
+
+	if len(options.LabelColumns) > 0 {
+		for _, label := range options.LabelColumns {
+			found := false
+			for i, column := range table.Columns {
+				if column.Text == label {
+					columns.labels = append(columns.labels, i)
+					found = true
+				}
+			}
+
+			if !found {
+				return nil, fmt.Errorf("Table has no label column named %s", label)
+			}
+		}
+
+		return columns, nil
+	}
+
+	for i := range table.Columns {
+		if i != columns.time && i != columns.value && !isNumericTableValue(firstRow[i]) {
+			columns.labels = append(columns.labels, i)
+		}
+	}
+
+	return columns, nil
+}
+
+// dereference unwraps the pointers data source executors scan rows into.
+func dereference(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *string:
+		if v != nil {
+			return *v
+		}
+	case *int64:
+		if v != nil {
+			return *v
+		}
+	case *float64:
+		if v != nil {
+			return *v
+		}
+	case *time.Time:
+		if v != nil {
+			return *v
+		}
+	case *[]byte:
+		if v != nil {
+			return string(*v)
+		}
+	case []byte:
+		return string(v)
+	default:
+		return value
+	}
+
+	return nil
+}
+
+func isNumericTableValue(value interface{}) bool {
+	switch dereference(value).(type) {
+	case int, int32, int64, uint, uint32, uint64, float32, float64, json.Number:
+		return true
+	}
+
+	return false
+}
+
+func tableValueToFloat(value interface{}) null.Float {
+	switch v := dereference(value).(type) {
+	case int:
+		return null.FloatFrom(float64(v))
+	case int32:
+		return null.FloatFrom(float64(v))
+	case int64:
+		return null.FloatFrom(float64(v))
+	case uint:
+		return null.FloatFrom(float64(v))
+	case uint32:
+		return null.FloatFrom(float64(v))
+	case uint64:
+		return null.FloatFrom(float64(v))
+	case float32:
+		return null.FloatFrom(float64(v))
+	case float64:
+		return null.FloatFrom(v)
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return null.FloatFrom(f)
+		}
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return null.FloatFrom(f)
+		}
+	}
+
+	return null.FloatFromPtr(nil)
+}
+
+// tableValueToTime returns the ms epoch of a time value. Numbers are
+// treated as ms, except in a time_sec column.
+func tableValueToTime(value interface{}, column string) null.Float {
+	if t, ok := dereference(value).(time.Time); ok {
+		return null.FloatFrom(float64(t.UnixNano() / int64(time.Millisecond)))
+	}
+
+	timestamp := tableValueToFloat(value)
+	if timestamp.Valid && column == "time_sec" {
+		timestamp.Float64 *= 1000
+	}
+
+	return timestamp
+}
+
+func tableValueToString(value interface{}) string {
+	switch v := dereference(value).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,114 @@
+package conditions
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTableToSeries(t *testing.T) {
+	Convey("when converting tables to series", t, func() {
+		timeRange := tsdb.NewTimeRange("5m", "now")
+
+		Convey("should group rows by label columns", func() {
+			eu, us := "eu", "us"
+			first, second, third := 10.0, 20.0, 5.0
+			table := &tsdb.Table{
+				Columns: []tsdb.TableColumn{{Text: "region"}, {Text: "revenue"}},
+				Rows: []tsdb.RowValues{
+					{&eu, &first},
+					{&us, &third},
+					{&eu, &second},
+				},
+			}
+
+			series, err := tableToSeries(table, TableOptions{}, timeRange)
+			So(err, ShouldBeNil)
+			So(len(series), ShouldEqual, 2)
+
+			So(series[0].Name, ShouldEqual, "eu")
+			So(series[0].Tags["region"], ShouldEqual, "eu")
+			So(len(series[0].Points), ShouldEqual, 2)
+			So(series[0].Points[1][0].Float64, ShouldEqual, 20)
+			So(series[0].Points[0][1].Float64, ShouldEqual, float64(timeRange.GetToAsMsEpoch()))
+
+			So(series[1].Name, ShouldEqual, "us")
+			So(series[1].Points[0][0].Float64, ShouldEqual, 5)
+		})
+
+		Convey("should use the value column name when there are no labels", func() {
+			table := &tsdb.Table{
+				Columns: []tsdb.TableColumn{{Text: "orders"}},
+				Rows:    []tsdb.RowValues{{int64(3)}},
+			}
+
+			series, err := tableToSeries(table, TableOptions{}, timeRange)
+			So(err, ShouldBeNil)
+			So(len(series), ShouldEqual, 1)
+			So(series[0].Name, ShouldEqual, "orders")
+			So(series[0].Tags, ShouldBeNil)
+		})
+
+		Convey("should read time columns and sort points", func() {
+			now := time.Now()
+			table := &tsdb.Table{
+				Columns: []tsdb.TableColumn{{Text: "time_sec"}, {Text: "value"}},
+				Rows: []tsdb.RowValues{
+					{json.Number("20"), json.Number("2")},
+					{json.Number("10"), json.Number("1")},
+				},
+			}
+
+			series, err := tableToSeries(table, TableOptions{}, timeRange)
+			So(err, ShouldBeNil)
+			So(series[0].Points[0][1].Float64, ShouldEqual, 10000)
+			So(series[0].Points[0][0].Float64, ShouldEqual, 1)
+
+			table = &tsdb.Table{
+				Columns: []tsdb.TableColumn{{Text: "time"}, {Text: "value"}},
+				Rows:    []tsdb.RowValues{{&now, 1.5}},
+			}
+
+			series, err = tableToSeries(table, TableOptions{}, timeRange)
+			So(err, ShouldBeNil)
+			So(series[0].Points[0][1].Float64, ShouldEqual, float64(now.UnixNano()/int64(time.Millisecond)))
+		})
+
+		Convey("should use configured columns", func() {
+			table := &tsdb.Table{
+				Columns: []tsdb.TableColumn{{Text: "host"}, {Text: "dc"}, {Text: "cpu"}, {Text: "mem"}},
+				Rows: []tsdb.RowValues{
+					{"a", "dc1", 1.0, 50.0},
+					{"b", "dc1", 2.0, 70.0},
+				},
+			}
+
+			series, err := tableToSeries(table, TableOptions{ValueColumn: "cpu", LabelColumns: []string{"dc"}}, timeRange)
+			So(err, ShouldBeNil)
+			So(len(series), ShouldEqual, 1)
+			So(series[0].Name, ShouldEqual, "dc1")
+			So(len(series[0].Points), ShouldEqual, 2)
+			So(series[0].Points[1][0].Float64, ShouldEqual, 2)
+		})
+
+		Convey("should return error for missing columns", func() {
+			table := &tsdb.Table{
+				Columns: []tsdb.TableColumn{{Text: "host"}, {Text: "cpu"}},
+				Rows:    []tsdb.RowValues{{"a", 1.0}},
+			}
+
+			_, err := tableToSeries(table, TableOptions{ValueColumn: "mem"}, timeRange)
+			So(err, ShouldNotBeNil)
+
+			_, err = tableToSeries(table, TableOptions{LabelColumns: []string{"dc"}}, timeRange)
+			So(err, ShouldNotBeNil)
+
+			table.Rows = []tsdb.RowValues{{"a", "b"}}
+			_, err = tableToSeries(table, TableOptions{}, timeRange)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
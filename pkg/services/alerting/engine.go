@@ -2,10 +2,12 @@ package alerting
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/benbjohnson/clock"
 	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -18,17 +20,27 @@ type Engine struct {
 	ruleReader    RuleReader
 	log           log.Logger
 	resultHandler ResultHandler
+	outbox        *notificationOutbox
 }
 
 func NewEngine() *Engine {
+	ruleReader := NewRuleReader()
+	outbox := newNotificationOutbox()
+	outbox.ownsAlert = ruleReader.ownsAlert
+
+	// retries and digests of the outbox run in the engine
+	resultHandler := NewResultHandler()
+	resultHandler.notifier = newNotificationServiceWithOutbox(outbox)
+
 	e := &Engine{
 		ticker:        NewTicker(time.Now(), time.Second*0, clock.New()),
 		execQueue:     make(chan *Job, 1000),
 		scheduler:     NewScheduler(),
 		evalHandler:   NewEvalHandler(),
-		ruleReader:    NewRuleReader(),
+		ruleReader:    ruleReader,
 		log:           log.New("alerting.engine"),
-		resultHandler: NewResultHandler(),
+		resultHandler: resultHandler,
+		outbox:        outbox,
 	}
 
 	return e
@@ -41,6 +53,7 @@ func (e *Engine) Run(ctx context.Context) error {
 
 	alertGroup.Go(func() error { return e.alertingTicker(ctx) })
 	alertGroup.Go(func() error { return e.runJobDispatcher(ctx) })
+	alertGroup.Go(func() error { return e.outbox.Run(ctx) })
 
 	err := alertGroup.Wait()
 
@@ -81,6 +94,7 @@ func (e *Engine) runJobDispatcher(grafanaCtx context.Context) error {
 		case <-grafanaCtx.Done():
 			return dispatcherGroup.Wait()
 		case job := <-e.execQueue:
+			metrics.M_Alerting_Execution_Queue.Update(int64(len(e.execQueue)))
 			dispatcherGroup.Go(func() error { return e.processJob(alertCtx, job) })
 		}
 	}
@@ -102,13 +116,16 @@ func (e *Engine) processJob(grafanaCtx context.Context, job *Job) error {
 
 	job.Running = true
 	evalContext := NewEvalContext(alertCtx, job.Rule)
+	start := time.Now()
 
 	done := make(chan struct{})
+	panicked := false
 
 	go func() {
 		defer func() {
 			if err := recover(); err != nil {
 				e.log.Error("Alert Panic", "error", err, "stack", log.Stack(1))
+				panicked = true
 				close(done)
 			}
 		}()
@@ -128,6 +145,7 @@ func (e *Engine) processJob(grafanaCtx context.Context, job *Job) error {
 		case <-done:
 		}
 	case <-done:
+		countEvaluationResult(evalContext, time.Since(start), panicked)
 	}
 
 	e.log.Debug("Job Execution completed", "timeMs", evalContext.GetDurationMs(), "alertId", evalContext.Rule.Id, "name", evalContext.Rule.Name, "firing", evalContext.Firing)
@@ -135,3 +153,22 @@ func (e *Engine) processJob(grafanaCtx context.Context, job *Job) error {
 	cancelFn()
 	return err
 }
+
+func countEvaluationResult(evalContext *EvalContext, elapsed time.Duration, panicked bool) {
+	ruleId := strconv.FormatInt(evalContext.Rule.Id, 10)
+	metrics.GetOrRegGauge("alerting.rule_execution_time", "rule_id", ruleId).Update(int64(elapsed / time.Millisecond))
+
+	reason := ""
+	switch {
+	case panicked:
+		reason = "panic"
+	case evalContext.Ctx.Err() == context.DeadlineExceeded:
+		reason = "timeout"
+	case evalContext.Error != nil:
+		reason = "error"
+	}
+
+	if reason != "" {
+		metrics.GetOrRegCounter("alerting.execution_failures", "reason", reason).Inc(1)
+	}
+}
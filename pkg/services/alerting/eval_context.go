@@ -22,11 +22,20 @@ type EvalContext struct {
 	EndTime         time.Time
 	Rule            *Rule
 	log             log.Logger
-	dashboardSlug   string
+	dashboardRef    *m.DashboardRef
+	dashboardRefErr error
+	dashboardRefSet bool
 	ImagePublicUrl  string
 	ImageOnDiskPath string
 	NoDataFound     bool
 	PrevAlertState  m.AlertStateType
+	Acknowledgement *Acknowledgement
+	// FiredBeforeInhibition is set when an inhibited alert goes back to ok
+	// and a firing notification was sent before it got inhibited.
+	FiredBeforeInhibition bool
+	// NotificationKey identifies the state change notifications are sent
+	// for, so servers sharing a database never send them twice.
+	NotificationKey string
 
 	Ctx context.Context
 }
@@ -66,6 +75,11 @@ func (c *EvalContext) GetStateModel() *StateDescription {
 			Color: "#D63232",
 			Text:  "Alerting",
 		}
+	case m.AlertStateInhibited:
+		return &StateDescription{
+			Color: "#888888",
+			Text:  "Inhibited",
+		}
 	default:
 		panic("Unknown rule state " + c.Rule.State)
 	}
@@ -75,11 +89,30 @@ func (c *EvalContext) ShouldUpdateAlertState() bool {
 	return c.Rule.State != c.PrevAlertState
 }
 
+// ShouldRefreshNotification is true when the rule is still firing and
+// notifiers that expire alerts have to be notified again.
+func (c *EvalContext) ShouldRefreshNotification() bool {
+	if c.ShouldUpdateAlertState() {
+		return false
+	}
+
+	return c.Rule.State == m.AlertStateAlerting || c.Rule.State == m.AlertStateNoData
+}
+
 func (c *EvalContext) ShouldSendNotification() bool {
 	if (c.PrevAlertState == m.AlertStatePending) && (c.Rule.State == m.AlertStateOK) {
 		return false
 	}
 
+	if c.Rule.State == m.AlertStateInhibited {
+		return false
+	}
+
+	// only resolve an inhibited alert when it fired before it was inhibited
+	if c.PrevAlertState == m.AlertStateInhibited && c.Rule.State == m.AlertStateOK {
+		return c.FiredBeforeInhibition
+	}
+
 	return true
 }
 
@@ -91,18 +124,43 @@ func (c *EvalContext) GetNotificationTitle() string {
 	return "[" + c.GetStateModel().Text + "] " + c.Rule.Name
 }
 
+// getDashboardRef looks up the dashboard of the rule once per evaluation,
+// failed lookups are not retried when notifications are rendered again.
+func (c *EvalContext) getDashboardRef() (*m.DashboardRef, error) {
+	if c.dashboardRefSet {
+		return c.dashboardRef, c.dashboardRefErr
+	}
+
+	c.dashboardRefSet = true
+
+	refQuery := &m.GetDashboardRefByIdQuery{Id: c.Rule.DashboardId}
+	if err := bus.Dispatch(refQuery); err != nil {
+		c.dashboardRefErr = err
+		return nil, err
+	}
+
+	c.dashboardRef = refQuery.Result
+	return c.dashboardRef, nil
+}
+
 func (c *EvalContext) GetDashboardSlug() (string, error) {
-	if c.dashboardSlug != "" {
-		return c.dashboardSlug, nil
+	ref, err := c.getDashboardRef()
+	if err != nil {
+		return "", err
 	}
 
-	slugQuery := &m.GetDashboardSlugByIdQuery{Id: c.Rule.DashboardId}
-	if err := bus.Dispatch(slugQuery); err != nil {
+	return ref.Slug, nil
+}
+
+// GetDashboardUid returns the uid of the dashboard of the rule, links built
+// with it keep working when the dashboard is renamed.
+func (c *EvalContext) GetDashboardUid() (string, error) {
+	ref, err := c.getDashboardRef()
+	if err != nil {
 		return "", err
 	}
 
-	c.dashboardSlug = slugQuery.Result
-	return c.dashboardSlug, nil
+	return ref.Uid, nil
 }
 
 func (c *EvalContext) GetRuleUrl() (string, error) {
@@ -110,10 +168,14 @@ func (c *EvalContext) GetRuleUrl() (string, error) {
 		return setting.AppUrl, nil
 	}
 
-	if slug, err := c.GetDashboardSlug(); err != nil {
+	if c.Rule.DashboardId == 0 {
+		return fmt.Sprintf("%salerting/list?orgId=%d", setting.AppUrl, c.Rule.OrgId), nil
+	}
+
+	if uid, err := c.GetDashboardUid(); err != nil {
 		return "", err
 	} else {
-		ruleUrl := fmt.Sprintf("%sdashboard/db/%s?fullscreen&edit&tab=alert&panelId=%d&orgId=%d", setting.AppUrl, slug, c.Rule.PanelId, c.Rule.OrgId)
+		ruleUrl := fmt.Sprintf("%sdashboard/uid/%s?fullscreen&edit&tab=alert&panelId=%d&orgId=%d", setting.AppUrl, uid, c.Rule.PanelId, c.Rule.OrgId)
 		return ruleUrl, nil
 	}
 }
@@ -29,6 +29,29 @@ func TestAlertingEvalContext(t *testing.T) {
 			})
 		})
 
+		Convey("Should refresh notifications", func() {
+			Convey("alerting -> alerting", func() {
+				ctx.PrevAlertState = models.AlertStateAlerting
+				ctx.Rule.State = models.AlertStateAlerting
+
+				So(ctx.ShouldRefreshNotification(), ShouldBeTrue)
+			})
+
+			Convey("ok -> alerting", func() {
+				ctx.PrevAlertState = models.AlertStateOK
+				ctx.Rule.State = models.AlertStateAlerting
+
+				So(ctx.ShouldRefreshNotification(), ShouldBeFalse)
+			})
+
+			Convey("ok -> ok", func() {
+				ctx.PrevAlertState = models.AlertStateOK
+				ctx.Rule.State = models.AlertStateOK
+
+				So(ctx.ShouldRefreshNotification(), ShouldBeFalse)
+			})
+		})
+
 		Convey("Should send notifications", func() {
 			Convey("pending -> ok", func() {
 				ctx.PrevAlertState = models.AlertStatePending
@@ -43,6 +66,35 @@ func TestAlertingEvalContext(t *testing.T) {
 
 				So(ctx.ShouldSendNotification(), ShouldBeTrue)
 			})
+
+			Convey("alerting -> inhibited", func() {
+				ctx.PrevAlertState = models.AlertStateAlerting
+				ctx.Rule.State = models.AlertStateInhibited
+
+				So(ctx.ShouldSendNotification(), ShouldBeFalse)
+			})
+
+			Convey("inhibited -> ok", func() {
+				ctx.PrevAlertState = models.AlertStateInhibited
+				ctx.Rule.State = models.AlertStateOK
+
+				So(ctx.ShouldSendNotification(), ShouldBeFalse)
+			})
+
+			Convey("alerting -> inhibited -> ok", func() {
+				ctx.PrevAlertState = models.AlertStateInhibited
+				ctx.Rule.State = models.AlertStateOK
+				ctx.FiredBeforeInhibition = true
+
+				So(ctx.ShouldSendNotification(), ShouldBeTrue)
+			})
+
+			Convey("inhibited -> alerting", func() {
+				ctx.PrevAlertState = models.AlertStateInhibited
+				ctx.Rule.State = models.AlertStateAlerting
+
+				So(ctx.ShouldSendNotification(), ShouldBeTrue)
+			})
 		})
 	})
 }
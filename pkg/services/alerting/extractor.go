@@ -108,6 +108,7 @@ func (e *DashAlertExtractor) GetAlerts() ([]*m.Alert, error) {
 				Name:        jsonAlert.Get("name").MustString(),
 				Handler:     jsonAlert.Get("handler").MustInt64(),
 				Message:     jsonAlert.Get("message").MustString(),
+				Severity:    jsonAlert.Get("severity").MustString(),
 				Frequency:   frequency,
 			}
 
@@ -143,10 +144,18 @@ func (e *DashAlertExtractor) GetAlerts() ([]*m.Alert, error) {
 				jsonQuery.Set("model", panelQuery.Interface())
 			}
 
+			if err := resolveNotificationNames(e.OrgId, jsonAlert, nil); err != nil {
+				return nil, err
+			}
+
 			alert.Settings = jsonAlert
 
 			// validate
 			_, err = NewRuleFromDBAlert(alert)
+			if err == nil {
+				err = validateMessageTemplate(alert)
+			}
+
 			if err == nil && alert.ValidToSave() {
 				alerts = append(alerts, alert)
 			} else {
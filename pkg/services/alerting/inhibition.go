@@ -0,0 +1,168 @@
+package alerting
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/annotations"
+)
+
+// Inhibition suppresses the notifications of a rule while another rule,
+// or any rule with the given tags, is alerting.
+type Inhibition struct {
+	AlertIds []int64
+	Tags     map[string]string
+}
+
+// ParseInhibition reads the inhibitedBy setting of an alert rule.
+func ParseInhibition(settings *simplejson.Json) (*Inhibition, error) {
+	inhibitionJson, exists := settings.CheckGet("inhibitedBy")
+	if !exists {
+		return nil, nil
+	}
+
+	inhibition := &Inhibition{
+		Tags: make(map[string]string),
+	}
+
+	for _, item := range inhibitionJson.Get("alertIds").MustArray() {
+		id, err := simplejson.NewFromAny(item).Int64()
+		if err != nil {
+			return nil, ValidationError{Reason: fmt.Sprintf("Invalid inhibiting alert id %v", item)}
+		}
+		inhibition.AlertIds = append(inhibition.AlertIds, id)
+	}
+
+	for key, value := range inhibitionJson.Get("tags").MustMap() {
+		inhibition.Tags[key] = fmt.Sprintf("%v", value)
+	}
+
+	if len(inhibition.AlertIds) == 0 && len(inhibition.Tags) == 0 {
+		return nil, nil
+	}
+
+	return inhibition, nil
+}
+
+// Matches returns true if the given rule can inhibit.
+func (i *Inhibition) Matches(ruleId int64, tags map[string]string) bool {
+	for _, id := range i.AlertIds {
+		if id == ruleId {
+			return true
+		}
+	}
+
+	return len(i.Tags) > 0 && TagsMatch(i.Tags, tags)
+}
+
+// findInhibitingAlert returns an alerting rule in the same org that
+// inhibits the rule, or nil if there is none.
+func findInhibitingAlert(rule *Rule) (*m.Alert, error) {
+	if rule.InhibitedBy == nil {
+		return nil, nil
+	}
+
+	query := &m.GetAlertsQuery{
+		OrgId: rule.OrgId,
+		State: []string{string(m.AlertStateAlerting)},
+	}
+
+	if err := bus.Dispatch(query); err != nil {
+		return nil, err
+	}
+
+	for _, alert := range query.Result {
+		if alert.Id == rule.Id {
+			continue
+		}
+
+		if rule.InhibitedBy.Matches(alert.Id, alertTags(alert)) {
+			return alert, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// firedBeforeInhibition reports whether the rule was alerting, and so sent
+// a firing notification, when it got inhibited. The state it came from is
+// read from the alert state annotations.
+func firedBeforeInhibition(rule *Rule) (bool, error) {
+	items, err := annotations.GetRepository().Find(&annotations.ItemQuery{
+		OrgId:    rule.OrgId,
+		AlertId:  rule.Id,
+		Type:     annotations.AlertType,
+		NewState: []string{string(m.AlertStateInhibited)},
+		Limit:    1,
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(items) > 0 && items[0].PrevState == string(m.AlertStateAlerting), nil
+}
+
+// alertTags returns the tags set in the settings of an alert.
+func alertTags(alert *m.Alert) map[string]string {
+	tags := make(map[string]string)
+	if alert.Settings == nil {
+		return tags
+	}
+
+	for key, value := range alert.Settings.Get("tags").MustMap() {
+		tags[key] = fmt.Sprintf("%v", value)
+	}
+
+	return tags
+}
+
+// sortByInhibition orders rules so that rules that can inhibit others come
+// before the rules they inhibit, which gives them an earlier offset in
+// each scheduling interval.
+func sortByInhibition(rules []*Rule) []*Rule {
+	depths := make(map[int64]int)
+
+	var depth func(rule *Rule, visiting map[int64]bool) int
+	depth = func(rule *Rule, visiting map[int64]bool) int {
+		if d, exists := depths[rule.Id]; exists {
+			return d
+		}
+
+		if rule.InhibitedBy == nil || visiting[rule.Id] {
+			return 0
+		}
+
+		visiting[rule.Id] = true
+		defer delete(visiting, rule.Id)
+
+		d := 0
+		for _, other := range rules {
+			if other.Id == rule.Id || other.OrgId != rule.OrgId || !rule.InhibitedBy.Matches(other.Id, other.Tags) {
+				continue
+			}
+
+			if otherDepth := depth(other, visiting) + 1; otherDepth > d {
+				d = otherDepth
+			}
+		}
+
+		depths[rule.Id] = d
+		return d
+	}
+
+	for _, rule := range rules {
+		depth(rule, make(map[int64]bool))
+	}
+
+	sorted := make([]*Rule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return depths[sorted[i].Id] < depths[sorted[j].Id]
+	})
+
+	return sorted
+}
@@ -0,0 +1,116 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertInhibition(t *testing.T) {
+	Convey("Alert inhibition", t, func() {
+		Convey("Can parse inhibition settings", func() {
+			settings, _ := simplejson.NewJson([]byte(`{"inhibitedBy": {"alertIds": [1, 2], "tags": {"team": "network"}}}`))
+			inhibition, err := ParseInhibition(settings)
+
+			So(err, ShouldBeNil)
+			So(inhibition.AlertIds, ShouldResemble, []int64{1, 2})
+			So(inhibition.Tags["team"], ShouldEqual, "network")
+		})
+
+		Convey("Should return nil without settings", func() {
+			inhibition, err := ParseInhibition(simplejson.New())
+
+			So(err, ShouldBeNil)
+			So(inhibition, ShouldBeNil)
+		})
+
+		Convey("Should return error for invalid alert id", func() {
+			settings, _ := simplejson.NewJson([]byte(`{"inhibitedBy": {"alertIds": ["core"]}}`))
+			_, err := ParseInhibition(settings)
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Should match on alert id or tags", func() {
+			inhibition := &Inhibition{AlertIds: []int64{5}, Tags: map[string]string{"team": "network", "core": ""}}
+
+			So(inhibition.Matches(5, nil), ShouldBeTrue)
+			So(inhibition.Matches(6, map[string]string{"team": "network", "core": "yes"}), ShouldBeTrue)
+			So(inhibition.Matches(6, map[string]string{"team": "network"}), ShouldBeFalse)
+			So(inhibition.Matches(6, map[string]string{"team": "db", "core": "yes"}), ShouldBeFalse)
+		})
+
+		Convey("Should find alerting inhibitor", func() {
+			var query *m.GetAlertsQuery
+			bus.AddHandler("test", func(q *m.GetAlertsQuery) error {
+				query = q
+				q.Result = []*m.Alert{
+					{Id: 3, Settings: simplejson.New()},
+					{Id: 4, Settings: simplejson.NewFromAny(map[string]interface{}{"tags": map[string]interface{}{"team": "network"}})},
+				}
+				return nil
+			})
+
+			rule := &Rule{Id: 3, OrgId: 1, InhibitedBy: &Inhibition{Tags: map[string]string{"team": "network"}}}
+			inhibitor, err := findInhibitingAlert(rule)
+
+			So(err, ShouldBeNil)
+			So(inhibitor.Id, ShouldEqual, 4)
+			So(query.OrgId, ShouldEqual, 1)
+			So(query.State, ShouldResemble, []string{"alerting"})
+
+			rule.InhibitedBy = &Inhibition{AlertIds: []int64{3}}
+			inhibitor, err = findInhibitingAlert(rule)
+
+			So(err, ShouldBeNil)
+			So(inhibitor, ShouldBeNil)
+		})
+
+		Convey("Should know if alert fired before it was inhibited", func() {
+			repo := &fakeAnnotationsRepo{}
+			annotations.SetRepository(repo)
+			rule := &Rule{Id: 1, OrgId: 1}
+
+			repo.items = []*annotations.Item{{PrevState: string(m.AlertStateOK), NewState: string(m.AlertStateInhibited)}}
+			fired, err := firedBeforeInhibition(rule)
+			So(err, ShouldBeNil)
+			So(fired, ShouldBeFalse)
+
+			repo.items = []*annotations.Item{{PrevState: string(m.AlertStateAlerting), NewState: string(m.AlertStateInhibited)}}
+			fired, err = firedBeforeInhibition(rule)
+			So(err, ShouldBeNil)
+			So(fired, ShouldBeTrue)
+		})
+
+		Convey("Should schedule inhibiting rules first", func() {
+			rules := []*Rule{
+				{Id: 1, InhibitedBy: &Inhibition{AlertIds: []int64{2}}},
+				{Id: 2, InhibitedBy: &Inhibition{Tags: map[string]string{"layer": "network"}}},
+				{Id: 3, Tags: map[string]string{"layer": "network"}},
+				{Id: 4},
+			}
+
+			sorted := sortByInhibition(rules)
+			ids := []int64{}
+			for _, rule := range sorted {
+				ids = append(ids, rule.Id)
+			}
+
+			So(ids, ShouldResemble, []int64{3, 4, 2, 1})
+			So(rules[0].Id, ShouldEqual, 1)
+		})
+
+		Convey("Should not loop on inhibition cycles", func() {
+			rules := []*Rule{
+				{Id: 1, InhibitedBy: &Inhibition{AlertIds: []int64{2}}},
+				{Id: 2, InhibitedBy: &Inhibition{AlertIds: []int64{1}}},
+			}
+
+			So(len(sortByInhibition(rules)), ShouldEqual, 2)
+		})
+	})
+}
@@ -1,6 +1,9 @@
 package alerting
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type EvalHandler interface {
 	Eval(evalContext *EvalContext)
@@ -21,6 +24,29 @@ type Notifier interface {
 	GetIsDefault() bool
 }
 
+// GroupNotifier is implemented by notifiers that can batch several
+// alert state changes into a single digest notification.
+type GroupNotifier interface {
+	Notifier
+	GetGroupSettings() *GroupSettings
+	NotifyGroup(ctx context.Context, evalContexts []*EvalContext) error
+}
+
+// AcknowledgementNotifier is implemented by notifiers that can report
+// when a firing alert is acknowledged.
+type AcknowledgementNotifier interface {
+	Notifier
+	NotifyAcknowledgement(evalContext *EvalContext) error
+}
+
+// RefreshNotifier is implemented by notifiers whose receivers expire
+// firing alerts that are not sent again. They are notified on every
+// evaluation of a rule that is still firing.
+type RefreshNotifier interface {
+	Notifier
+	NotifyRefresh(evalContext *EvalContext) error
+}
+
 type NotifierSlice []Notifier
 
 func (notifiers NotifierSlice) ShouldUploadImage() bool {
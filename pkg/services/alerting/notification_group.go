@@ -0,0 +1,234 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+const (
+	GroupByNone      = ""
+	GroupByChannel   = "channel"
+	GroupByDashboard = "dashboard"
+	GroupByTag       = "tag"
+)
+
+var (
+	defaultGroupWait     = time.Second * 30
+	defaultGroupInterval = time.Minute * 5
+)
+
+type GroupSettings struct {
+	By       string
+	Tag      string
+	Wait     time.Duration
+	Interval time.Duration
+}
+
+func NewGroupSettings(settings *simplejson.Json) *GroupSettings {
+	group := &GroupSettings{
+		By:       settings.Get("groupBy").MustString(GroupByNone),
+		Tag:      settings.Get("groupByTag").MustString(),
+		Wait:     defaultGroupWait,
+		Interval: defaultGroupInterval,
+	}
+
+	if wait, err := time.ParseDuration(settings.Get("groupWait").MustString()); err == nil {
+		group.Wait = wait
+	}
+
+	if interval, err := time.ParseDuration(settings.Get("groupInterval").MustString()); err == nil {
+		group.Interval = interval
+	}
+
+	return group
+}
+
+func (s *GroupSettings) Enabled() bool {
+	switch s.By {
+	case GroupByChannel, GroupByDashboard:
+		return true
+	case GroupByTag:
+		return s.Tag != ""
+	}
+
+	return false
+}
+
+// GroupKey returns the key of the group the evaluation belongs to.
+func (s *GroupSettings) GroupKey(evalContext *EvalContext) string {
+	switch s.By {
+	case GroupByDashboard:
+		return fmt.Sprintf("dashboard-%d", evalContext.Rule.DashboardId)
+	case GroupByTag:
+		for _, match := range evalContext.EvalMatches {
+			if value, exists := match.Tags[s.Tag]; exists {
+				return fmt.Sprintf("tag-%s=%s", s.Tag, value)
+			}
+		}
+		return fmt.Sprintf("tag-%s", s.Tag)
+	}
+
+	return GroupByChannel
+}
+
+// groupedNotification is a state change waiting for the digest of its
+// group, together with the outbox delivery it is stored as.
+type groupedNotification struct {
+	evalContext *EvalContext
+	delivery    *m.AlertNotificationDelivery
+}
+
+type notificationGroup struct {
+	notifier      GroupNotifier
+	notifications []*groupedNotification
+	interval      time.Duration
+	lastFlush     time.Time
+	scheduled     bool
+}
+
+// notificationGrouper collects state changes per notifier and group key
+// and sends them as one digest once the group wait has passed. Later
+// digests for the same group are sent at most once per group interval.
+type notificationGrouper struct {
+	mtx    sync.Mutex
+	groups map[string]*notificationGroup
+	log    log.Logger
+	// sent is called with the notifications of every digest and the
+	// error of sending it
+	sent func(notifications []*groupedNotification, err error)
+}
+
+func newNotificationGrouper() *notificationGrouper {
+	return &notificationGrouper{
+		groups: make(map[string]*notificationGroup),
+		log:    log.New("alerting.notifier.grouper"),
+	}
+}
+
+func (g *notificationGrouper) add(notifier GroupNotifier, evalContext *EvalContext, delivery *m.AlertNotificationDelivery) {
+	settings := notifier.GetGroupSettings()
+	key := fmt.Sprintf("%d/%s", notifier.GetNotifierId(), settings.GroupKey(evalContext))
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	group, exists := g.groups[key]
+	if !exists {
+		group = &notificationGroup{}
+		g.groups[key] = group
+	}
+
+	// a newer state change of the rule replaces one waiting in another group
+	prefix := fmt.Sprintf("%d/", notifier.GetNotifierId())
+	for otherKey, other := range g.groups {
+		if otherKey != key && strings.HasPrefix(otherKey, prefix) {
+			other.notifications = removeNotification(other.notifications, evalContext.Rule.Id)
+		}
+	}
+
+	group.notifier = notifier
+	group.interval = settings.Interval
+	group.notifications = appendOrReplaceNotification(group.notifications, &groupedNotification{
+		evalContext: evalContext,
+		delivery:    delivery,
+	})
+
+	if group.scheduled {
+		return
+	}
+
+	delay := settings.Wait
+	if !group.lastFlush.IsZero() {
+		if untilInterval := group.lastFlush.Add(settings.Interval).Sub(time.Now()); untilInterval > delay {
+			delay = untilInterval
+		}
+	}
+
+	group.scheduled = true
+	time.AfterFunc(delay, func() { g.flush(key) })
+}
+
+func (g *notificationGrouper) flush(key string) {
+	g.mtx.Lock()
+	group := g.groups[key]
+	notifications := group.notifications
+	notifier := group.notifier
+	group.notifications = nil
+	group.scheduled = false
+	group.lastFlush = time.Now()
+	g.mtx.Unlock()
+
+	// forget the group once its interval has passed without state changes
+	time.AfterFunc(group.interval, func() { g.prune(key) })
+
+	if len(notifications) == 0 {
+		return
+	}
+
+	contexts := make([]*EvalContext, 0, len(notifications))
+	for _, notification := range notifications {
+		contexts = append(contexts, notification.evalContext)
+	}
+
+	g.log.Info("Sending grouped notification", "type", notifier.GetType(), "id", notifier.GetNotifierId(), "group", key, "count", len(contexts))
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), alertTimeout)
+	defer cancelFn()
+
+	err := instrumentNotification(notifier.GetType(), func() error {
+		return notifier.NotifyGroup(ctx, contexts)
+	})
+
+	if err != nil {
+		g.log.Error("Failed to send grouped notification", "group", key, "error", err)
+	}
+
+	if g.sent != nil {
+		g.sent(notifications, err)
+	}
+}
+
+// prune removes a group that has nothing queued and has not been flushed
+// within its interval, so groups of removed rules and channels do not pile up.
+func (g *notificationGrouper) prune(key string) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	group, exists := g.groups[key]
+	if !exists || group.scheduled || len(group.notifications) > 0 {
+		return
+	}
+
+	if time.Since(group.lastFlush) >= group.interval {
+		delete(g.groups, key)
+	}
+}
+
+// appendOrReplaceNotification keeps only the latest state change per rule.
+func appendOrReplaceNotification(notifications []*groupedNotification, notification *groupedNotification) []*groupedNotification {
+	for i, existing := range notifications {
+		if existing.evalContext.Rule.Id == notification.evalContext.Rule.Id {
+			notifications[i] = notification
+			return notifications
+		}
+	}
+
+	return append(notifications, notification)
+}
+
+func removeNotification(notifications []*groupedNotification, ruleId int64) []*groupedNotification {
+	for i, existing := range notifications {
+		if existing.evalContext.Rule.Id == ruleId {
+			return append(notifications[:i], notifications[i+1:]...)
+		}
+	}
+
+	return notifications
+}
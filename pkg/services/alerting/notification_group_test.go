@@ -0,0 +1,151 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type FakeGroupNotifier struct {
+	FakeNotifier
+	settings *GroupSettings
+	sent     chan []*EvalContext
+	err      error
+}
+
+func (fn *FakeGroupNotifier) GetGroupSettings() *GroupSettings {
+	return fn.settings
+}
+
+func (fn *FakeGroupNotifier) NotifyGroup(ctx context.Context, evalContexts []*EvalContext) error {
+	fn.sent <- evalContexts
+	return fn.err
+}
+
+func newGroupTestContext(ruleId, dashboardId int64, state m.AlertStateType) *EvalContext {
+	ctx := NewEvalContext(context.TODO(), &Rule{Id: ruleId, DashboardId: dashboardId, State: state})
+	ctx.EvalMatches = []*EvalMatch{{Tags: map[string]string{"dc": "eu"}}}
+	return ctx
+}
+
+func TestNotificationGrouping(t *testing.T) {
+	Convey("Notification grouping", t, func() {
+		Convey("Parsing group settings", func() {
+			settings := simplejson.New()
+			So(NewGroupSettings(settings).Enabled(), ShouldBeFalse)
+
+			settings.Set("groupBy", "tag")
+			So(NewGroupSettings(settings).Enabled(), ShouldBeFalse)
+
+			settings.Set("groupByTag", "dc")
+			settings.Set("groupWait", "10s")
+			group := NewGroupSettings(settings)
+			So(group.Enabled(), ShouldBeTrue)
+			So(group.Wait, ShouldEqual, time.Second*10)
+			So(group.Interval, ShouldEqual, defaultGroupInterval)
+		})
+
+		Convey("Group keys", func() {
+			ctx := newGroupTestContext(1, 5, m.AlertStateAlerting)
+
+			So((&GroupSettings{By: GroupByChannel}).GroupKey(ctx), ShouldEqual, "channel")
+			So((&GroupSettings{By: GroupByDashboard}).GroupKey(ctx), ShouldEqual, "dashboard-5")
+			So((&GroupSettings{By: GroupByTag, Tag: "dc"}).GroupKey(ctx), ShouldEqual, "tag-dc=eu")
+		})
+
+		Convey("Should batch state changes into one digest", func() {
+			grouper := newNotificationGrouper()
+			notifier := &FakeGroupNotifier{
+				settings: &GroupSettings{By: GroupByDashboard, Wait: time.Millisecond * 20, Interval: time.Hour},
+				sent:     make(chan []*EvalContext, 2),
+			}
+
+			grouper.add(notifier, newGroupTestContext(1, 1, m.AlertStateAlerting), nil)
+			grouper.add(notifier, newGroupTestContext(2, 1, m.AlertStateAlerting), nil)
+			grouper.add(notifier, newGroupTestContext(1, 1, m.AlertStateOK), nil)
+			grouper.add(notifier, newGroupTestContext(3, 2, m.AlertStateAlerting), nil)
+
+			digests := map[int64][]*EvalContext{}
+			for i := 0; i < 2; i++ {
+				select {
+				case contexts := <-notifier.sent:
+					digests[contexts[0].Rule.DashboardId] = contexts
+				case <-time.After(time.Second):
+				}
+			}
+
+			So(len(digests), ShouldEqual, 2)
+			So(len(digests[1]), ShouldEqual, 2)
+			So(digests[1][0].Rule.State, ShouldEqual, m.AlertStateOK)
+			So(len(digests[2]), ShouldEqual, 1)
+		})
+
+		Convey("Should only send the latest state change when it moves to another group", func() {
+			grouper := newNotificationGrouper()
+			notifier := &FakeGroupNotifier{
+				settings: &GroupSettings{By: GroupByTag, Tag: "dc", Wait: time.Millisecond * 20, Interval: time.Hour},
+				sent:     make(chan []*EvalContext, 2),
+			}
+
+			resolved := newGroupTestContext(1, 1, m.AlertStateOK)
+			resolved.EvalMatches = nil
+
+			grouper.add(notifier, newGroupTestContext(1, 1, m.AlertStateAlerting), nil)
+			grouper.add(notifier, resolved, nil)
+
+			digests := make([][]*EvalContext, 0)
+			for i := 0; i < 2; i++ {
+				select {
+				case contexts := <-notifier.sent:
+					digests = append(digests, contexts)
+				case <-time.After(time.Millisecond * 200):
+				}
+			}
+
+			So(len(digests), ShouldEqual, 1)
+			So(digests[0][0].Rule.State, ShouldEqual, m.AlertStateOK)
+		})
+
+		Convey("Should remove groups after flush and interval", func() {
+			grouper := newNotificationGrouper()
+			notifier := &FakeGroupNotifier{
+				settings: &GroupSettings{By: GroupByChannel, Wait: time.Millisecond * 10, Interval: time.Millisecond * 30},
+				sent:     make(chan []*EvalContext, 1),
+			}
+
+			grouper.add(notifier, newGroupTestContext(1, 1, m.AlertStateAlerting), nil)
+
+			select {
+			case <-notifier.sent:
+			case <-time.After(time.Second):
+			}
+
+			groupCount := func() int {
+				grouper.mtx.Lock()
+				defer grouper.mtx.Unlock()
+				return len(grouper.groups)
+			}
+
+			So(groupCount(), ShouldEqual, 1)
+
+			deadline := time.Now().Add(time.Second)
+			for groupCount() > 0 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond * 10)
+			}
+			So(groupCount(), ShouldEqual, 0)
+		})
+
+		Convey("Should not group test notifications", func() {
+			notifier := &FakeGroupNotifier{settings: &GroupSettings{By: GroupByChannel}}
+			ctx := newGroupTestContext(1, 1, m.AlertStateAlerting)
+			So(shouldGroupNotification(notifier, ctx), ShouldBeTrue)
+
+			ctx.IsTestRun = true
+			So(shouldGroupNotification(notifier, ctx), ShouldBeFalse)
+		})
+	})
+}
@@ -3,6 +3,7 @@ package alerting
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/imguploader"
 	"github.com/grafana/grafana/pkg/components/renderer"
 	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
 	m "github.com/grafana/grafana/pkg/models"
 )
 
@@ -23,6 +25,8 @@ type NotifierPlugin struct {
 
 type NotificationService interface {
 	Send(context *EvalContext) error
+	SendAcknowledgement(context *EvalContext) error
+	Refresh(context *EvalContext) error
 }
 
 func NewNotificationService() NotificationService {
@@ -30,12 +34,20 @@ func NewNotificationService() NotificationService {
 }
 
 type notificationService struct {
-	log log.Logger
+	log    log.Logger
+	outbox *notificationOutbox
 }
 
 func newNotificationService() *notificationService {
+	return newNotificationServiceWithOutbox(newNotificationOutbox())
+}
+
+// newNotificationServiceWithOutbox creates a notification service sending
+// through the given outbox, so its retries and digests share one outbox.
+func newNotificationServiceWithOutbox(outbox *notificationOutbox) *notificationService {
 	return &notificationService{
-		log: log.New("alerting.notifier"),
+		log:    log.New("alerting.notifier"),
+		outbox: outbox,
 	}
 }
 
@@ -51,7 +63,8 @@ func (n *notificationService) Send(context *EvalContext) error {
 		return nil
 	}
 
-	if notifiers.ShouldUploadImage() {
+	// standalone alerts have no panel to render
+	if notifiers.ShouldUploadImage() && context.Rule.DashboardId != 0 {
 		if err = n.uploadImage(context); err != nil {
 			n.log.Error("Failed to upload alert panel image.", "error", err)
 		}
@@ -65,8 +78,85 @@ func (n *notificationService) sendNotifications(context *EvalContext, notifiers
 
 	for _, notifier := range notifiers {
 		not := notifier //avoid updating scope variable in go routine
+
+		if groupNotifier, ok := not.(GroupNotifier); ok && shouldGroupNotification(groupNotifier, context) {
+			n.log.Info("Queueing grouped notification", "type", not.GetType(), "id", not.GetNotifierId())
+			n.outbox.Queue(context, groupNotifier)
+			continue
+		}
+
 		n.log.Info("Sending notification", "type", not.GetType(), "id", not.GetNotifierId(), "isDefault", not.GetIsDefault())
-		g.Go(func() error { return not.Notify(context) })
+		if context.IsTestRun {
+			g.Go(func() error { return not.Notify(context) })
+		} else {
+			g.Go(func() error { return n.outbox.Send(context, not) })
+		}
+	}
+
+	return g.Wait()
+}
+
+func notifyWithMetrics(notifier Notifier, evalContext *EvalContext) error {
+	return instrumentNotification(notifier.GetType(), func() error {
+		return notifier.Notify(evalContext)
+	})
+}
+
+// instrumentNotification records the duration and failures of sending a
+// notification per notifier type.
+func instrumentNotification(notifierType string, send func() error) error {
+	start := time.Now()
+	err := send()
+
+	metrics.GetOrRegTimer("alerting.notification_time", "type", notifierType).Update(time.Since(start) / time.Millisecond)
+	if err != nil {
+		metrics.GetOrRegCounter("alerting.notification_errors", "type", notifierType).Inc(1)
+	}
+
+	return err
+}
+
+// SendAcknowledgement reports an acknowledgement to the notifiers of the
+// rule that support it.
+func (n *notificationService) SendAcknowledgement(context *EvalContext) error {
+	notifiers, err := n.getNotifiers(context.Rule.OrgId, context.Rule.Notifications, context)
+	if err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(context.Ctx)
+
+	for _, notifier := range notifiers {
+		ackNotifier, ok := notifier.(AcknowledgementNotifier)
+		if !ok {
+			continue
+		}
+
+		n.log.Info("Sending acknowledgement", "type", ackNotifier.GetType(), "id", ackNotifier.GetNotifierId())
+		g.Go(func() error { return ackNotifier.NotifyAcknowledgement(context) })
+	}
+
+	return g.Wait()
+}
+
+// Refresh sends the firing alert again to the notifiers of the rule that
+// expire alerts which are not refreshed.
+func (n *notificationService) Refresh(context *EvalContext) error {
+	notifiers, err := n.getNotifiers(context.Rule.OrgId, context.Rule.Notifications, context)
+	if err != nil {
+		return err
+	}
+
+	g, _ := errgroup.WithContext(context.Ctx)
+
+	for _, notifier := range notifiers {
+		refreshNotifier, ok := notifier.(RefreshNotifier)
+		if !ok {
+			continue
+		}
+
+		n.log.Debug("Refreshing notification", "type", refreshNotifier.GetType(), "id", refreshNotifier.GetNotifierId())
+		g.Go(func() error { return refreshNotifier.NotifyRefresh(context) })
 	}
 
 	return g.Wait()
@@ -85,10 +175,10 @@ func (n *notificationService) uploadImage(context *EvalContext) (err error) {
 		OrgId:   context.Rule.OrgId,
 	}
 
-	if slug, err := context.GetDashboardSlug(); err != nil {
+	if uid, err := context.GetDashboardUid(); err != nil {
 		return err
 	} else {
-		renderOpts.Path = fmt.Sprintf("dashboard-solo/db/%s?&panelId=%d", slug, context.Rule.PanelId)
+		renderOpts.Path = fmt.Sprintf("dashboard-solo/uid/%s?&panelId=%d", uid, context.Rule.PanelId)
 	}
 
 	if imagePath, err := renderer.RenderToPng(renderOpts); err != nil {
@@ -128,6 +218,10 @@ func (n *notificationService) getNotifiers(orgId int64, notificationIds []int64,
 }
 
 func (n *notificationService) createNotifierFor(model *m.AlertNotification) (Notifier, error) {
+	return createNotifier(model)
+}
+
+func createNotifier(model *m.AlertNotification) (Notifier, error) {
 	notifierPlugin, found := notifierFactories[model.Type]
 	if !found {
 		return nil, errors.New("Unsupported notification type")
@@ -137,7 +231,9 @@ func (n *notificationService) createNotifierFor(model *m.AlertNotification) (Not
 }
 
 func shouldUseNotification(notifier Notifier, context *EvalContext) bool {
-	if !context.Firing {
+	// resolves are not routed, a channel always learns that an alert it
+	// received went back to ok
+	if !context.Firing && context.Rule.State == m.AlertStateOK {
 		return true
 	}
 
@@ -148,6 +244,14 @@ func shouldUseNotification(notifier Notifier, context *EvalContext) bool {
 	return notifier.PassesFilter(context.Rule)
 }
 
+func shouldGroupNotification(notifier GroupNotifier, context *EvalContext) bool {
+	if context.IsTestRun {
+		return false
+	}
+
+	return notifier.GetGroupSettings().Enabled()
+}
+
 type NotifierFactory func(notification *m.AlertNotification) (Notifier, error)
 
 var notifierFactories map[string]*NotifierPlugin = make(map[string]*NotifierPlugin)
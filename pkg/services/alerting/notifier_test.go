@@ -39,15 +39,41 @@ func (fn *FakeNotifier) PassesFilter(rule *Rule) bool {
 func TestAlertNotificationExtraction(t *testing.T) {
 
 	Convey("Notifier tests", t, func() {
-		Convey("none firing alerts", func() {
+		Convey("resolved alerts cannot be ignored", func() {
 			ctx := &EvalContext{
 				Firing: false,
 				Rule: &Rule{
-					State: m.AlertStateAlerting,
+					State: m.AlertStateOK,
+				},
+			}
+			notifier := &FakeNotifier{FakeMatchResult: false}
+
+			So(shouldUseNotification(notifier, ctx), ShouldBeTrue)
+		})
+
+		Convey("no data alert that dont match", func() {
+			ctx := &EvalContext{
+				Firing:      false,
+				NoDataFound: true,
+				Rule: &Rule{
+					State: m.AlertStateNoData,
 				},
 			}
 			notifier := &FakeNotifier{FakeMatchResult: false}
 
+			So(shouldUseNotification(notifier, ctx), ShouldBeFalse)
+		})
+
+		Convey("no data alert that match", func() {
+			ctx := &EvalContext{
+				Firing:      false,
+				NoDataFound: true,
+				Rule: &Rule{
+					State: m.AlertStateNoData,
+				},
+			}
+			notifier := &FakeNotifier{FakeMatchResult: true}
+
 			So(shouldUseNotification(notifier, ctx), ShouldBeTrue)
 		})
 
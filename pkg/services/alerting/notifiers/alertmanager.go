@@ -0,0 +1,218 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/annotations"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "alertmanager",
+		Name:        "Prometheus Alertmanager",
+		Description: "Sends alert to Prometheus Alertmanager",
+		Factory:     NewAlertmanagerNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">Alertmanager settings</h3>
+      <div class="gf-form">
+        <span class="gf-form-label width-10">Url</span>
+        <input type="text" required class="gf-form-input max-width-26" ng-model="ctrl.model.settings.url" placeholder="http://localhost:9093"></input>
+      </div>
+    `,
+	})
+}
+
+func NewAlertmanagerNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	url := model.Settings.Get("url").MustString()
+	if url == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find url property in settings"}
+	}
+
+	base, err := NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AlertmanagerNotifier{
+		NotifierBase: base,
+		Url:          strings.TrimSuffix(url, "/"),
+		log:          log.New("alerting.notifier.alertmanager"),
+	}, nil
+}
+
+// minAlertValidity is the shortest time a firing alert stays active in
+// alertmanager without being sent again.
+var minAlertValidity = time.Minute * 5
+
+type AlertmanagerNotifier struct {
+	NotifierBase
+	Url string
+	log log.Logger
+}
+
+func (this *AlertmanagerNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Sending alertmanager alert", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+	metrics.M_Alerting_Notification_Sent_Alertmanager.Inc(1)
+
+	return this.send(evalContext)
+}
+
+// NotifyRefresh sends the firing alerts again before their endsAt passes,
+// alertmanager resolves alerts that are not refreshed.
+func (this *AlertmanagerNotifier) NotifyRefresh(evalContext *alerting.EvalContext) error {
+	this.log.Debug("Refreshing alertmanager alert", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+
+	return this.send(evalContext)
+}
+
+func (this *AlertmanagerNotifier) send(evalContext *alerting.EvalContext) error {
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+		return err
+	}
+
+	matches := evalContext.EvalMatches
+	if evalContext.Rule.State == m.AlertStateOK {
+		// the series that were firing are needed to resolve the matching alerts
+		matches = this.getFiringMatches(evalContext)
+	}
+
+	alerts := make([]*simplejson.Json, 0)
+	for _, match := range matches {
+		alerts = append(alerts, this.createAlert(evalContext, match, ruleUrl))
+	}
+
+	// execution errors and no data have no matches
+	if len(alerts) == 0 {
+		alerts = append(alerts, this.createAlert(evalContext, nil, ruleUrl))
+	}
+
+	body, _ := json.Marshal(alerts)
+
+	cmd := &m.SendWebhookSync{
+		Url:        this.Url + "/api/v1/alerts",
+		HttpMethod: "POST",
+		Body:       string(body),
+	}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		this.log.Error("Failed to send alertmanager", "error", err, "alertmanager", this.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (this *AlertmanagerNotifier) createAlert(evalContext *alerting.EvalContext, match *alerting.EvalMatch, ruleUrl string) *simplejson.Json {
+	alertJSON := simplejson.New()
+	alertJSON.Set("startsAt", evalContext.StartTime.UTC().Format(time.RFC3339))
+	if evalContext.Rule.State == m.AlertStateOK {
+		alertJSON.Set("endsAt", time.Now().UTC().Format(time.RFC3339))
+	} else {
+		alertJSON.Set("endsAt", firingEndsAt(evalContext.Rule).UTC().Format(time.RFC3339))
+	}
+	alertJSON.Set("generatorURL", ruleUrl)
+
+	// annotations, summary and description are the commonly used ones
+	alertJSON.SetPath([]string{"annotations", "summary"}, this.GetTitle(evalContext))
+
+	description := this.GetMessage(evalContext)
+	if evalContext.Error != nil {
+		if description != "" {
+			description += "\n"
+		}
+		description += "Error: " + evalContext.Error.Error()
+	}
+	if description != "" {
+		alertJSON.SetPath([]string{"annotations", "description"}, description)
+	}
+
+	if evalContext.ImagePublicUrl != "" {
+		alertJSON.SetPath([]string{"annotations", "image"}, evalContext.ImagePublicUrl)
+	}
+
+	// labels identify the alert in alertmanager, one alert per series
+	labels := make(map[string]string)
+	if match != nil {
+		if match.Value.Valid {
+			alertJSON.SetPath([]string{"annotations", "value"}, match.Value.String())
+		}
+
+		if len(match.Tags) == 0 {
+			labels["metric"] = match.Metric
+		}
+
+		for key, value := range match.Tags {
+			labels[key] = value
+		}
+	}
+
+	labels["alertname"] = evalContext.Rule.Name
+	if evalContext.Rule.PanelId != 0 {
+		labels["panelId"] = strconv.FormatInt(evalContext.Rule.PanelId, 10)
+	}
+
+	if !evalContext.IsTestRun && evalContext.Rule.DashboardId != 0 {
+		if slug, err := evalContext.GetDashboardSlug(); err == nil {
+			labels["dashboard"] = slug
+		}
+	}
+
+	alertJSON.Set("labels", labels)
+	return alertJSON
+}
+
+// firingEndsAt gives a firing alert time for a few evaluations of the rule,
+// so it only resolves in alertmanager when the rule stops being evaluated.
+func firingEndsAt(rule *alerting.Rule) time.Time {
+	validity := time.Duration(rule.Frequency) * time.Second * 3
+	if validity < minAlertValidity {
+		validity = minAlertValidity
+	}
+
+	return time.Now().Add(validity)
+}
+
+// getFiringMatches returns the eval matches of the last time the rule
+// started alerting, read from the alert state annotations.
+func (this *AlertmanagerNotifier) getFiringMatches(evalContext *alerting.EvalContext) []*alerting.EvalMatch {
+	repo := annotations.GetRepository()
+	if repo == nil || evalContext.Rule.Id == 0 {
+		return nil
+	}
+
+	items, err := repo.Find(&annotations.ItemQuery{
+		OrgId:    evalContext.Rule.OrgId,
+		AlertId:  evalContext.Rule.Id,
+		Type:     annotations.AlertType,
+		NewState: []string{string(m.AlertStateAlerting)},
+		Limit:    1,
+	})
+
+	if err != nil || len(items) == 0 || items[0].Data == nil {
+		return nil
+	}
+
+	data, err := items[0].Data.MarshalJSON()
+	if err != nil {
+		return nil
+	}
+
+	matches := make([]*alerting.EvalMatch, 0)
+	if err := json.Unmarshal(data, &matches); err != nil {
+		this.log.Debug("Could not read eval matches from annotation", "error", err)
+		return nil
+	}
+
+	return matches
+}
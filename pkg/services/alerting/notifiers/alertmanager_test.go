@@ -0,0 +1,184 @@
+package notifiers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// fakeAnnotationsRepo returns the newest saved items matching the type and
+// state of the query.
+type fakeAnnotationsRepo struct {
+	items []*annotations.Item
+}
+
+func (r *fakeAnnotationsRepo) Save(item *annotations.Item) error {
+	r.items = append(r.items, item)
+	return nil
+}
+func (r *fakeAnnotationsRepo) Update(item *annotations.Item) error { return nil }
+func (r *fakeAnnotationsRepo) Find(query *annotations.ItemQuery) ([]*annotations.Item, error) {
+	result := make([]*annotations.Item, 0)
+	for i := len(r.items) - 1; i >= 0; i-- {
+		item := r.items[i]
+		if query.Type != "" && item.Type != query.Type {
+			continue
+		}
+		if len(query.NewState) > 0 && item.NewState != query.NewState[0] {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+func (r *fakeAnnotationsRepo) Delete(params *annotations.DeleteParams) error { return nil }
+
+func TestAlertmanagerNotifier(t *testing.T) {
+	Convey("Alertmanager notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "alertmanager",
+					Type:     "alertmanager",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewAlertmanagerNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `{ "url": "http://127.0.0.1:9093/" }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "alertmanager",
+					Type:     "alertmanager",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewAlertmanagerNotifier(model)
+				alertmanagerNotifier := not.(*AlertmanagerNotifier)
+
+				So(err, ShouldBeNil)
+				So(alertmanagerNotifier.Url, ShouldEqual, "http://127.0.0.1:9093")
+				So(alertmanagerNotifier.Type, ShouldEqual, "alertmanager")
+			})
+		})
+
+		Convey("Sending alerts", func() {
+			var sent *m.SendWebhookSync
+			bus.AddCtxHandler("test", func(ctx context.Context, cmd *m.SendWebhookSync) error {
+				sent = cmd
+				return nil
+			})
+
+			settingsJSON, _ := simplejson.NewJson([]byte(`{ "url": "http://127.0.0.1:9093" }`))
+			not, _ := NewAlertmanagerNotifier(&m.AlertNotification{Name: "am", Type: "alertmanager", Settings: settingsJSON})
+
+			evalContext := alerting.NewEvalContext(context.TODO(), &alerting.Rule{
+				Name:    "High CPU",
+				Message: "cpu is high",
+				PanelId: 2,
+				State:   m.AlertStateAlerting,
+			})
+			evalContext.IsTestRun = true
+
+			Convey("should create one alert per series", func() {
+				evalContext.EvalMatches = []*alerting.EvalMatch{
+					{Metric: "cpu", Value: null.FloatFrom(91), Tags: map[string]string{"host": "a"}},
+					{Metric: "cpu", Value: null.FloatFrom(95), Tags: map[string]string{"host": "b"}},
+				}
+
+				So(not.Notify(evalContext), ShouldBeNil)
+				So(sent.Url, ShouldEqual, "http://127.0.0.1:9093/api/v1/alerts")
+
+				alerts, _ := simplejson.NewJson([]byte(sent.Body))
+				So(len(alerts.MustArray()), ShouldEqual, 2)
+
+				first := alerts.GetIndex(0)
+				So(first.GetPath("labels", "alertname").MustString(), ShouldEqual, "High CPU")
+				So(first.GetPath("labels", "host").MustString(), ShouldEqual, "a")
+				So(first.GetPath("labels", "panelId").MustString(), ShouldEqual, "2")
+				So(first.GetPath("annotations", "description").MustString(), ShouldEqual, "cpu is high")
+				_, hasGeneratorURL := first.CheckGet("generatorURL")
+				So(hasGeneratorURL, ShouldBeTrue)
+
+				endsAt, err := time.Parse(time.RFC3339, first.Get("endsAt").MustString())
+				So(err, ShouldBeNil)
+				So(endsAt, ShouldHappenAfter, time.Now().Add(minAlertValidity-time.Minute))
+			})
+
+			Convey("should extend endsAt of firing alerts on refresh", func() {
+				evalContext.Rule.Frequency = 600
+				evalContext.EvalMatches = []*alerting.EvalMatch{{Metric: "cpu", Value: null.FloatFrom(91)}}
+
+				So(not.(alerting.RefreshNotifier).NotifyRefresh(evalContext), ShouldBeNil)
+				alerts, _ := simplejson.NewJson([]byte(sent.Body))
+				So(len(alerts.MustArray()), ShouldEqual, 1)
+
+				endsAt, err := time.Parse(time.RFC3339, alerts.GetIndex(0).Get("endsAt").MustString())
+				So(err, ShouldBeNil)
+				So(endsAt, ShouldHappenAfter, time.Now().Add(time.Minute*29))
+			})
+
+			Convey("should use metric label when series has no tags", func() {
+				evalContext.EvalMatches = []*alerting.EvalMatch{{Metric: "cpu", Value: null.FloatFrom(91)}}
+
+				So(not.Notify(evalContext), ShouldBeNil)
+				alerts, _ := simplejson.NewJson([]byte(sent.Body))
+				So(alerts.GetIndex(0).GetPath("labels", "metric").MustString(), ShouldEqual, "cpu")
+			})
+
+			Convey("should set endsAt when alert is ok", func() {
+				evalContext.Rule.State = m.AlertStateOK
+
+				So(not.Notify(evalContext), ShouldBeNil)
+				alerts, _ := simplejson.NewJson([]byte(sent.Body))
+				So(len(alerts.MustArray()), ShouldEqual, 1)
+				So(alerts.GetIndex(0).Get("endsAt").MustString(), ShouldNotEqual, "")
+			})
+
+			Convey("should resolve the firing series when the alert was acknowledged", func() {
+				repo := &fakeAnnotationsRepo{}
+				annotations.SetRepository(repo)
+				defer annotations.SetRepository(nil)
+
+				repo.Save(&annotations.Item{
+					Type:     annotations.AlertType,
+					AlertId:  1,
+					NewState: string(m.AlertStateAlerting),
+					Data: simplejson.NewFromAny([]interface{}{
+						map[string]interface{}{"metric": "cpu", "tags": map[string]interface{}{"host": "a"}},
+						map[string]interface{}{"metric": "cpu", "tags": map[string]interface{}{"host": "b"}},
+					}),
+				})
+				repo.Save(&annotations.Item{
+					Type:    annotations.AlertAckType,
+					AlertId: 1,
+					Data:    simplejson.NewFromAny(map[string]interface{}{"acknowledged": true, "login": "admin"}),
+				})
+
+				evalContext.Rule.Id = 1
+				evalContext.Rule.State = m.AlertStateOK
+
+				So(not.Notify(evalContext), ShouldBeNil)
+				alerts, _ := simplejson.NewJson([]byte(sent.Body))
+				So(len(alerts.MustArray()), ShouldEqual, 2)
+				So(alerts.GetIndex(1).GetPath("labels", "host").MustString(), ShouldEqual, "b")
+			})
+		})
+	})
+}
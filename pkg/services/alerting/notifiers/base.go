@@ -1,32 +1,121 @@
 package notifiers
 
 import (
+	"time"
+
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/log"
 	"github.com/grafana/grafana/pkg/services/alerting"
 )
 
+var baseLog = log.New("alerting.notifier")
+
 type NotifierBase struct {
 	Name        string
 	Type        string
 	Id          int64
 	IsDeault    bool
 	UploadImage bool
+
+	TitleTemplate string
+	BodyTemplate  string
+	GroupSettings *alerting.GroupSettings
+	Routes        []*alerting.NotificationRoute
 }
 
-func NewNotifierBase(id int64, isDefault bool, name, notifierType string, model *simplejson.Json) NotifierBase {
+// groupOptionsTemplate is appended to the options template of notifiers
+// that support sending grouped digest notifications.
+const groupOptionsTemplate = `
+      <h3 class="page-heading">Grouping</h3>
+      <div class="gf-form">
+        <span class="gf-form-label width-10">Group by</span>
+        <div class="gf-form-select-wrapper width-14">
+          <select class="gf-form-input" ng-model="ctrl.model.settings.groupBy" ng-options="t for t in ['', 'channel', 'dashboard', 'tag']">
+          </select>
+        </div>
+      </div>
+      <div class="gf-form" ng-if="ctrl.model.settings.groupBy === 'tag'">
+        <span class="gf-form-label width-10">Tag</span>
+        <input type="text" class="gf-form-input max-width-14" ng-model="ctrl.model.settings.groupByTag" placeholder="series tag key"></input>
+      </div>
+      <div class="gf-form" ng-if="ctrl.model.settings.groupBy">
+        <span class="gf-form-label width-10">Group wait</span>
+        <input type="text" class="gf-form-input max-width-14" ng-model="ctrl.model.settings.groupWait" placeholder="30s"></input>
+        <info-popover mode="right-absolute">
+          How long to wait for more state changes before sending the first digest
+        </info-popover>
+      </div>
+      <div class="gf-form" ng-if="ctrl.model.settings.groupBy">
+        <span class="gf-form-label width-10">Group interval</span>
+        <input type="text" class="gf-form-input max-width-14" ng-model="ctrl.model.settings.groupInterval" placeholder="5m"></input>
+        <info-popover mode="right-absolute">
+          Minimum time between two digests for the same group
+        </info-popover>
+      </div>
+`
+
+// NewNotifierBase fails when the routes of the channel are invalid, so a
+// broken route never sends every notification to the channel.
+func NewNotifierBase(id int64, isDefault bool, name, notifierType string, model *simplejson.Json) (NotifierBase, error) {
 	uploadImage := model.Get("uploadImage").MustBool(true)
 
+	routes, err := alerting.ParseNotificationRoutes(model)
+	if err != nil {
+		return NotifierBase{}, err
+	}
+
 	return NotifierBase{
 		Id:          id,
 		Name:        name,
 		IsDeault:    isDefault,
 		Type:        notifierType,
 		UploadImage: uploadImage,
-	}
+
+		TitleTemplate: model.Get("titleTemplate").MustString(),
+		BodyTemplate:  model.Get("bodyTemplate").MustString(),
+		GroupSettings: alerting.NewGroupSettings(model),
+		Routes:        routes,
+	}, nil
 }
 
+// PassesFilter returns true when the channel has no routes or when any
+// of its routes matches the rule.
 func (n *NotifierBase) PassesFilter(rule *alerting.Rule) bool {
-	return true
+	return alerting.RoutesMatch(n.Routes, rule, time.Now())
+}
+
+// GetTitle returns the notification title, rendered from the channel
+// title template when one is configured.
+func (n *NotifierBase) GetTitle(evalContext *alerting.EvalContext) string {
+	if n.TitleTemplate != "" {
+		title, err := evalContext.RenderTemplate(n.TitleTemplate)
+		if err == nil {
+			return title
+		}
+
+		baseLog.Error("Failed to render title template, using the default title", "notifier", n.Name, "error", err)
+	}
+
+	return evalContext.GetNotificationTitle()
+}
+
+// GetMessage returns the notification body, rendered from the channel
+// body template when one is configured and from the rule message otherwise.
+func (n *NotifierBase) GetMessage(evalContext *alerting.EvalContext) string {
+	if n.BodyTemplate != "" {
+		body, err := evalContext.RenderTemplate(n.BodyTemplate)
+		if err == nil {
+			return body
+		}
+
+		baseLog.Error("Failed to render body template, using the rule message", "notifier", n.Name, "error", err)
+	}
+
+	return evalContext.GetNotificationMessage()
+}
+
+func (n *NotifierBase) GetGroupSettings() *alerting.GroupSettings {
+	return n.GroupSettings
 }
 
 func (n *NotifierBase) GetType() string {
@@ -0,0 +1,43 @@
+package notifiers
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBaseNotifier(t *testing.T) {
+	Convey("Base notifier tests", t, func() {
+		Convey("Passes filter without routes", func() {
+			base, err := NewNotifierBase(1, false, "name", "email", simplejson.New())
+			So(err, ShouldBeNil)
+			So(base.PassesFilter(&alerting.Rule{State: m.AlertStateAlerting}), ShouldBeTrue)
+		})
+
+		Convey("Passes filter when a route matches", func() {
+			json := `{"routes": [{"severity": ["critical"], "tags": {"team": "backend"}}]}`
+			settings, _ := simplejson.NewJson([]byte(json))
+			base, err := NewNotifierBase(1, false, "name", "email", settings)
+			So(err, ShouldBeNil)
+
+			critical := &alerting.Rule{Severity: "critical", Tags: map[string]string{"team": "backend"}}
+			warning := &alerting.Rule{Severity: "warning", Tags: map[string]string{"team": "backend"}}
+
+			So(base.PassesFilter(critical), ShouldBeTrue)
+			So(base.PassesFilter(warning), ShouldBeFalse)
+		})
+
+		Convey("Fails on invalid routes", func() {
+			settings, _ := simplejson.NewJson([]byte(`{"routes": [{"schedule": {"days": ["someday"]}}]}`))
+			_, err := NewNotifierBase(1, false, "name", "email", settings)
+			So(err, ShouldNotBeNil)
+
+			settings.Set("addresses", "ops@example.com")
+			_, err = NewEmailNotifier(&m.AlertNotification{Name: "ops", Type: "email", Settings: settings})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
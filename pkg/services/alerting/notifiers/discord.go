@@ -0,0 +1,153 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "discord",
+		Name:        "Discord",
+		Description: "Sends notifications to Discord",
+		Factory:     NewDiscordNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">Discord settings</h3>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-6">Url</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.url" placeholder="Discord webhook url"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-6">Content</span>
+        <input type="text"
+          class="gf-form-input max-width-30"
+          ng-model="ctrl.model.settings.content"
+          data-placement="right">
+        </input>
+        <info-popover mode="right-absolute">
+          Message text sent above the embed, use it to mention users or roles
+        </info-popover>
+      </div>
+    `,
+	})
+
+}
+
+func NewDiscordNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	url := model.Settings.Get("url").MustString()
+	if url == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find url property in settings"}
+	}
+
+	base, err := NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiscordNotifier{
+		NotifierBase: base,
+		Url:          url,
+		Content:      model.Settings.Get("content").MustString(),
+		log:          log.New("alerting.notifier.discord"),
+	}, nil
+}
+
+type DiscordNotifier struct {
+	NotifierBase
+	Url     string
+	Content string
+	log     log.Logger
+}
+
+func (this *DiscordNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Executing discord notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+	metrics.M_Alerting_Notification_Sent_Discord.Inc(1)
+
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+		return err
+	}
+
+	fields := make([]map[string]interface{}, 0)
+	for index, evt := range evalContext.EvalMatches {
+		fields = append(fields, map[string]interface{}{
+			"name":   evt.Metric,
+			"value":  evt.Value.String(),
+			"inline": true,
+		})
+		if index > maxFieldCount {
+			break
+		}
+	}
+
+	if evalContext.Error != nil {
+		fields = append(fields, map[string]interface{}{
+			"name":   "Error message",
+			"value":  evalContext.Error.Error(),
+			"inline": false,
+		})
+	}
+
+	description := ""
+	if evalContext.Rule.State != m.AlertStateOK { //dont add message when going back to alert state ok.
+		description = this.GetMessage(evalContext)
+	}
+
+	embed := map[string]interface{}{
+		"title":       this.GetTitle(evalContext),
+		"url":         ruleUrl,
+		"description": description,
+		"color":       discordColor(evalContext.GetStateModel().Color),
+		"fields":      fields,
+		"footer": map[string]interface{}{
+			"text":     "Grafana v" + setting.BuildVersion,
+			"icon_url": "https://grafana.com/assets/img/fav32.png",
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if evalContext.ImagePublicUrl != "" {
+		embed["image"] = map[string]interface{}{
+			"url": evalContext.ImagePublicUrl,
+		}
+	}
+
+	body := map[string]interface{}{
+		"embeds": []map[string]interface{}{embed},
+	}
+
+	if this.Content != "" {
+		body["content"] = this.Content
+	}
+
+	data, _ := json.Marshal(&body)
+	cmd := &m.SendWebhookSync{Url: this.Url, Body: string(data)}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		this.log.Error("Failed to send discord notification", "error", err, "webhook", this.Name)
+		return err
+	}
+
+	return nil
+}
+
+// discordColor converts a hex color like #D63232 to the integer
+// representation used by Discord embeds.
+func discordColor(hex string) int64 {
+	color, err := strconv.ParseInt(strings.TrimPrefix(hex, "#"), 16, 64)
+	if err != nil {
+		return 0
+	}
+
+	return color
+}
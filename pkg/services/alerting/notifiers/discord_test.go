@@ -0,0 +1,79 @@
+package notifiers
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDiscordNotifier(t *testing.T) {
+	Convey("Discord notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "ops",
+					Type:     "discord",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewDiscordNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+          "url": "https://discordapp.com/api/webhooks/1/abc"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "ops",
+					Type:     "discord",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewDiscordNotifier(model)
+				discordNotifier := not.(*DiscordNotifier)
+
+				So(err, ShouldBeNil)
+				So(discordNotifier.Name, ShouldEqual, "ops")
+				So(discordNotifier.Type, ShouldEqual, "discord")
+				So(discordNotifier.Url, ShouldEqual, "https://discordapp.com/api/webhooks/1/abc")
+				So(discordNotifier.Content, ShouldEqual, "")
+			})
+
+			Convey("from settings with content", func() {
+				json := `
+				{
+          "url": "https://discordapp.com/api/webhooks/1/abc",
+          "content": "@here"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "ops",
+					Type:     "discord",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewDiscordNotifier(model)
+				discordNotifier := not.(*DiscordNotifier)
+
+				So(err, ShouldBeNil)
+				So(discordNotifier.Content, ShouldEqual, "@here")
+			})
+		})
+
+		Convey("Should convert state color", func() {
+			So(discordColor("#D63232"), ShouldEqual, 0xD63232)
+			So(discordColor("invalid"), ShouldEqual, 0)
+		})
+	})
+}
@@ -1,6 +1,8 @@
 package notifiers
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"strings"
 
@@ -26,25 +28,50 @@ func init() {
       <div class="gf-form">
       <span>You can enter multiple email addresses using a ";" separator</span>
       </div>
-    `,
+      <h3 class="page-heading">Teams</h3>
+      <div class="gf-form">
+         <input type="text" class="gf-form-input width-25" ng-model="ctrl.model.settings.teams" placeholder="team names"></input>
+      </div>
+      <div class="gf-form">
+      <span>Members of the teams are notified, or the team email if the team has one</span>
+      </div>
+    ` + groupOptionsTemplate,
 	})
 }
 
 type EmailNotifier struct {
 	NotifierBase
+	OrgId     int64
 	Addresses []string
+	Teams     []string
 	log       log.Logger
 }
 
 func NewEmailNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
-	addressesString := model.Settings.Get("addresses").MustString()
+	addresses := splitEmailSetting(model.Settings.Get("addresses").MustString())
+	teams := splitEmailSetting(model.Settings.Get("teams").MustString())
 
-	if addressesString == "" {
-		return nil, alerting.ValidationError{Reason: "Could not find addresses in settings"}
+	if len(addresses) == 0 && len(teams) == 0 {
+		return nil, alerting.ValidationError{Reason: "Could not find addresses or teams in settings"}
 	}
 
-	// split addresses with a few different ways
-	addresses := strings.FieldsFunc(addressesString, func(r rune) bool {
+	base, err := NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailNotifier{
+		NotifierBase: base,
+		OrgId:        model.OrgId,
+		Addresses:    addresses,
+		Teams:        teams,
+		log:          log.New("alerting.notifier.email"),
+	}, nil
+}
+
+// split addresses and team names with a few different ways
+func splitEmailSetting(value string) []string {
+	items := strings.FieldsFunc(value, func(r rune) bool {
 		switch r {
 		case ',', ';', '\n':
 			return true
@@ -52,15 +79,70 @@ func NewEmailNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
 		return false
 	})
 
-	return &EmailNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
-		Addresses:    addresses,
-		log:          log.New("alerting.notifier.email"),
-	}, nil
+	result := make([]string, 0)
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// recipients returns the addresses together with the emails of the teams.
+// Teams with an email are notified on that address, other teams on the
+// addresses of their members.
+func (this *EmailNotifier) recipients() ([]string, error) {
+	result := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(address string) {
+		if address != "" && !seen[address] {
+			seen[address] = true
+			result = append(result, address)
+		}
+	}
+
+	for _, address := range this.Addresses {
+		add(address)
+	}
+
+	for _, name := range this.Teams {
+		teamQuery := &m.SearchTeamsQuery{OrgId: this.OrgId, Name: name, Limit: 1, Page: 1}
+		if err := bus.Dispatch(teamQuery); err != nil {
+			return nil, err
+		}
+
+		if len(teamQuery.Result.Teams) == 0 {
+			this.log.Warn("Could not find team to notify", "team", name)
+			continue
+		}
+
+		team := teamQuery.Result.Teams[0]
+		if team.Email != "" {
+			add(team.Email)
+			continue
+		}
+
+		membersQuery := &m.GetTeamMembersQuery{OrgId: this.OrgId, TeamId: team.Id}
+		if err := bus.Dispatch(membersQuery); err != nil {
+			return nil, err
+		}
+
+		for _, member := range membersQuery.Result {
+			add(member.Email)
+		}
+	}
+
+	return result, nil
 }
 
 func (this *EmailNotifier) Notify(evalContext *alerting.EvalContext) error {
-	this.log.Info("Sending alert notification to", "addresses", this.Addresses)
+	addresses, err := this.recipients()
+	if err != nil {
+		this.log.Error("Failed to get team emails", "error", err)
+		return err
+	}
+
+	this.log.Info("Sending alert notification to", "addresses", addresses)
 	metrics.M_Alerting_Notification_Sent_Email.Inc(1)
 
 	ruleUrl, err := evalContext.GetRuleUrl()
@@ -71,20 +153,20 @@ func (this *EmailNotifier) Notify(evalContext *alerting.EvalContext) error {
 
 	cmd := &m.SendEmailCommandSync{
 		SendEmailCommand: m.SendEmailCommand{
-			Subject: evalContext.GetNotificationTitle(),
+			Subject: this.GetTitle(evalContext),
 			Data: map[string]interface{}{
-				"Title":        evalContext.GetNotificationTitle(),
+				"Title":        this.GetTitle(evalContext),
 				"State":        evalContext.Rule.State,
 				"Name":         evalContext.Rule.Name,
 				"StateModel":   evalContext.GetStateModel(),
-				"Message":      evalContext.Rule.Message,
+				"Message":      this.GetMessage(evalContext),
 				"RuleUrl":      ruleUrl,
 				"ImageLink":    "",
 				"EmbededImage": "",
 				"AlertPageUrl": setting.AppUrl + "alerting",
 				"EvalMatches":  evalContext.EvalMatches,
 			},
-			To:           this.Addresses,
+			To:           addresses,
 			Template:     "alert_notification.html",
 			EmbededFiles: []string{},
 		},
@@ -109,3 +191,56 @@ func (this *EmailNotifier) Notify(evalContext *alerting.EvalContext) error {
 	return nil
 
 }
+
+func (this *EmailNotifier) NotifyGroup(ctx context.Context, evalContexts []*alerting.EvalContext) error {
+	addresses, err := this.recipients()
+	if err != nil {
+		this.log.Error("Failed to get team emails", "error", err)
+		return err
+	}
+
+	this.log.Info("Sending grouped alert notification to", "addresses", addresses, "count", len(evalContexts))
+	metrics.M_Alerting_Notification_Sent_Email.Inc(1)
+
+	alerts := make([]map[string]interface{}, 0)
+	for _, evalContext := range evalContexts {
+		ruleUrl, err := evalContext.GetRuleUrl()
+		if err != nil {
+			this.log.Error("Failed get rule link", "error", err)
+			return err
+		}
+
+		alerts = append(alerts, map[string]interface{}{
+			"Title":       this.GetTitle(evalContext),
+			"State":       evalContext.Rule.State,
+			"Name":        evalContext.Rule.Name,
+			"StateModel":  evalContext.GetStateModel(),
+			"Message":     this.GetMessage(evalContext),
+			"RuleUrl":     ruleUrl,
+			"ImageLink":   evalContext.ImagePublicUrl,
+			"EvalMatches": evalContext.EvalMatches,
+		})
+	}
+
+	title := fmt.Sprintf("[%d alerts] State changes", len(evalContexts))
+	cmd := &m.SendEmailCommandSync{
+		SendEmailCommand: m.SendEmailCommand{
+			Subject: title,
+			Data: map[string]interface{}{
+				"Title":        title,
+				"Alerts":       alerts,
+				"AlertPageUrl": setting.AppUrl + "alerting",
+			},
+			To:           addresses,
+			Template:     "alert_notification_group.html",
+			EmbededFiles: []string{},
+		},
+	}
+
+	if err := bus.DispatchCtx(ctx, cmd); err != nil {
+		this.log.Error("Failed to send grouped alert notification email", "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package notifiers
 import (
 	"testing"
 
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	m "github.com/grafana/grafana/pkg/models"
 	. "github.com/smartystreets/goconvey/convey"
@@ -74,6 +75,51 @@ func TestEmailNotifier(t *testing.T) {
 				So(emailNotifier.Addresses[0], ShouldEqual, "ops@grafana.org")
 				So(emailNotifier.Addresses[1], ShouldEqual, "dev@grafana.org")
 			})
+
+			Convey("from settings with teams", func() {
+				json := `
+				{
+					"addresses": "ops@grafana.org",
+					"teams": "backend; frontend"
+				}`
+
+				settingsJSON, err := simplejson.NewJson([]byte(json))
+				So(err, ShouldBeNil)
+
+				model := &m.AlertNotification{
+					OrgId:    1,
+					Name:     "ops",
+					Type:     "email",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewEmailNotifier(model)
+				So(err, ShouldBeNil)
+				emailNotifier := not.(*EmailNotifier)
+				So(emailNotifier.Teams, ShouldResemble, []string{"backend", "frontend"})
+
+				bus.AddHandler("test", func(query *m.SearchTeamsQuery) error {
+					switch query.Name {
+					case "backend":
+						query.Result.Teams = []*m.SearchTeamDto{{Id: 1, OrgId: 1, Name: "backend"}}
+					case "frontend":
+						query.Result.Teams = []*m.SearchTeamDto{{Id: 2, OrgId: 1, Name: "frontend", Email: "frontend@grafana.org"}}
+					}
+					return nil
+				})
+
+				bus.AddHandler("test", func(query *m.GetTeamMembersQuery) error {
+					query.Result = []*m.TeamMemberDTO{
+						{TeamId: 1, UserId: 1, Email: "alice@grafana.org"},
+						{TeamId: 1, UserId: 2, Email: "ops@grafana.org"},
+					}
+					return nil
+				})
+
+				addresses, err := emailNotifier.recipients()
+				So(err, ShouldBeNil)
+				So(addresses, ShouldResemble, []string{"ops@grafana.org", "alice@grafana.org", "frontend@grafana.org"})
+			})
 		})
 	})
 }
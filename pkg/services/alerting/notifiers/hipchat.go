@@ -58,8 +58,13 @@ func NewHipChatNotifier(model *models.AlertNotification) (alerting.Notifier, err
 	apikey := model.Settings.Get("apikey").MustString()
 	roomId := model.Settings.Get("roomid").MustString()
 
+	base, err := NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
 	return &HipChatNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: base,
 		Url:          url,
 		ApiKey:       apikey,
 		RoomId:       roomId,
@@ -84,7 +89,7 @@ func (this *HipChatNotifier) Notify(evalContext *alerting.EvalContext) error {
 		return err
 	}
 
-	message := evalContext.GetNotificationTitle() + " in state " + evalContext.GetStateModel().Text + "<br><a href=" + ruleUrl + ">Check Dasboard</a>"
+	message := this.GetTitle(evalContext) + " in state " + evalContext.GetStateModel().Text + "<br><a href=" + ruleUrl + ">Check Dasboard</a>"
 	fields := make([]map[string]interface{}, 0)
 	message += "<br>"
 	for index, evt := range evalContext.EvalMatches {
@@ -108,7 +113,7 @@ func (this *HipChatNotifier) Notify(evalContext *alerting.EvalContext) error {
 	}
 
 	if evalContext.Rule.State != models.AlertStateOK { //dont add message when going back to alert state ok.
-		message += " " + evalContext.Rule.Message
+		message += " " + this.GetMessage(evalContext)
 	}
 	//HipChat has a set list of colors
 	var color string
@@ -126,8 +131,8 @@ func (this *HipChatNotifier) Notify(evalContext *alerting.EvalContext) error {
 		"style":       "link",
 		"url":         ruleUrl,
 		"id":          "1",
-		"title":       evalContext.GetNotificationTitle(),
-		"description": evalContext.GetNotificationTitle() + " in state " + evalContext.GetStateModel().Text,
+		"title":       this.GetTitle(evalContext),
+		"description": this.GetTitle(evalContext) + " in state " + evalContext.GetStateModel().Text,
 		"icon": map[string]interface{}{
 			"url": "https://grafana.com/assets/img/fav32.png",
 		},
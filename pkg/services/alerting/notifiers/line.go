@@ -38,8 +38,13 @@ func NewLINENotifier(model *m.AlertNotification) (alerting.Notifier, error) {
 		return nil, alerting.ValidationError{Reason: "Could not find token in settings"}
 	}
 
+	base, err := NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
 	return &LineNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: base,
 		Token:        token,
 		log:          log.New("alerting.notifier.line"),
 	}, nil
@@ -72,7 +77,7 @@ func (this *LineNotifier) createAlert(evalContext *alerting.EvalContext) error {
 	}
 
 	form := url.Values{}
-	body := fmt.Sprintf("%s - %s\n%s", evalContext.Rule.Name, ruleUrl, evalContext.Rule.Message)
+	body := fmt.Sprintf("%s - %s\n%s", evalContext.Rule.Name, ruleUrl, this.GetMessage(evalContext))
 	form.Add("message", body)
 
 	cmd := &m.SendWebhookSync{
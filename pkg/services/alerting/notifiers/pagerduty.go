@@ -1,7 +1,10 @@
 package notifiers
 
 import (
+	"context"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -32,7 +35,7 @@ func init() {
            tooltip="Resolve incidents in pagerduty once the alert goes back to ok.">
         </gf-form-switch>
       </div>
-    `,
+    ` + groupOptionsTemplate,
 	})
 }
 
@@ -47,8 +50,13 @@ func NewPagerdutyNotifier(model *m.AlertNotification) (alerting.Notifier, error)
 		return nil, alerting.ValidationError{Reason: "Could not find integration key property in settings"}
 	}
 
+	base, err := NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
 	return &PagerdutyNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: base,
 		Key:          key,
 		AutoResolve:  autoResolve,
 		log:          log.New("alerting.notifier.pagerduty"),
@@ -79,7 +87,7 @@ func (this *PagerdutyNotifier) Notify(evalContext *alerting.EvalContext) error {
 
 	bodyJSON := simplejson.New()
 	bodyJSON.Set("service_key", this.Key)
-	bodyJSON.Set("description", evalContext.Rule.Name+" - "+evalContext.Rule.Message)
+	bodyJSON.Set("description", evalContext.Rule.Name+" - "+this.GetMessage(evalContext))
 	bodyJSON.Set("client", "Grafana")
 	bodyJSON.Set("event_type", eventType)
 	bodyJSON.Set("incident_key", "alertId-"+strconv.FormatInt(evalContext.Rule.Id, 10))
@@ -100,6 +108,81 @@ func (this *PagerdutyNotifier) Notify(evalContext *alerting.EvalContext) error {
 		bodyJSON.Set("contexts", contexts)
 	}
 
+	return this.sendEvent(evalContext.Ctx, bodyJSON)
+}
+
+// NotifyAcknowledgement acknowledges the PagerDuty incident of the alert.
+// PagerDuty cannot unacknowledge incidents so removed acknowledgements are
+// not reported.
+func (this *PagerdutyNotifier) NotifyAcknowledgement(evalContext *alerting.EvalContext) error {
+	if !evalContext.Acknowledgement.Acknowledged {
+		return nil
+	}
+
+	metrics.M_Alerting_Notification_Sent_PagerDuty.Inc(1)
+	this.log.Info("Notifying Pagerduty", "event_type", "acknowledge")
+
+	bodyJSON := simplejson.New()
+	bodyJSON.Set("service_key", this.Key)
+	bodyJSON.Set("description", evalContext.Acknowledgement.Text())
+	bodyJSON.Set("event_type", "acknowledge")
+	bodyJSON.Set("incident_key", "alertId-"+strconv.FormatInt(evalContext.Rule.Id, 10))
+
+	return this.sendEvent(evalContext.Ctx, bodyJSON)
+}
+
+// NotifyGroup sends one PagerDuty event for the whole group. The incident
+// is triggered while any alert in the group is not ok and resolved once all
+// of them are.
+func (this *PagerdutyNotifier) NotifyGroup(ctx context.Context, evalContexts []*alerting.EvalContext) error {
+	metrics.M_Alerting_Notification_Sent_PagerDuty.Inc(1)
+
+	eventType := "resolve"
+	names := make([]string, 0)
+	details := make([]interface{}, 0)
+	contexts := make([]interface{}, 0)
+
+	for _, evalContext := range evalContexts {
+		if evalContext.Rule.State != m.AlertStateOK {
+			eventType = "trigger"
+			names = append(names, evalContext.Rule.Name)
+		}
+
+		detail := simplejson.New()
+		detail.Set("ruleName", evalContext.Rule.Name)
+		detail.Set("state", evalContext.Rule.State)
+		detail.Set("message", this.GetMessage(evalContext))
+		if ruleUrl, err := evalContext.GetRuleUrl(); err == nil {
+			detail.Set("ruleUrl", ruleUrl)
+			link := simplejson.New()
+			link.Set("type", "link")
+			link.Set("href", ruleUrl)
+			link.Set("text", evalContext.Rule.Name)
+			contexts = append(contexts, link)
+		}
+		details = append(details, detail)
+	}
+
+	if eventType == "resolve" && !this.AutoResolve {
+		this.log.Info("Not sending a grouped trigger to Pagerduty", "auto resolve", this.AutoResolve)
+		return nil
+	}
+
+	this.log.Info("Notifying Pagerduty with grouped event", "event_type", eventType, "count", len(evalContexts))
+
+	bodyJSON := simplejson.New()
+	bodyJSON.Set("service_key", this.Key)
+	bodyJSON.Set("description", fmt.Sprintf("%d alerts firing: %s", len(names), strings.Join(names, ", ")))
+	bodyJSON.Set("client", "Grafana")
+	bodyJSON.Set("event_type", eventType)
+	bodyJSON.Set("incident_key", fmt.Sprintf("notifierId-%d-%s", this.Id, this.GroupSettings.GroupKey(evalContexts[0])))
+	bodyJSON.Set("details", details)
+	bodyJSON.Set("contexts", contexts)
+
+	return this.sendEvent(ctx, bodyJSON)
+}
+
+func (this *PagerdutyNotifier) sendEvent(ctx context.Context, bodyJSON *simplejson.Json) error {
 	body, _ := bodyJSON.MarshalJSON()
 
 	cmd := &m.SendWebhookSync{
@@ -108,7 +191,7 @@ func (this *PagerdutyNotifier) Notify(evalContext *alerting.EvalContext) error {
 		HttpMethod: "POST",
 	}
 
-	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+	if err := bus.DispatchCtx(ctx, cmd); err != nil {
 		this.log.Error("Failed to send notification to Pagerduty", "error", err, "body", string(body))
 		return err
 	}
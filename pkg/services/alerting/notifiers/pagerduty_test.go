@@ -1,10 +1,13 @@
 package notifiers
 
 import (
+	"context"
 	"testing"
 
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -49,5 +52,37 @@ func TestPagerdutyNotifier(t *testing.T) {
 			})
 
 		})
+
+		Convey("Acknowledging alerts", func() {
+			var sent *m.SendWebhookSync
+			bus.AddCtxHandler("test", func(ctx context.Context, cmd *m.SendWebhookSync) error {
+				sent = cmd
+				return nil
+			})
+
+			settingsJSON, _ := simplejson.NewJson([]byte(`{ "integrationKey": "abcdefgh0123456789" }`))
+			not, _ := NewPagerdutyNotifier(&m.AlertNotification{Name: "pd", Type: "pagerduty", Settings: settingsJSON})
+			pagerdutyNotifier := not.(*PagerdutyNotifier)
+
+			evalContext := alerting.NewEvalContext(context.TODO(), &alerting.Rule{Id: 5, Name: "High CPU", State: m.AlertStateAlerting})
+			evalContext.Acknowledgement = &alerting.Acknowledgement{Acknowledged: true, Login: "admin", Comment: "on it"}
+
+			Convey("should acknowledge the incident of the alert", func() {
+				err := pagerdutyNotifier.NotifyAcknowledgement(evalContext)
+				So(err, ShouldBeNil)
+
+				body, _ := simplejson.NewJson([]byte(sent.Body))
+				So(body.Get("event_type").MustString(), ShouldEqual, "acknowledge")
+				So(body.Get("incident_key").MustString(), ShouldEqual, "alertId-5")
+				So(body.Get("description").MustString(), ShouldEqual, "Acknowledged by admin: on it")
+			})
+
+			Convey("should not send removed acknowledgements", func() {
+				evalContext.Acknowledgement.Acknowledged = false
+				err := pagerdutyNotifier.NotifyAcknowledgement(evalContext)
+				So(err, ShouldBeNil)
+				So(sent, ShouldBeNil)
+			})
+		})
 	})
 }
@@ -99,8 +99,14 @@ func NewPushoverNotifier(model *m.AlertNotification) (alerting.Notifier, error)
 	if apiToken == "" {
 		return nil, alerting.ValidationError{Reason: "API token not given"}
 	}
+
+	base, err := NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
 	return &PushoverNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: base,
 		UserKey:      userKey,
 		ApiToken:     apiToken,
 		Priority:     priority,
@@ -131,7 +137,7 @@ func (this *PushoverNotifier) Notify(evalContext *alerting.EvalContext) error {
 		this.log.Error("Failed get rule link", "error", err)
 		return err
 	}
-	message := evalContext.Rule.Message
+	message := this.GetMessage(evalContext)
 	for idx, evt := range evalContext.EvalMatches {
 		message += fmt.Sprintf("\n<b>%s</b>: %v", evt.Metric, evt.Value)
 		if idx > 4 {
@@ -159,7 +165,7 @@ func (this *PushoverNotifier) Notify(evalContext *alerting.EvalContext) error {
 	if this.Sound != "default" {
 		q.Add("sound", this.Sound)
 	}
-	q.Add("title", evalContext.GetNotificationTitle())
+	q.Add("title", this.GetTitle(evalContext))
 	q.Add("url", ruleUrl)
 	q.Add("url_title", "Show dashboard with alert")
 	q.Add("message", message)
@@ -1,7 +1,9 @@
 package notifiers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
@@ -46,7 +48,7 @@ func init() {
           Mention a user or a group using @ when notifying in a channel
         </info-popover>
       </div>
-    `,
+    ` + groupOptionsTemplate,
 	})
 
 }
@@ -60,8 +62,13 @@ func NewSlackNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
 	recipient := model.Settings.Get("recipient").MustString()
 	mention := model.Settings.Get("mention").MustString()
 
+	base, err := NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
 	return &SlackNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: base,
 		Url:          url,
 		Recipient:    recipient,
 		Mention:      mention,
@@ -81,12 +88,84 @@ func (this *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 	this.log.Info("Executing slack notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
 	metrics.M_Alerting_Notification_Sent_Slack.Inc(1)
 
+	attachment, err := this.buildAttachment(evalContext, this.Mention)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]interface{}{
+		"attachments": []map[string]interface{}{attachment},
+		"parse":       "full", // to linkify urls, users and channels in alert message.
+	}
+
+	return this.sendBody(evalContext.Ctx, body)
+}
+
+func (this *SlackNotifier) NotifyGroup(ctx context.Context, evalContexts []*alerting.EvalContext) error {
+	this.log.Info("Executing grouped slack notification", "count", len(evalContexts), "notification", this.Name)
+	metrics.M_Alerting_Notification_Sent_Slack.Inc(1)
+
+	attachments := make([]map[string]interface{}, 0)
+	for _, evalContext := range evalContexts {
+		attachment, err := this.buildAttachment(evalContext, "")
+		if err != nil {
+			return err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	text := fmt.Sprintf("%d alerts changed state", len(evalContexts))
+	if this.Mention != "" {
+		text = this.Mention + " " + text
+	}
+
+	body := map[string]interface{}{
+		"text":        text,
+		"attachments": attachments,
+		"parse":       "full",
+	}
+
+	return this.sendBody(ctx, body)
+}
+
+// NotifyAcknowledgement posts who acknowledged the alert to the channel.
+func (this *SlackNotifier) NotifyAcknowledgement(evalContext *alerting.EvalContext) error {
+	this.log.Info("Executing slack acknowledgement notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+	metrics.M_Alerting_Notification_Sent_Slack.Inc(1)
+
 	ruleUrl, err := evalContext.GetRuleUrl()
 	if err != nil {
 		this.log.Error("Failed get rule link", "error", err)
 		return err
 	}
 
+	text := evalContext.Acknowledgement.Text()
+	body := map[string]interface{}{
+		"attachments": []map[string]interface{}{
+			{
+				"fallback":    text,
+				"color":       "#F2C037",
+				"title":       evalContext.Rule.Name,
+				"title_link":  ruleUrl,
+				"text":        text,
+				"footer":      "Grafana v" + setting.BuildVersion,
+				"footer_icon": "https://grafana.com/assets/img/fav32.png",
+				"ts":          time.Now().Unix(),
+			},
+		},
+		"parse": "full",
+	}
+
+	return this.sendBody(evalContext.Ctx, body)
+}
+
+func (this *SlackNotifier) buildAttachment(evalContext *alerting.EvalContext, mention string) (map[string]interface{}, error) {
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+		return nil, err
+	}
+
 	fields := make([]map[string]interface{}, 0)
 	fieldLimitCount := 4
 	for index, evt := range evalContext.EvalMatches {
@@ -108,29 +187,26 @@ func (this *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 		})
 	}
 
-	message := this.Mention
+	message := mention
 	if evalContext.Rule.State != m.AlertStateOK { //dont add message when going back to alert state ok.
-		message += " " + evalContext.Rule.Message
+		message += " " + this.GetMessage(evalContext)
 	}
 
-	body := map[string]interface{}{
-		"attachments": []map[string]interface{}{
-			{
-				"fallback":    evalContext.GetNotificationTitle(),
-				"color":       evalContext.GetStateModel().Color,
-				"title":       evalContext.GetNotificationTitle(),
-				"title_link":  ruleUrl,
-				"text":        message,
-				"fields":      fields,
-				"image_url":   evalContext.ImagePublicUrl,
-				"footer":      "Grafana v" + setting.BuildVersion,
-				"footer_icon": "https://grafana.com/assets/img/fav32.png",
-				"ts":          time.Now().Unix(),
-			},
-		},
-		"parse": "full", // to linkify urls, users and channels in alert message.
-	}
+	return map[string]interface{}{
+		"fallback":    this.GetTitle(evalContext),
+		"color":       evalContext.GetStateModel().Color,
+		"title":       this.GetTitle(evalContext),
+		"title_link":  ruleUrl,
+		"text":        message,
+		"fields":      fields,
+		"image_url":   evalContext.ImagePublicUrl,
+		"footer":      "Grafana v" + setting.BuildVersion,
+		"footer_icon": "https://grafana.com/assets/img/fav32.png",
+		"ts":          time.Now().Unix(),
+	}, nil
+}
 
+func (this *SlackNotifier) sendBody(ctx context.Context, body map[string]interface{}) error {
 	//recipient override
 	if this.Recipient != "" {
 		body["channel"] = this.Recipient
@@ -139,7 +215,7 @@ func (this *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 	data, _ := json.Marshal(&body)
 	cmd := &m.SendWebhookSync{Url: this.Url, Body: string(data)}
 
-	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+	if err := bus.DispatchCtx(ctx, cmd); err != nil {
 		this.log.Error("Failed to send slack notification", "error", err, "webhook", this.Name)
 		return err
 	}
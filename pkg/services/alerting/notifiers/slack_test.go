@@ -2,9 +2,11 @@ package notifiers
 
 import (
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -76,6 +78,31 @@ func TestSlackNotifier(t *testing.T) {
 				So(slackNotifier.Mention, ShouldEqual, "@carl")
 			})
 
+			Convey("from settings with grouping", func() {
+				json := `
+				{
+          "url": "http://google.com",
+          "groupBy": "dashboard",
+          "groupWait": "1m"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "ops",
+					Type:     "slack",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewSlackNotifier(model)
+				So(err, ShouldBeNil)
+
+				groupNotifier, ok := not.(alerting.GroupNotifier)
+				So(ok, ShouldBeTrue)
+				So(groupNotifier.GetGroupSettings().Enabled(), ShouldBeTrue)
+				So(groupNotifier.GetGroupSettings().By, ShouldEqual, alerting.GroupByDashboard)
+				So(groupNotifier.GetGroupSettings().Wait, ShouldEqual, time.Minute)
+			})
+
 		})
 	})
 }
@@ -39,8 +39,13 @@ func NewVictoropsNotifier(model *models.AlertNotification) (alerting.Notifier, e
 		return nil, alerting.ValidationError{Reason: "Could not find victorops url property in settings"}
 	}
 
+	base, err := NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
 	return &VictoropsNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: base,
 		URL:          url,
 		log:          log.New("alerting.notifier.victorops"),
 	}, nil
@@ -97,7 +102,7 @@ func (this *VictoropsNotifier) Notify(evalContext *alerting.EvalContext) error {
 		"entity_id":        evalContext.Rule.Name,
 		"timestamp":        time.Now().Unix(),
 		"state_start_time": evalContext.StartTime.Unix(),
-		"state_message":    evalContext.Rule.Message + "\n" + ruleUrl,
+		"state_message":    this.GetMessage(evalContext) + "\n" + ruleUrl,
 		"monitoring_tool":  "Grafana v" + setting.BuildVersion,
 	}
 
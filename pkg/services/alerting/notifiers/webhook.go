@@ -1,6 +1,9 @@
 package notifiers
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/log"
@@ -36,7 +39,7 @@ func init() {
         <span class="gf-form-label width-10">Password</span>
         <input type="text" class="gf-form-input max-width-14" ng-model="ctrl.model.settings.password"></input>
       </div>
-    `,
+    ` + groupOptionsTemplate,
 	})
 
 }
@@ -47,8 +50,13 @@ func NewWebHookNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
 		return nil, alerting.ValidationError{Reason: "Could not find url property in settings"}
 	}
 
+	base, err := NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
 	return &WebhookNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: base,
 		Url:          url,
 		User:         model.Settings.Get("username").MustString(),
 		Password:     model.Settings.Get("password").MustString(),
@@ -70,8 +78,28 @@ func (this *WebhookNotifier) Notify(evalContext *alerting.EvalContext) error {
 	this.log.Info("Sending webhook")
 	metrics.M_Alerting_Notification_Sent_Webhook.Inc(1)
 
+	return this.sendBody(evalContext.Ctx, this.buildBody(evalContext))
+}
+
+func (this *WebhookNotifier) NotifyGroup(ctx context.Context, evalContexts []*alerting.EvalContext) error {
+	this.log.Info("Sending grouped webhook", "count", len(evalContexts))
+	metrics.M_Alerting_Notification_Sent_Webhook.Inc(1)
+
+	alerts := make([]*simplejson.Json, 0)
+	for _, evalContext := range evalContexts {
+		alerts = append(alerts, this.buildBody(evalContext))
+	}
+
 	bodyJSON := simplejson.New()
-	bodyJSON.Set("title", evalContext.GetNotificationTitle())
+	bodyJSON.Set("title", fmt.Sprintf("%d alerts changed state", len(evalContexts)))
+	bodyJSON.Set("alerts", alerts)
+
+	return this.sendBody(ctx, bodyJSON)
+}
+
+func (this *WebhookNotifier) buildBody(evalContext *alerting.EvalContext) *simplejson.Json {
+	bodyJSON := simplejson.New()
+	bodyJSON.Set("title", this.GetTitle(evalContext))
 	bodyJSON.Set("ruleId", evalContext.Rule.Id)
 	bodyJSON.Set("ruleName", evalContext.Rule.Name)
 	bodyJSON.Set("state", evalContext.Rule.State)
@@ -86,10 +114,14 @@ func (this *WebhookNotifier) Notify(evalContext *alerting.EvalContext) error {
 		bodyJSON.Set("imageUrl", evalContext.ImagePublicUrl)
 	}
 
-	if evalContext.Rule.Message != "" {
-		bodyJSON.Set("message", evalContext.Rule.Message)
+	if message := this.GetMessage(evalContext); message != "" {
+		bodyJSON.Set("message", message)
 	}
 
+	return bodyJSON
+}
+
+func (this *WebhookNotifier) sendBody(ctx context.Context, bodyJSON *simplejson.Json) error {
 	body, _ := bodyJSON.MarshalJSON()
 
 	cmd := &m.SendWebhookSync{
@@ -100,7 +132,7 @@ func (this *WebhookNotifier) Notify(evalContext *alerting.EvalContext) error {
 		HttpMethod: this.HttpMethod,
 	}
 
-	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+	if err := bus.DispatchCtx(ctx, cmd); err != nil {
 		this.log.Error("Failed to send webhook", "error", err, "webhook", this.Name)
 		return err
 	}
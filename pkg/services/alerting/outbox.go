@@ -0,0 +1,302 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+var (
+	outboxMaxAttempts  int           = 5
+	outboxRetryBackoff time.Duration = time.Second * 30
+	outboxMaxBackoff   time.Duration = time.Hour
+	outboxPollInterval time.Duration = time.Second * 10
+)
+
+// notificationOutbox persists every notification before it is sent and
+// retries failed deliveries with exponential backoff. Notifications of
+// grouping notifiers are sent as digests by its grouper.
+type notificationOutbox struct {
+	log     log.Logger
+	grouper *notificationGrouper
+	// ownsAlert limits retries to the alerts this server evaluates
+	ownsAlert func(alertId int64) bool
+}
+
+func newNotificationOutbox() *notificationOutbox {
+	outbox := &notificationOutbox{
+		log:     log.New("alerting.outbox"),
+		grouper: newNotificationGrouper(),
+	}
+	outbox.grouper.sent = outbox.groupSent
+
+	return outbox
+}
+
+// Send stores the notification in the outbox and makes the first
+// delivery attempt.
+func (o *notificationOutbox) Send(evalContext *EvalContext, notifier Notifier) error {
+	// leave time for the first attempt before the delivery can be retried
+	cmd := newDeliveryCommand(evalContext, notifier, time.Now().Add(alertTimeout+outboxRetryBackoff))
+
+	if err := bus.Dispatch(cmd); err != nil {
+		if err == m.ErrNotificationAlreadySent {
+			o.log.Info("Notification already sent by another server", "alertId", cmd.AlertId, "notifierId", cmd.NotifierId)
+			return nil
+		}
+
+		o.log.Error("Failed to store notification in outbox", "error", err)
+		return notifyWithMetrics(notifier, evalContext)
+	}
+
+	return o.deliver(evalContext, notifier, cmd.Result)
+}
+
+// Queue stores the notification in the outbox and adds it to the group of
+// the notifier. The delivery is not retried while its digest is pending,
+// pending digests lost on restart are picked up again by the retries.
+func (o *notificationOutbox) Queue(evalContext *EvalContext, notifier GroupNotifier) {
+	cmd := newDeliveryCommand(evalContext, notifier, time.Now().Add(groupHoldTime(notifier)))
+
+	if err := bus.Dispatch(cmd); err != nil {
+		if err == m.ErrNotificationAlreadySent {
+			o.log.Info("Notification already sent by another server", "alertId", cmd.AlertId, "notifierId", cmd.NotifierId)
+			return
+		}
+
+		o.log.Error("Failed to store notification in outbox", "error", err)
+	}
+
+	o.grouper.add(notifier, evalContext, cmd.Result)
+}
+
+func (o *notificationOutbox) deliver(evalContext *EvalContext, notifier Notifier, delivery *m.AlertNotificationDelivery) error {
+	notifyErr := notifyWithMetrics(notifier, evalContext)
+	o.complete(delivery, notifyErr)
+
+	return notifyErr
+}
+
+// groupSent records the result of a digest for each of its deliveries.
+func (o *notificationOutbox) groupSent(notifications []*groupedNotification, notifyErr error) {
+	for _, notification := range notifications {
+		if notification.delivery != nil {
+			o.complete(notification.delivery, notifyErr)
+		}
+	}
+}
+
+// complete records a delivery attempt and schedules the next one when it
+// failed.
+func (o *notificationOutbox) complete(delivery *m.AlertNotificationDelivery, notifyErr error) {
+	attempts := delivery.Attempts + 1
+
+	cmd := &m.UpdateAlertNotificationDeliveryCommand{
+		Id:          delivery.Id,
+		Status:      m.DeliveryStatusDelivered,
+		Attempts:    attempts,
+		NextAttempt: time.Now(),
+	}
+
+	if notifyErr != nil {
+		cmd.LastError = notifyErr.Error()
+
+		if attempts >= outboxMaxAttempts {
+			cmd.Status = m.DeliveryStatusFailed
+			o.log.Error("Giving up on notification", "deliveryId", delivery.Id, "alertId", delivery.AlertId, "notifierId", delivery.NotifierId, "attempts", attempts, "error", notifyErr)
+		} else {
+			cmd.Status = m.DeliveryStatusPending
+			cmd.NextAttempt = time.Now().Add(retryBackoff(attempts))
+			o.log.Info("Notification failed, will retry", "deliveryId", delivery.Id, "attempts", attempts, "nextAttempt", cmd.NextAttempt, "error", notifyErr)
+		}
+	}
+
+	if err := bus.Dispatch(cmd); err != nil {
+		o.log.Error("Failed to update notification delivery", "deliveryId", delivery.Id, "error", err)
+	}
+}
+
+// Run retries pending deliveries until the context is cancelled.
+func (o *notificationOutbox) Run(ctx context.Context) error {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			o.retryPending()
+		}
+	}
+}
+
+func (o *notificationOutbox) retryPending() {
+	query := &m.GetPendingAlertNotificationDeliveriesQuery{Before: time.Now()}
+	if err := bus.Dispatch(query); err != nil {
+		o.log.Error("Failed to read notification outbox", "error", err)
+		return
+	}
+
+	for _, delivery := range query.Result {
+		if o.ownsAlert != nil && !o.ownsAlert(delivery.AlertId) {
+			continue
+		}
+
+		o.retry(delivery)
+	}
+}
+
+func (o *notificationOutbox) retry(delivery *m.AlertNotificationDelivery) {
+	notifier, evalContext, err := o.restore(context.Background(), delivery)
+	if err != nil {
+		o.log.Error("Cannot retry notification", "deliveryId", delivery.Id, "error", err)
+		bus.Dispatch(&m.UpdateAlertNotificationDeliveryCommand{
+			Id:          delivery.Id,
+			Status:      m.DeliveryStatusFailed,
+			Attempts:    delivery.Attempts,
+			LastError:   err.Error(),
+			NextAttempt: time.Now(),
+		})
+		return
+	}
+
+	if groupNotifier, ok := notifier.(GroupNotifier); ok && shouldGroupNotification(groupNotifier, evalContext) {
+		o.regroup(evalContext, groupNotifier, delivery)
+		return
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), alertTimeout)
+	defer cancelFn()
+	evalContext.Ctx = ctx
+
+	o.log.Info("Retrying notification", "deliveryId", delivery.Id, "alertId", delivery.AlertId, "notifierId", delivery.NotifierId, "attempt", delivery.Attempts+1)
+	o.deliver(evalContext, notifier, delivery)
+}
+
+// regroup holds the delivery back from retries and adds it to the group of
+// the notifier again, so it is sent with the next digest.
+func (o *notificationOutbox) regroup(evalContext *EvalContext, notifier GroupNotifier, delivery *m.AlertNotificationDelivery) {
+	delivery.NextAttempt = time.Now().Add(groupHoldTime(notifier))
+
+	err := bus.Dispatch(&m.UpdateAlertNotificationDeliveryCommand{
+		Id:          delivery.Id,
+		Status:      m.DeliveryStatusPending,
+		Attempts:    delivery.Attempts,
+		LastError:   delivery.LastError,
+		NextAttempt: delivery.NextAttempt,
+	})
+
+	if err != nil {
+		o.log.Error("Failed to update notification delivery", "deliveryId", delivery.Id, "error", err)
+		return
+	}
+
+	o.log.Info("Queueing notification for next digest", "deliveryId", delivery.Id, "alertId", delivery.AlertId, "notifierId", delivery.NotifierId, "attempt", delivery.Attempts+1)
+	o.grouper.add(notifier, evalContext, delivery)
+}
+
+func (o *notificationOutbox) restore(ctx context.Context, delivery *m.AlertNotificationDelivery) (Notifier, *EvalContext, error) {
+	notificationQuery := &m.GetAlertNotificationsQuery{OrgId: delivery.OrgId, Id: delivery.NotifierId}
+	if err := bus.Dispatch(notificationQuery); err != nil {
+		return nil, nil, err
+	}
+
+	if notificationQuery.Result == nil {
+		return nil, nil, errors.New("Notification channel not found")
+	}
+
+	notifier, err := createNotifier(notificationQuery.Result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	alertQuery := &m.GetAlertByIdQuery{Id: delivery.AlertId}
+	if err := bus.Dispatch(alertQuery); err != nil {
+		return nil, nil, err
+	}
+
+	rule, err := NewRuleFromDBAlert(alertQuery.Result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rule.State = delivery.State
+	evalContext := NewEvalContext(ctx, rule)
+	restoreDeliveryPayload(evalContext, delivery.Payload)
+
+	return notifier, evalContext, nil
+}
+
+func newDeliveryCommand(evalContext *EvalContext, notifier Notifier, nextAttempt time.Time) *m.CreateAlertNotificationDeliveryCommand {
+	return &m.CreateAlertNotificationDeliveryCommand{
+		OrgId:       evalContext.Rule.OrgId,
+		AlertId:     evalContext.Rule.Id,
+		NotifierId:  notifier.GetNotifierId(),
+		State:       evalContext.Rule.State,
+		Payload:     newDeliveryPayload(evalContext),
+		NextAttempt: nextAttempt,
+		DedupKey:    evalContext.NotificationKey,
+	}
+}
+
+// groupHoldTime is the longest a notification waits for its digest.
+func groupHoldTime(notifier GroupNotifier) time.Duration {
+	settings := notifier.GetGroupSettings()
+	return settings.Wait + settings.Interval + alertTimeout + outboxRetryBackoff
+}
+
+// retryBackoff doubles the wait after every failed attempt.
+func retryBackoff(attempts int) time.Duration {
+	backoff := outboxRetryBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+
+	return backoff
+}
+
+func newDeliveryPayload(evalContext *EvalContext) *simplejson.Json {
+	payload := simplejson.New()
+	payload.Set("prevState", evalContext.PrevAlertState)
+	payload.Set("firing", evalContext.Firing)
+	payload.Set("noData", evalContext.NoDataFound)
+	payload.Set("evalMatches", evalContext.EvalMatches)
+	payload.Set("imageUrl", evalContext.ImagePublicUrl)
+	payload.Set("imageOnDiskPath", evalContext.ImageOnDiskPath)
+
+	if evalContext.Error != nil {
+		payload.Set("error", evalContext.Error.Error())
+	}
+
+	return payload
+}
+
+func restoreDeliveryPayload(evalContext *EvalContext, payload *simplejson.Json) {
+	if payload == nil {
+		return
+	}
+
+	evalContext.PrevAlertState = m.AlertStateType(payload.Get("prevState").MustString())
+	evalContext.Firing = payload.Get("firing").MustBool()
+	evalContext.NoDataFound = payload.Get("noData").MustBool()
+	evalContext.ImagePublicUrl = payload.Get("imageUrl").MustString()
+	evalContext.ImageOnDiskPath = payload.Get("imageOnDiskPath").MustString()
+
+	if errorMessage := payload.Get("error").MustString(); errorMessage != "" {
+		evalContext.Error = errors.New(errorMessage)
+	}
+
+	if matches, err := payload.Get("evalMatches").MarshalJSON(); err == nil {
+		json.Unmarshal(matches, &evalContext.EvalMatches)
+	}
+}
@@ -0,0 +1,251 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type httpTestNotifier struct {
+	FakeNotifier
+	url string
+}
+
+func (n *httpTestNotifier) GetNotifierId() int64 { return 10 }
+
+func (n *httpTestNotifier) Notify(evalContext *EvalContext) error {
+	body, _ := json.Marshal(map[string]interface{}{
+		"state":   evalContext.Rule.State,
+		"matches": len(evalContext.EvalMatches),
+	})
+
+	resp, err := http.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Receiver returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+type fakeReceiver struct {
+	mtx      sync.Mutex
+	failures int
+	received []map[string]interface{}
+}
+
+func (r *fakeReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	body := map[string]interface{}{}
+	json.NewDecoder(req.Body).Decode(&body)
+	r.received = append(r.received, body)
+
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(200)
+}
+
+func TestNotificationOutbox(t *testing.T) {
+	Convey("Notification outbox", t, func() {
+		receiver := &fakeReceiver{}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		deliveries := map[int64]*m.AlertNotificationDelivery{}
+
+		bus.AddHandler("test", func(cmd *m.CreateAlertNotificationDeliveryCommand) error {
+			cmd.Result = &m.AlertNotificationDelivery{
+				Id:          int64(len(deliveries) + 1),
+				OrgId:       cmd.OrgId,
+				AlertId:     cmd.AlertId,
+				NotifierId:  cmd.NotifierId,
+				State:       cmd.State,
+				Status:      m.DeliveryStatusPending,
+				Payload:     cmd.Payload,
+				NextAttempt: cmd.NextAttempt,
+			}
+			deliveries[cmd.Result.Id] = cmd.Result
+			return nil
+		})
+
+		bus.AddHandler("test", func(cmd *m.UpdateAlertNotificationDeliveryCommand) error {
+			delivery := deliveries[cmd.Id]
+			delivery.Status = cmd.Status
+			delivery.Attempts = cmd.Attempts
+			delivery.LastError = cmd.LastError
+			delivery.NextAttempt = cmd.NextAttempt
+			return nil
+		})
+
+		bus.AddHandler("test", func(query *m.GetPendingAlertNotificationDeliveriesQuery) error {
+			query.Result = nil
+			for _, delivery := range deliveries {
+				if delivery.Status == m.DeliveryStatusPending && !delivery.NextAttempt.After(query.Before) {
+					query.Result = append(query.Result, delivery)
+				}
+			}
+			return nil
+		})
+
+		RegisterNotifier(&NotifierPlugin{
+			Type: "outbox-test",
+			Factory: func(model *m.AlertNotification) (Notifier, error) {
+				return &httpTestNotifier{url: model.Settings.Get("url").MustString()}, nil
+			},
+		})
+
+		bus.AddHandler("test", func(query *m.GetAlertNotificationsQuery) error {
+			query.Result = &m.AlertNotification{
+				Id:       10,
+				OrgId:    query.OrgId,
+				Type:     "outbox-test",
+				Settings: simplejson.NewFromAny(map[string]interface{}{"url": server.URL}),
+			}
+			return nil
+		})
+
+		RegisterCondition("outbox-test", func(model *simplejson.Json, index int) (Condition, error) {
+			return &conditionStub{}, nil
+		})
+
+		bus.AddHandler("test", func(query *m.GetAlertByIdQuery) error {
+			query.Result = &m.Alert{
+				Id:    query.Id,
+				OrgId: 1,
+				Name:  "cpu",
+				State: m.AlertStateOK,
+				Settings: simplejson.NewFromAny(map[string]interface{}{
+					"conditions": []interface{}{map[string]interface{}{"type": "outbox-test"}},
+				}),
+			}
+			return nil
+		})
+
+		outbox := newNotificationOutbox()
+		notifier := &httpTestNotifier{url: server.URL}
+
+		evalContext := NewEvalContext(context.TODO(), &Rule{Id: 1, OrgId: 1, State: m.AlertStateAlerting})
+		evalContext.PrevAlertState = m.AlertStateOK
+		evalContext.EvalMatches = []*EvalMatch{{Metric: "cpu", Value: null.FloatFrom(99)}}
+
+		Convey("Should record successful delivery", func() {
+			err := outbox.Send(evalContext, notifier)
+			So(err, ShouldBeNil)
+			So(deliveries[1].Status, ShouldEqual, m.DeliveryStatusDelivered)
+			So(deliveries[1].Attempts, ShouldEqual, 1)
+			So(len(receiver.received), ShouldEqual, 1)
+		})
+
+		Convey("Should retry failed delivery with restored context", func() {
+			receiver.failures = 1
+
+			err := outbox.Send(evalContext, notifier)
+			So(err, ShouldNotBeNil)
+			So(deliveries[1].Status, ShouldEqual, m.DeliveryStatusPending)
+			So(deliveries[1].Attempts, ShouldEqual, 1)
+			So(deliveries[1].LastError, ShouldContainSubstring, "500")
+			So(deliveries[1].NextAttempt, ShouldHappenAfter, time.Now())
+
+			deliveries[1].NextAttempt = time.Now().Add(-time.Second)
+			outbox.retryPending()
+
+			So(deliveries[1].Status, ShouldEqual, m.DeliveryStatusDelivered)
+			So(deliveries[1].Attempts, ShouldEqual, 2)
+			So(len(receiver.received), ShouldEqual, 2)
+			So(receiver.received[1]["state"], ShouldEqual, "alerting")
+			So(receiver.received[1]["matches"], ShouldEqual, 1)
+		})
+
+		Convey("Should give up after max attempts", func() {
+			receiver.failures = 100
+
+			outbox.Send(evalContext, notifier)
+			for i := 0; i < outboxMaxAttempts; i++ {
+				deliveries[1].NextAttempt = time.Now().Add(-time.Second)
+				outbox.retryPending()
+			}
+
+			So(deliveries[1].Status, ShouldEqual, m.DeliveryStatusFailed)
+			So(deliveries[1].Attempts, ShouldEqual, outboxMaxAttempts)
+			So(len(receiver.received), ShouldEqual, outboxMaxAttempts)
+		})
+
+		Convey("When grouped notifications are queued", func() {
+			groupNotifier := &FakeGroupNotifier{
+				settings: &GroupSettings{By: GroupByChannel, Wait: time.Millisecond * 10, Interval: time.Hour},
+				sent:     make(chan []*EvalContext, 1),
+			}
+
+			digestDone := make(chan bool, 1)
+			groupSent := outbox.grouper.sent
+			outbox.grouper.sent = func(notifications []*groupedNotification, err error) {
+				groupSent(notifications, err)
+				digestDone <- true
+			}
+
+			Convey("Should hold the delivery back from retries until the digest is sent", func() {
+				groupNotifier.settings.Wait = time.Hour
+				outbox.Queue(evalContext, groupNotifier)
+
+				So(deliveries[1].Status, ShouldEqual, m.DeliveryStatusPending)
+				So(deliveries[1].NextAttempt, ShouldHappenAfter, time.Now().Add(time.Hour*2))
+			})
+
+			Convey("Should mark the delivery delivered with the digest", func() {
+				outbox.Queue(evalContext, groupNotifier)
+
+				So(waitForDigest(groupNotifier, digestDone), ShouldEqual, 1)
+				So(deliveries[1].Status, ShouldEqual, m.DeliveryStatusDelivered)
+				So(deliveries[1].Attempts, ShouldEqual, 1)
+			})
+
+			Convey("Should retry the delivery when the digest fails", func() {
+				groupNotifier.err = fmt.Errorf("digest failed")
+				outbox.Queue(evalContext, groupNotifier)
+
+				So(waitForDigest(groupNotifier, digestDone), ShouldEqual, 1)
+				So(deliveries[1].Status, ShouldEqual, m.DeliveryStatusPending)
+				So(deliveries[1].Attempts, ShouldEqual, 1)
+				So(deliveries[1].LastError, ShouldEqual, "digest failed")
+			})
+		})
+
+		Convey("Backoff should grow exponentially up to max", func() {
+			So(retryBackoff(1), ShouldEqual, outboxRetryBackoff)
+			So(retryBackoff(3), ShouldEqual, outboxRetryBackoff*4)
+			So(retryBackoff(100), ShouldEqual, outboxMaxBackoff)
+		})
+	})
+}
+
+func waitForDigest(notifier *FakeGroupNotifier, done chan bool) int {
+	select {
+	case contexts := <-notifier.sent:
+		<-done
+		return len(contexts)
+	case <-time.After(time.Second):
+		return 0
+	}
+}
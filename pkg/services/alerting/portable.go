@@ -0,0 +1,316 @@
+package alerting
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+const (
+	ImportConflictSkip      = "skip"
+	ImportConflictOverwrite = "overwrite"
+	ImportConflictError     = "error"
+)
+
+// AlertRulesExport describes notification channels and standalone alert
+// rules of an org. Channels and data sources are referenced by name so
+// they can be imported in another Grafana instance.
+type AlertRulesExport struct {
+	Notifications []*ExportedNotification `json:"notifications"`
+	Alerts        []*simplejson.Json      `json:"alerts"`
+}
+
+type ExportedNotification struct {
+	Name      string           `json:"name"`
+	Type      string           `json:"type"`
+	IsDefault bool             `json:"isDefault"`
+	Settings  *simplejson.Json `json:"settings"`
+}
+
+type ExportAlertRulesQuery struct {
+	OrgId int64
+	Tags  map[string]string
+
+	Result *AlertRulesExport
+}
+
+// ImportAlertRulesCommand imports an export. OnConflict decides what
+// happens to channels and rules that already exist with the same name,
+// they are skipped by default.
+type ImportAlertRulesCommand struct {
+	AlertRulesExport
+	OnConflict string `json:"onConflict"`
+
+	OrgId  int64                   `json:"-"`
+	Result *ImportAlertRulesResult `json:"-"`
+}
+
+type ImportAlertRulesResult struct {
+	Notifications ImportSummary `json:"notifications"`
+	Alerts        ImportSummary `json:"alerts"`
+}
+
+type ImportSummary struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Skipped []string `json:"skipped"`
+}
+
+// ImportConflict is returned when an imported channel or rule already
+// exists and conflicts should not be skipped or overwritten.
+type ImportConflict struct {
+	Kind string
+	Name string
+}
+
+func (e ImportConflict) Error() string {
+	return fmt.Sprintf("%s %s already exists", e.Kind, e.Name)
+}
+
+func init() {
+	bus.AddHandler("alerting", handleExportAlertRulesQuery)
+	bus.AddHandler("alerting", handleImportAlertRulesCommand)
+}
+
+func handleExportAlertRulesQuery(query *ExportAlertRulesQuery) error {
+	notificationsQuery := &m.GetAllAlertNotificationsQuery{OrgId: query.OrgId}
+	if err := bus.Dispatch(notificationsQuery); err != nil {
+		return err
+	}
+
+	notificationNames := make(map[int64]string)
+	for _, notification := range notificationsQuery.Result {
+		notificationNames[notification.Id] = notification.Name
+	}
+
+	alerts, err := getStandaloneAlerts(query.OrgId)
+	if err != nil {
+		return err
+	}
+
+	result := &AlertRulesExport{
+		Notifications: make([]*ExportedNotification, 0),
+		Alerts:        make([]*simplejson.Json, 0),
+	}
+
+	referenced := make(map[string]bool)
+	for _, alert := range alerts {
+		if !TagsMatch(query.Tags, alertTags(alert)) {
+			continue
+		}
+
+		alertJson, err := exportAlert(query.OrgId, alert, notificationNames)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range alertJson.Get("notifications").MustArray() {
+			referenced[simplejson.NewFromAny(item).Get("name").MustString()] = true
+		}
+
+		result.Alerts = append(result.Alerts, alertJson)
+	}
+
+	// default channels are used by all rules, so they are always included
+	for _, notification := range notificationsQuery.Result {
+		if !notification.IsDefault && !referenced[notification.Name] {
+			continue
+		}
+
+		result.Notifications = append(result.Notifications, &ExportedNotification{
+			Name:      notification.Name,
+			Type:      notification.Type,
+			IsDefault: notification.IsDefault,
+			Settings:  notification.Settings,
+		})
+	}
+
+	query.Result = result
+	return nil
+}
+
+func exportAlert(orgId int64, alert *m.Alert, notificationNames map[int64]string) (*simplejson.Json, error) {
+	alertJson, err := copyJson(alert.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	alertJson.Del("id")
+	alertJson.Set("name", alert.Name)
+
+	notifications := make([]interface{}, 0)
+	for _, item := range alertJson.Get("notifications").MustArray() {
+		id := simplejson.NewFromAny(item).Get("id").MustInt64()
+		if name, exists := notificationNames[id]; exists {
+			notifications = append(notifications, map[string]interface{}{"name": name})
+		}
+	}
+	alertJson.Set("notifications", notifications)
+
+	for _, condition := range alertJson.Get("conditions").MustArray() {
+		jsonQuery := simplejson.NewFromAny(condition).Get("query")
+		if _, exists := jsonQuery.CheckGet("datasourceId"); !exists {
+			continue
+		}
+
+		dsQuery := &m.GetDataSourceByIdQuery{Id: jsonQuery.Get("datasourceId").MustInt64(), OrgId: orgId}
+		if err := bus.Dispatch(dsQuery); err != nil {
+			return nil, err
+		}
+
+		jsonQuery.Del("datasourceId")
+		jsonQuery.Set("datasource", dsQuery.Result.Name)
+	}
+
+	return alertJson, nil
+}
+
+func handleImportAlertRulesCommand(cmd *ImportAlertRulesCommand) error {
+	if cmd.OnConflict == "" {
+		cmd.OnConflict = ImportConflictSkip
+	}
+
+	if cmd.OnConflict != ImportConflictSkip && cmd.OnConflict != ImportConflictOverwrite && cmd.OnConflict != ImportConflictError {
+		return ValidationError{Reason: "Invalid conflict mode " + cmd.OnConflict}
+	}
+
+	existingNotifications := make(map[string]*m.AlertNotification)
+	notificationsQuery := &m.GetAllAlertNotificationsQuery{OrgId: cmd.OrgId}
+	if err := bus.Dispatch(notificationsQuery); err != nil {
+		return err
+	}
+	for _, notification := range notificationsQuery.Result {
+		existingNotifications[notification.Name] = notification
+	}
+
+	existingAlerts := make(map[string]*m.Alert)
+	alerts, err := getStandaloneAlerts(cmd.OrgId)
+	if err != nil {
+		return err
+	}
+	for _, alert := range alerts {
+		existingAlerts[alert.Name] = alert
+	}
+
+	// check and prepare everything before changing anything
+	saveCmd := &m.SaveImportedAlertRulesCommand{}
+	result := &ImportAlertRulesResult{}
+	pending := make(map[string]bool)
+
+	for _, notification := range cmd.Notifications {
+		if err := ValidateNotificationSettings(notification.Settings); err != nil {
+			return err
+		}
+
+		existing, exists := existingNotifications[notification.Name]
+
+		switch {
+		case !exists:
+			saveCmd.CreateNotifications = append(saveCmd.CreateNotifications, &m.CreateAlertNotificationCommand{
+				OrgId:     cmd.OrgId,
+				Name:      notification.Name,
+				Type:      notification.Type,
+				IsDefault: notification.IsDefault,
+				Settings:  notification.Settings,
+			})
+			pending[notification.Name] = true
+			result.Notifications.Created = append(result.Notifications.Created, notification.Name)
+		case cmd.OnConflict == ImportConflictError:
+			return ImportConflict{Kind: "Notification channel", Name: notification.Name}
+		case cmd.OnConflict == ImportConflictOverwrite:
+			saveCmd.UpdateNotifications = append(saveCmd.UpdateNotifications, &m.UpdateAlertNotificationCommand{
+				Id:        existing.Id,
+				OrgId:     cmd.OrgId,
+				Name:      notification.Name,
+				Type:      notification.Type,
+				IsDefault: notification.IsDefault,
+				Settings:  notification.Settings,
+			})
+			result.Notifications.Updated = append(result.Notifications.Updated, notification.Name)
+		default:
+			result.Notifications.Skipped = append(result.Notifications.Skipped, notification.Name)
+		}
+	}
+
+	for _, alertJson := range cmd.Alerts {
+		name := alertJson.Get("name").MustString()
+		existing, exists := existingAlerts[name]
+
+		if exists && cmd.OnConflict == ImportConflictError {
+			return ImportConflict{Kind: "Alert rule", Name: name}
+		}
+
+		if exists && cmd.OnConflict != ImportConflictOverwrite {
+			result.Alerts.Skipped = append(result.Alerts.Skipped, name)
+			continue
+		}
+
+		alert, err := newStandaloneAlert(cmd.OrgId, alertJson, pending)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			alert.Id = existing.Id
+			saveCmd.UpdateAlerts = append(saveCmd.UpdateAlerts, &m.UpdateAlertCommand{Alert: alert})
+			result.Alerts.Updated = append(result.Alerts.Updated, name)
+		} else {
+			saveCmd.CreateAlerts = append(saveCmd.CreateAlerts, &m.CreateAlertCommand{Alert: alert})
+			result.Alerts.Created = append(result.Alerts.Created, name)
+		}
+	}
+
+	if err := bus.Dispatch(saveCmd); err != nil {
+		return err
+	}
+
+	cmd.Result = result
+	return nil
+}
+
+func getStandaloneAlerts(orgId int64) ([]*m.Alert, error) {
+	query := &m.GetAlertsQuery{OrgId: orgId}
+	if err := bus.Dispatch(query); err != nil {
+		return nil, err
+	}
+
+	alerts := make([]*m.Alert, 0)
+	for _, alert := range query.Result {
+		if alert.IsStandalone() {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return alerts, nil
+}
+
+// resolveNotificationNames sets the id of notification channels that an
+// alert references by name. Pending channels are skipped.
+func resolveNotificationNames(orgId int64, alertJson *simplejson.Json, pending map[string]bool) error {
+	for _, item := range alertJson.Get("notifications").MustArray() {
+		notification := simplejson.NewFromAny(item)
+		if _, err := notification.Get("id").Int64(); err == nil {
+			continue
+		}
+
+		name := notification.Get("name").MustString()
+		if name == "" || pending[name] {
+			continue
+		}
+
+		query := &m.GetAlertNotificationsQuery{OrgId: orgId, Name: name}
+		if err := bus.Dispatch(query); err != nil {
+			return err
+		}
+
+		if query.Result == nil {
+			return ValidationError{Reason: "Could not find notification channel " + name}
+		}
+
+		notification.Set("id", query.Result.Id)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertRulesImportExport(t *testing.T) {
+	Convey("Alert rule import and export", t, func() {
+		RegisterCondition("query", func(model *simplejson.Json, index int) (Condition, error) {
+			return &conditionStub{}, nil
+		})
+
+		settings, _ := simplejson.NewJson([]byte(`{
+			"name": "High CPU",
+			"tags": {"team": "ops"},
+			"notifications": [{"id": 3}],
+			"conditions": [{
+				"type": "query",
+				"query": {"params": ["A", "5m", "now"], "datasourceId": 12, "model": {"target": "cpu"}}
+			}]
+		}`))
+
+		notifications := []*m.AlertNotification{
+			{Id: 3, OrgId: 1, Name: "ops-email", Type: "email", Settings: simplejson.New()},
+			{Id: 4, OrgId: 1, Name: "default-slack", Type: "slack", IsDefault: true, Settings: simplejson.New()},
+			{Id: 5, OrgId: 1, Name: "unused", Type: "email", Settings: simplejson.New()},
+		}
+		alerts := []*m.Alert{
+			{Id: 1, OrgId: 1, Name: "High CPU", Settings: settings},
+			{Id: 2, OrgId: 1, DashboardId: 7, Name: "Dashboard alert", Settings: simplejson.New()},
+		}
+
+		bus.AddHandler("test", func(query *m.GetAllAlertNotificationsQuery) error {
+			query.Result = notifications
+			return nil
+		})
+		bus.AddHandler("test", func(query *m.GetAlertNotificationsQuery) error {
+			query.Result = nil
+			for _, notification := range notifications {
+				if notification.Name == query.Name {
+					query.Result = notification
+				}
+			}
+			return nil
+		})
+		bus.AddHandler("test", func(query *m.GetAlertsQuery) error {
+			query.Result = alerts
+			return nil
+		})
+		bus.AddHandler("test", func(query *m.GetDataSourceByIdQuery) error {
+			query.Result = &m.DataSource{Id: 12, OrgId: 1, Name: "graphite"}
+			return nil
+		})
+		bus.AddHandler("test", func(query *m.GetDataSourceByNameQuery) error {
+			if query.Name != "graphite" {
+				return m.ErrDataSourceNotFound
+			}
+			query.Result = &m.DataSource{Id: 12, OrgId: 1, Name: "graphite"}
+			return nil
+		})
+
+		Convey("Export should reference channels and data sources by name", func() {
+			query := &ExportAlertRulesQuery{OrgId: 1}
+			err := bus.Dispatch(query)
+			So(err, ShouldBeNil)
+
+			So(len(query.Result.Alerts), ShouldEqual, 1)
+			alertJson := query.Result.Alerts[0]
+			So(alertJson.Get("name").MustString(), ShouldEqual, "High CPU")
+			So(alertJson.Get("notifications").GetIndex(0).Get("name").MustString(), ShouldEqual, "ops-email")
+
+			jsonQuery := alertJson.Get("conditions").GetIndex(0).Get("query")
+			So(jsonQuery.Get("datasource").MustString(), ShouldEqual, "graphite")
+			_, hasId := jsonQuery.CheckGet("datasourceId")
+			So(hasId, ShouldBeFalse)
+
+			So(len(query.Result.Notifications), ShouldEqual, 2)
+			So(query.Result.Notifications[0].Name, ShouldEqual, "ops-email")
+			So(query.Result.Notifications[1].Name, ShouldEqual, "default-slack")
+
+			Convey("and not change the stored rule", func() {
+				So(settings.Get("notifications").GetIndex(0).Get("id").MustInt64(), ShouldEqual, 3)
+				So(settings.Get("conditions").GetIndex(0).Get("query").Get("datasourceId").MustInt64(), ShouldEqual, 12)
+			})
+		})
+
+		Convey("Export should filter by tags", func() {
+			query := &ExportAlertRulesQuery{OrgId: 1, Tags: map[string]string{"team": "dev"}}
+			err := bus.Dispatch(query)
+			So(err, ShouldBeNil)
+			So(len(query.Result.Alerts), ShouldEqual, 0)
+			So(len(query.Result.Notifications), ShouldEqual, 1)
+		})
+
+		Convey("Import", func() {
+			var created []*m.Alert
+			var updated []*m.Alert
+			var createdChannels []string
+			var updatedChannels []string
+			saved := false
+
+			bus.AddHandler("test", func(cmd *m.SaveImportedAlertRulesCommand) error {
+				saved = true
+				for _, createCmd := range cmd.CreateAlerts {
+					created = append(created, createCmd.Alert)
+				}
+				for _, updateCmd := range cmd.UpdateAlerts {
+					updated = append(updated, updateCmd.Alert)
+				}
+				for _, createCmd := range cmd.CreateNotifications {
+					createdChannels = append(createdChannels, createCmd.Name)
+				}
+				for _, updateCmd := range cmd.UpdateNotifications {
+					updatedChannels = append(updatedChannels, updateCmd.Name)
+				}
+				return nil
+			})
+
+			export := &ExportAlertRulesQuery{OrgId: 1}
+			So(bus.Dispatch(export), ShouldBeNil)
+
+			newAlert, _ := simplejson.NewJson([]byte(`{
+				"name": "Low disk",
+				"notifications": [{"name": "ops-email"}],
+				"conditions": [{
+					"type": "query",
+					"query": {"params": ["A", "5m", "now"], "datasource": "graphite", "model": {"target": "disk"}}
+				}]
+			}`))
+
+			cmd := &ImportAlertRulesCommand{OrgId: 1}
+			cmd.Notifications = append(export.Result.Notifications, &ExportedNotification{Name: "pager", Type: "email", Settings: simplejson.New()})
+			cmd.Alerts = append(export.Result.Alerts, newAlert)
+
+			Convey("should skip existing rules and channels by default", func() {
+				err := bus.Dispatch(cmd)
+				So(err, ShouldBeNil)
+
+				So(cmd.Result.Notifications.Created, ShouldResemble, []string{"pager"})
+				So(cmd.Result.Notifications.Skipped, ShouldResemble, []string{"ops-email", "default-slack"})
+				So(cmd.Result.Alerts.Created, ShouldResemble, []string{"Low disk"})
+				So(cmd.Result.Alerts.Skipped, ShouldResemble, []string{"High CPU"})
+
+				So(len(created), ShouldEqual, 1)
+				So(created[0].Settings.Get("notifications").GetIndex(0).Get("id").MustInt64(), ShouldEqual, 3)
+				So(created[0].Settings.Get("conditions").GetIndex(0).Get("query").Get("datasourceId").MustInt64(), ShouldEqual, 12)
+			})
+
+			Convey("should overwrite existing rules and channels", func() {
+				cmd.OnConflict = ImportConflictOverwrite
+				err := bus.Dispatch(cmd)
+				So(err, ShouldBeNil)
+
+				So(updatedChannels, ShouldResemble, []string{"ops-email", "default-slack"})
+				So(len(updated), ShouldEqual, 1)
+				So(updated[0].Id, ShouldEqual, 1)
+				So(cmd.Result.Alerts.Updated, ShouldResemble, []string{"High CPU"})
+			})
+
+			Convey("should fail on conflict without changing anything", func() {
+				cmd.OnConflict = ImportConflictError
+				err := bus.Dispatch(cmd)
+				So(err, ShouldHaveSameTypeAs, ImportConflict{})
+				So(saved, ShouldBeFalse)
+			})
+
+			Convey("should not save anything when a rule is invalid", func() {
+				invalidAlert, _ := simplejson.NewJson([]byte(`{
+					"name": "Unknown source",
+					"conditions": [{
+						"type": "query",
+						"query": {"params": ["A", "5m", "now"], "datasource": "missing", "model": {"target": "disk"}}
+					}]
+				}`))
+				cmd.Alerts = append(cmd.Alerts, invalidAlert)
+
+				err := bus.Dispatch(cmd)
+				So(err, ShouldHaveSameTypeAs, ValidationError{})
+				So(saved, ShouldBeFalse)
+			})
+
+			Convey("should leave references to imported channels for the save", func() {
+				pagerAlert, _ := simplejson.NewJson([]byte(`{
+					"name": "Paged",
+					"notifications": [{"name": "pager"}],
+					"conditions": [{
+						"type": "query",
+						"query": {"params": ["A", "5m", "now"], "datasource": "graphite", "model": {"target": "disk"}}
+					}]
+				}`))
+				cmd.Alerts = []*simplejson.Json{pagerAlert}
+
+				err := bus.Dispatch(cmd)
+				So(err, ShouldBeNil)
+				So(createdChannels, ShouldResemble, []string{"pager"})
+				So(len(created), ShouldEqual, 1)
+				So(created[0].Settings.Get("notifications").GetIndex(0).Get("name").MustString(), ShouldEqual, "pager")
+			})
+
+			Convey("should reject unknown conflict modes", func() {
+				cmd.OnConflict = "merge"
+				err := bus.Dispatch(cmd)
+				So(err, ShouldHaveSameTypeAs, ValidationError{})
+			})
+		})
+	})
+}
@@ -8,6 +8,12 @@ import (
 	"github.com/grafana/grafana/pkg/log"
 	"github.com/grafana/grafana/pkg/metrics"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var (
+	heartbeatInterval time.Duration = time.Second * 10
+	heartbeatTimeout  time.Duration = time.Second * 30
 )
 
 type RuleReader interface {
@@ -16,6 +22,7 @@ type RuleReader interface {
 
 type DefaultRuleReader struct {
 	sync.RWMutex
+	clusterMode    bool
 	serverID       string
 	serverPosition int
 	clusterSize    int
@@ -23,16 +30,25 @@ type DefaultRuleReader struct {
 }
 
 func NewRuleReader() *DefaultRuleReader {
-	ruleReader := &DefaultRuleReader{
-		log: log.New("alerting.ruleReader"),
-	}
+	ruleReader := newRuleReader(setting.AlertingClusterMode, setting.AlertingClusterServerId)
+	// join the cluster before the first rules are fetched
+	ruleReader.heartbeat()
 
 	go ruleReader.initReader()
 	return ruleReader
 }
 
+func newRuleReader(clusterMode bool, serverID string) *DefaultRuleReader {
+	return &DefaultRuleReader{
+		clusterMode: clusterMode,
+		serverID:    serverID,
+		clusterSize: 1,
+		log:         log.New("alerting.ruleReader"),
+	}
+}
+
 func (arr *DefaultRuleReader) initReader() {
-	heartbeat := time.NewTicker(time.Second * 10)
+	heartbeat := time.NewTicker(heartbeatInterval)
 
 	for {
 		select {
@@ -52,6 +68,10 @@ func (arr *DefaultRuleReader) Fetch() []*Rule {
 
 	res := make([]*Rule, 0)
 	for _, ruleDef := range cmd.Result {
+		if !arr.ownsAlert(ruleDef.Id) {
+			continue
+		}
+
 		if model, err := NewRuleFromDBAlert(ruleDef); err != nil {
 			arr.log.Error("Could not build alert model for rule", "ruleId", ruleDef.Id, "error", err)
 		} else {
@@ -63,7 +83,39 @@ func (arr *DefaultRuleReader) Fetch() []*Rule {
 	return res
 }
 
+// ownsAlert returns true if this server is responsible for the alert.
+// Alerts are sharded by id between the servers in the cluster.
+func (arr *DefaultRuleReader) ownsAlert(alertId int64) bool {
+	arr.RLock()
+	defer arr.RUnlock()
+
+	return alertId%int64(arr.clusterSize) == int64(arr.serverPosition)
+}
+
 func (arr *DefaultRuleReader) heartbeat() {
-	arr.clusterSize = 1
-	arr.serverPosition = 1
+	if !arr.clusterMode {
+		arr.Lock()
+		arr.clusterSize = 1
+		arr.serverPosition = 0
+		arr.Unlock()
+		return
+	}
+
+	cmd := &m.HeartBeatCommand{ServerId: arr.serverID, Timeout: heartbeatTimeout}
+	if err := bus.Dispatch(cmd); err != nil {
+		// keep the last known position, the other servers cannot see
+		// this one either while the database is unavailable
+		arr.log.Error("Failed to send alerting heartbeat", "error", err)
+		return
+	}
+
+	arr.Lock()
+	defer arr.Unlock()
+
+	if arr.clusterSize != cmd.Result.ClusterSize || arr.serverPosition != cmd.Result.UptimePosition {
+		arr.log.Info("Alerting cluster changed", "serverId", arr.serverID, "position", cmd.Result.UptimePosition, "clusterSize", cmd.Result.ClusterSize)
+	}
+
+	arr.clusterSize = cmd.Result.ClusterSize
+	arr.serverPosition = cmd.Result.UptimePosition
 }
@@ -0,0 +1,88 @@
+package alerting
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRuleReaderClustering(t *testing.T) {
+	Convey("Rule reader in cluster mode", t, func() {
+		RegisterCondition("reader-test", func(model *simplejson.Json, index int) (Condition, error) {
+			return &conditionStub{}, nil
+		})
+
+		bus.AddHandler("test", func(query *m.GetAllAlertsQuery) error {
+			query.Result = nil
+			for id := int64(1); id <= 10; id++ {
+				query.Result = append(query.Result, &m.Alert{
+					Id:    id,
+					OrgId: 1,
+					Settings: simplejson.NewFromAny(map[string]interface{}{
+						"conditions": []interface{}{map[string]interface{}{"type": "reader-test"}},
+					}),
+				})
+			}
+			return nil
+		})
+
+		// in memory version of the heartbeat table shared by the servers
+		heartbeats := map[string]time.Time{}
+		bus.AddHandler("test", func(cmd *m.HeartBeatCommand) error {
+			heartbeats[cmd.ServerId] = time.Now()
+
+			servers := make([]string, 0)
+			for server, updated := range heartbeats {
+				if time.Since(updated) <= cmd.Timeout {
+					servers = append(servers, server)
+				}
+			}
+			sort.Strings(servers)
+
+			cmd.Result = m.AlertingClusterInfo{ServerId: cmd.ServerId, ClusterSize: len(servers)}
+			for position, server := range servers {
+				if server == cmd.ServerId {
+					cmd.Result.UptimePosition = position
+				}
+			}
+			return nil
+		})
+
+		ruleIds := func(rules []*Rule) []int64 {
+			ids := make([]int64, 0)
+			for _, rule := range rules {
+				ids = append(ids, rule.Id)
+			}
+			return ids
+		}
+
+		Convey("Single server should read all rules", func() {
+			reader := newRuleReader(false, "")
+			reader.heartbeat()
+			So(len(reader.Fetch()), ShouldEqual, 10)
+		})
+
+		Convey("Servers should split rules", func() {
+			reader1 := newRuleReader(true, "server-1")
+			reader2 := newRuleReader(true, "server-2")
+			reader1.heartbeat()
+			reader2.heartbeat()
+			reader1.heartbeat()
+
+			So(ruleIds(reader1.Fetch()), ShouldResemble, []int64{2, 4, 6, 8, 10})
+			So(ruleIds(reader2.Fetch()), ShouldResemble, []int64{1, 3, 5, 7, 9})
+
+			Convey("Remaining server should take over rules of missing server", func() {
+				heartbeats["server-2"] = time.Now().Add(-time.Hour)
+				reader1.heartbeat()
+
+				So(len(reader1.Fetch()), ShouldEqual, 10)
+			})
+		})
+	})
+}
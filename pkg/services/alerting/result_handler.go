@@ -1,6 +1,7 @@
 package alerting
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
@@ -44,7 +45,24 @@ func (handler *DefaultResultHandler) Handle(evalContext *EvalContext) error {
 		annotationData.Set("no_data", true)
 	}
 
+	if evalContext.Rule.State == m.AlertStateAlerting && evalContext.Rule.InhibitedBy != nil {
+		inhibitor, err := findInhibitingAlert(evalContext.Rule)
+		if err != nil {
+			handler.log.Error("Failed to check alert inhibition", "alertId", evalContext.Rule.Id, "error", err)
+		} else if inhibitor != nil {
+			handler.log.Info("Alert is inhibited", "alertId", evalContext.Rule.Id, "inhibitedBy", inhibitor.Id)
+			evalContext.Rule.State = m.AlertStateInhibited
+		}
+	}
+
 	countStateResult(evalContext.Rule.State)
+
+	if evalContext.ShouldRefreshNotification() {
+		if err := handler.notifier.Refresh(evalContext); err != nil {
+			handler.log.Error("Failed to refresh notifications", "alertId", evalContext.Rule.Id, "error", err)
+		}
+	}
+
 	if evalContext.ShouldUpdateAlertState() {
 		handler.log.Info("New state change", "alertId", evalContext.Rule.Id, "newState", evalContext.Rule.State, "prev state", evalContext.PrevAlertState)
 
@@ -90,6 +108,25 @@ func (handler *DefaultResultHandler) Handle(evalContext *EvalContext) error {
 			handler.log.Error("Failed to save annotation for new alert state", "error", err)
 		}
 
+		if cmd.Result != nil {
+			evalContext.NotificationKey = fmt.Sprintf("%d-%d-%s", cmd.Result.Id, cmd.Result.StateChanges, cmd.Result.State)
+		}
+
+		if cmd.Result != nil && cmd.Result.Acknowledged && evalContext.Rule.State != m.AlertStateOK {
+			handler.log.Info("Alert is acknowledged, skipping notification", "alertId", evalContext.Rule.Id, "acknowledgedBy", cmd.Result.AckLogin)
+			return nil
+		}
+
+		if evalContext.PrevAlertState == m.AlertStateInhibited && evalContext.Rule.State == m.AlertStateOK {
+			fired, err := firedBeforeInhibition(evalContext.Rule)
+			if err != nil {
+				// rather resolve an alert that never fired than leave one firing
+				handler.log.Error("Failed to read state before inhibition", "alertId", evalContext.Rule.Id, "error", err)
+				fired = true
+			}
+			evalContext.FiredBeforeInhibition = fired
+		}
+
 		if evalContext.ShouldSendNotification() {
 			handler.notifier.Send(evalContext)
 		}
@@ -110,5 +147,7 @@ func countStateResult(state m.AlertStateType) {
 		metrics.M_Alerting_Result_State_Paused.Inc(1)
 	case m.AlertStateNoData:
 		metrics.M_Alerting_Result_State_NoData.Inc(1)
+	case m.AlertStateInhibited:
+		metrics.M_Alerting_Result_State_Inhibited.Inc(1)
 	}
 }
@@ -0,0 +1,248 @@
+package alerting
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// routeStates are the alert states a route can match on. Resolves are
+// always sent, so ok is not one of them.
+var routeStates = map[m.AlertStateType]bool{
+	m.AlertStateAlerting: true,
+	m.AlertStateNoData:   true,
+	m.AlertStatePending:  true,
+	m.AlertStatePaused:   true,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// NotificationRoute decides if a notification channel should be used
+// for an alert rule. All configured criteria have to match.
+type NotificationRoute struct {
+	Tags       map[string]string
+	Severities []string
+	States     []m.AlertStateType
+	Schedule   *RouteSchedule
+}
+
+// RouteSchedule limits a route to days of the week and a time of day
+// range. A range where From is after To spans midnight.
+type RouteSchedule struct {
+	Days     []time.Weekday
+	From     time.Duration
+	To       time.Duration
+	Location *time.Location
+}
+
+// ParseNotificationRoutes reads the routes setting of a notification
+// channel.
+func ParseNotificationRoutes(settings *simplejson.Json) ([]*NotificationRoute, error) {
+	routes := make([]*NotificationRoute, 0)
+	if settings == nil {
+		return routes, nil
+	}
+
+	for _, item := range settings.Get("routes").MustArray() {
+		routeJson := simplejson.NewFromAny(item)
+		route := &NotificationRoute{
+			Tags: make(map[string]string),
+		}
+
+		for key, value := range routeJson.Get("tags").MustMap() {
+			route.Tags[key] = fmt.Sprintf("%v", value)
+		}
+
+		route.Severities = routeJson.Get("severity").MustStringArray()
+
+		for _, state := range routeJson.Get("states").MustStringArray() {
+			if !routeStates[m.AlertStateType(state)] {
+				return nil, ValidationError{Reason: "Invalid route state " + state}
+			}
+			route.States = append(route.States, m.AlertStateType(state))
+		}
+
+		if scheduleJson, exists := routeJson.CheckGet("schedule"); exists {
+			schedule, err := parseRouteSchedule(scheduleJson)
+			if err != nil {
+				return nil, err
+			}
+			route.Schedule = schedule
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+func parseRouteSchedule(scheduleJson *simplejson.Json) (*RouteSchedule, error) {
+	schedule := &RouteSchedule{
+		From:     0,
+		To:       time.Hour * 24,
+		Location: time.Local,
+	}
+
+	for _, day := range scheduleJson.Get("days").MustStringArray() {
+		weekday, exists := weekdays[strings.ToLower(day)]
+		if !exists {
+			return nil, ValidationError{Reason: "Invalid schedule day " + day}
+		}
+		schedule.Days = append(schedule.Days, weekday)
+	}
+
+	if from := scheduleJson.Get("from").MustString(); from != "" {
+		offset, err := parseTimeOfDay(from)
+		if err != nil {
+			return nil, err
+		}
+		schedule.From = offset
+	}
+
+	if to := scheduleJson.Get("to").MustString(); to != "" {
+		offset, err := parseTimeOfDay(to)
+		if err != nil {
+			return nil, err
+		}
+		schedule.To = offset
+	}
+
+	if timezone := scheduleJson.Get("timezone").MustString(); timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, ValidationError{Reason: "Invalid schedule timezone " + timezone, Err: err}
+		}
+		schedule.Location = location
+	}
+
+	return schedule, nil
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, ValidationError{Reason: "Invalid schedule time " + value + ", expected HH:MM"}
+	}
+
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+func (r *NotificationRoute) Matches(rule *Rule, now time.Time) bool {
+	if !TagsMatch(r.Tags, rule.Tags) {
+		return false
+	}
+
+	if len(r.Severities) > 0 && !containsString(r.Severities, rule.Severity) {
+		return false
+	}
+
+	if len(r.States) > 0 {
+		found := false
+		for _, state := range r.States {
+			if state == rule.State {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if r.Schedule != nil && !r.Schedule.Contains(now) {
+		return false
+	}
+
+	return true
+}
+
+func (s *RouteSchedule) Contains(now time.Time) bool {
+	local := now.In(s.Location)
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	day := local.Weekday()
+
+	if s.From > s.To {
+		// the range spans midnight, early hours belong to the previous day
+		if offset < s.To {
+			day = (day + 6) % 7
+		} else if offset < s.From {
+			return false
+		}
+	} else if offset < s.From || offset >= s.To {
+		return false
+	}
+
+	if len(s.Days) == 0 {
+		return true
+	}
+
+	for _, scheduled := range s.Days {
+		if scheduled == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RoutesMatch returns true when there are no routes or any of them matches.
+func RoutesMatch(routes []*NotificationRoute, rule *Rule, now time.Time) bool {
+	if len(routes) == 0 {
+		return true
+	}
+
+	for _, route := range routes {
+		if route.Matches(rule, now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TagsMatch returns true if the tags contain all tags of the filter. A
+// filter tag with an empty or * value only requires the tag to exist.
+func TagsMatch(filter map[string]string, tags map[string]string) bool {
+	for key, value := range filter {
+		tagValue, exists := tags[key]
+		if !exists {
+			return false
+		}
+		if value != "" && value != "*" && value != tagValue {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateNotificationSettings validates the settings shared by all
+// notification channels.
+func ValidateNotificationSettings(settings *simplejson.Json) error {
+	if err := ValidateNotificationTemplates(settings); err != nil {
+		return err
+	}
+
+	_, err := ParseNotificationRoutes(settings)
+	return err
+}
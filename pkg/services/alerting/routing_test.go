@@ -0,0 +1,107 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func parseRoutes(json string) ([]*NotificationRoute, error) {
+	settings, _ := simplejson.NewJson([]byte(json))
+	return ParseNotificationRoutes(settings)
+}
+
+func TestNotificationRouting(t *testing.T) {
+	Convey("Notification routing", t, func() {
+		rule := &Rule{
+			State:    m.AlertStateAlerting,
+			Severity: "warning",
+			Tags:     map[string]string{"team": "backend", "service": "api"},
+		}
+
+		// Wednesday
+		officeHours := time.Date(2017, 6, 14, 10, 30, 0, 0, time.UTC)
+		night := time.Date(2017, 6, 14, 23, 15, 0, 0, time.UTC)
+
+		Convey("No routes should pass everything", func() {
+			routes, err := parseRoutes(`{}`)
+			So(err, ShouldBeNil)
+			So(RoutesMatch(routes, rule, night), ShouldBeTrue)
+		})
+
+		Convey("Should match on tags", func() {
+			routes, _ := parseRoutes(`{"routes": [{"tags": {"team": "backend"}}]}`)
+			So(RoutesMatch(routes, rule, night), ShouldBeTrue)
+
+			routes, _ = parseRoutes(`{"routes": [{"tags": {"team": "frontend"}}]}`)
+			So(RoutesMatch(routes, rule, night), ShouldBeFalse)
+
+			routes, _ = parseRoutes(`{"routes": [{"tags": {"service": "*"}}]}`)
+			So(RoutesMatch(routes, rule, night), ShouldBeTrue)
+
+			routes, _ = parseRoutes(`{"routes": [{"tags": {"region": ""}}]}`)
+			So(RoutesMatch(routes, rule, night), ShouldBeFalse)
+		})
+
+		Convey("Should match on severity and state", func() {
+			routes, _ := parseRoutes(`{"routes": [{"severity": ["critical"]}]}`)
+			So(RoutesMatch(routes, rule, night), ShouldBeFalse)
+
+			routes, _ = parseRoutes(`{"routes": [{"severity": ["warning", "critical"], "states": ["alerting"]}]}`)
+			So(RoutesMatch(routes, rule, night), ShouldBeTrue)
+
+			routes, _ = parseRoutes(`{"routes": [{"states": ["no_data"]}]}`)
+			So(RoutesMatch(routes, rule, night), ShouldBeFalse)
+
+			rule.State = m.AlertStateNoData
+			So(RoutesMatch(routes, rule, night), ShouldBeTrue)
+		})
+
+		Convey("Should match on schedule", func() {
+			routes, err := parseRoutes(`{"routes": [{"schedule": {"days": ["mon", "tue", "wed", "thu", "fri"], "from": "09:00", "to": "17:00", "timezone": "UTC"}}]}`)
+			So(err, ShouldBeNil)
+			So(RoutesMatch(routes, rule, officeHours), ShouldBeTrue)
+			So(RoutesMatch(routes, rule, night), ShouldBeFalse)
+			So(RoutesMatch(routes, rule, officeHours.AddDate(0, 0, 3)), ShouldBeFalse)
+		})
+
+		Convey("Schedule spanning midnight belongs to the day it starts", func() {
+			routes, _ := parseRoutes(`{"routes": [{"schedule": {"days": ["fri"], "from": "22:00", "to": "06:00", "timezone": "UTC"}}]}`)
+
+			friday := time.Date(2017, 6, 16, 23, 0, 0, 0, time.UTC)
+			So(RoutesMatch(routes, rule, friday), ShouldBeTrue)
+			So(RoutesMatch(routes, rule, friday.Add(time.Hour*4)), ShouldBeTrue)
+			So(RoutesMatch(routes, rule, friday.Add(time.Hour*8)), ShouldBeFalse)
+			So(RoutesMatch(routes, rule, friday.AddDate(0, 0, -1)), ShouldBeFalse)
+		})
+
+		Convey("Any matching route should pass", func() {
+			routes, _ := parseRoutes(`{"routes": [
+				{"severity": ["warning"], "schedule": {"from": "09:00", "to": "17:00", "timezone": "UTC"}},
+				{"severity": ["critical"]}
+			]}`)
+			So(RoutesMatch(routes, rule, officeHours), ShouldBeTrue)
+			So(RoutesMatch(routes, rule, night), ShouldBeFalse)
+
+			rule.Severity = "critical"
+			So(RoutesMatch(routes, rule, night), ShouldBeTrue)
+		})
+
+		Convey("Should validate routes", func() {
+			_, err := parseRoutes(`{"routes": [{"schedule": {"days": ["someday"]}}]}`)
+			So(err, ShouldNotBeNil)
+
+			_, err = parseRoutes(`{"routes": [{"schedule": {"from": "25:00"}}]}`)
+			So(err, ShouldNotBeNil)
+
+			_, err = parseRoutes(`{"routes": [{"schedule": {"timezone": "Mars/Olympus"}}]}`)
+			So(err, ShouldNotBeNil)
+
+			_, err = parseRoutes(`{"routes": [{"states": ["ok"]}]}`)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
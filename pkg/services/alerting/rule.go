@@ -18,6 +18,9 @@ type Rule struct {
 	Frequency           int64
 	Name                string
 	Message             string
+	Severity            string
+	Tags                map[string]string
+	InhibitedBy         *Inhibition
 	NoDataState         m.NoDataOption
 	ExecutionErrorState m.ExecutionErrorOption
 	State               m.AlertStateType
@@ -96,11 +99,23 @@ func NewRuleFromDBAlert(ruleDef *m.Alert) (*Rule, error) {
 	model.PanelId = ruleDef.PanelId
 	model.Name = ruleDef.Name
 	model.Message = ruleDef.Message
+	model.Severity = ruleDef.Severity
 	model.Frequency = ruleDef.Frequency
 	model.State = ruleDef.State
 	model.NoDataState = m.NoDataOption(ruleDef.Settings.Get("noDataState").MustString("no_data"))
 	model.ExecutionErrorState = m.ExecutionErrorOption(ruleDef.Settings.Get("executionErrorState").MustString("alerting"))
 
+	model.Tags = make(map[string]string)
+	for key, value := range ruleDef.Settings.Get("tags").MustMap() {
+		model.Tags[key] = fmt.Sprintf("%v", value)
+	}
+
+	inhibition, err := ParseInhibition(ruleDef.Settings)
+	if err != nil {
+		return nil, ValidationError{Reason: "Invalid inhibition settings", Err: err, DashboardId: model.DashboardId, Alertid: model.Id, PanelId: model.PanelId}
+	}
+	model.InhibitedBy = inhibition
+
 	for _, v := range ruleDef.Settings.Get("notifications").MustArray() {
 		jsonModel := simplejson.NewFromAny(v)
 		if id, err := jsonModel.Get("id").Int64(); err != nil {
@@ -2,9 +2,11 @@ package alerting
 
 import (
 	"math"
+	"strconv"
 	"time"
 
 	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
 	"github.com/grafana/grafana/pkg/models"
 )
 
@@ -24,6 +26,7 @@ func (s *SchedulerImpl) Update(rules []*Rule) {
 	s.log.Debug("Scheduling update", "ruleCount", len(rules))
 
 	jobs := make(map[int64]*Job, 0)
+	rules = sortByInhibition(rules)
 
 	for i, rule := range rules {
 		var job *Job
@@ -45,6 +48,12 @@ func (s *SchedulerImpl) Update(rules []*Rule) {
 		jobs[rule.Id] = job
 	}
 
+	for id := range s.jobs {
+		if _, exists := jobs[id]; !exists {
+			metrics.Unregister("alerting.rule_execution_time", "rule_id", strconv.FormatInt(id, 10))
+		}
+	}
+
 	s.jobs = jobs
 }
 
@@ -52,7 +61,15 @@ func (s *SchedulerImpl) Tick(tickTime time.Time, execQueue chan *Job) {
 	now := tickTime.Unix()
 
 	for _, job := range s.jobs {
-		if job.Running || job.Rule.State == models.AlertStatePaused {
+		if job.Rule.State == models.AlertStatePaused {
+			continue
+		}
+
+		if job.Running {
+			if now%job.Rule.Frequency == 0 {
+				s.log.Debug("Scheduler: Skipping job that is still running", "name", job.Rule.Name, "id", job.Rule.Id)
+				metrics.M_Alerting_Jobs_Skipped.Inc(1)
+			}
 			continue
 		}
 
@@ -75,4 +92,5 @@ func (s *SchedulerImpl) Tick(tickTime time.Time, execQueue chan *Job) {
 func (s *SchedulerImpl) enque(job *Job, execQueue chan *Job) {
 	s.log.Debug("Scheduler: Putting job on to exec queue", "name", job.Rule.Name, "id", job.Rule.Id)
 	execQueue <- job
+	metrics.M_Alerting_Execution_Queue.Update(int64(len(execQueue)))
 }
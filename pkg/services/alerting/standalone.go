@@ -0,0 +1,118 @@
+package alerting
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// NewStandaloneAlert creates an alert that is not part of any dashboard
+// from the same alert model used in panels. Query conditions have to
+// reference their data source by id or name and include the query model,
+// since there is no panel to look them up in.
+func NewStandaloneAlert(orgId int64, alertJson *simplejson.Json) (*m.Alert, error) {
+	return newStandaloneAlert(orgId, alertJson, nil)
+}
+
+// newStandaloneAlert leaves references to the pending notification
+// channels unresolved, they are resolved once the channels are saved.
+func newStandaloneAlert(orgId int64, alertJson *simplejson.Json, pending map[string]bool) (*m.Alert, error) {
+	settings, err := copyJson(alertJson)
+	if err != nil {
+		return nil, err
+	}
+
+	name := settings.Get("name").MustString()
+	if name == "" {
+		return nil, ValidationError{Reason: "Alert name is required"}
+	}
+
+	frequency, err := getTimeDurationStringToSeconds(settings.Get("frequency").MustString("60s"))
+	if err != nil {
+		return nil, ValidationError{Reason: "Could not parse frequency"}
+	}
+
+	for index, condition := range settings.Get("conditions").MustArray() {
+		jsonCondition := simplejson.NewFromAny(condition)
+		if jsonCondition.Get("type").MustString() != "query" {
+			continue
+		}
+
+		jsonQuery := jsonCondition.Get("query")
+		if len(jsonQuery.Get("params").MustArray()) < 3 {
+			return nil, ValidationError{Reason: fmt.Sprintf("Condition %d requires query params [refId, from, to]", index)}
+		}
+
+		if _, hasModel := jsonQuery.CheckGet("model"); !hasModel {
+			return nil, ValidationError{Reason: fmt.Sprintf("Condition %d is missing the query model", index)}
+		}
+
+		// exported rules reference the data source by name
+		if dsName := jsonQuery.Get("datasource").MustString(); dsName != "" {
+			dsQuery := &m.GetDataSourceByNameQuery{Name: dsName, OrgId: orgId}
+			if err := bus.Dispatch(dsQuery); err != nil {
+				return nil, ValidationError{Reason: fmt.Sprintf("Condition %d refers to data source %s that cannot be found", index, dsName)}
+			}
+
+			jsonQuery.Del("datasource")
+			jsonQuery.Set("datasourceId", dsQuery.Result.Id)
+			continue
+		}
+
+		dsQuery := &m.GetDataSourceByIdQuery{Id: jsonQuery.Get("datasourceId").MustInt64(), OrgId: orgId}
+		if err := bus.Dispatch(dsQuery); err != nil {
+			return nil, ValidationError{Reason: fmt.Sprintf("Condition %d refers to a data source that cannot be found", index)}
+		}
+	}
+
+	if err := resolveNotificationNames(orgId, settings, pending); err != nil {
+		return nil, err
+	}
+
+	alert := &m.Alert{
+		OrgId:     orgId,
+		Name:      name,
+		Message:   settings.Get("message").MustString(),
+		Severity:  settings.Get("severity").MustString(),
+		Frequency: frequency,
+		Settings:  settings,
+	}
+
+	if err := validateStandaloneRule(alert, pending); err != nil {
+		return nil, err
+	}
+
+	if err := validateMessageTemplate(alert); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// validateStandaloneRule parses the rule. Pending notification channels
+// have no id yet, so the rule is parsed as if they had one.
+func validateStandaloneRule(alert *m.Alert, pending map[string]bool) error {
+	if len(pending) == 0 {
+		_, err := NewRuleFromDBAlert(alert)
+		return err
+	}
+
+	settings, err := copyJson(alert.Settings)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range settings.Get("notifications").MustArray() {
+		notification := simplejson.NewFromAny(item)
+		if pending[notification.Get("name").MustString()] {
+			notification.Set("id", 0)
+		}
+	}
+
+	rule := *alert
+	rule.Settings = settings
+	_, err = NewRuleFromDBAlert(&rule)
+	return err
+}
@@ -0,0 +1,74 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStandaloneAlert(t *testing.T) {
+	Convey("Standalone alerts", t, func() {
+		RegisterCondition("query", func(model *simplejson.Json, index int) (Condition, error) {
+			return &conditionStub{}, nil
+		})
+
+		bus.AddHandler("test", func(query *m.GetDataSourceByIdQuery) error {
+			if query.Id != 12 || query.OrgId != 1 {
+				return m.ErrDataSourceNotFound
+			}
+			query.Result = &m.DataSource{Id: 12, OrgId: 1}
+			return nil
+		})
+
+		parse := func(json string) (*m.Alert, error) {
+			alertJson, _ := simplejson.NewJson([]byte(json))
+			return NewStandaloneAlert(1, alertJson)
+		}
+
+		Convey("Should create alert from json", func() {
+			alert, err := parse(`{
+				"name": "High CPU",
+				"message": "cpu is high",
+				"severity": "critical",
+				"frequency": "30s",
+				"conditions": [{
+					"type": "query",
+					"query": {"params": ["A", "5m", "now"], "datasourceId": 12, "model": {"target": "cpu"}},
+					"reducer": {"type": "avg"},
+					"evaluator": {"type": "gt", "params": [90]}
+				}]
+			}`)
+
+			So(err, ShouldBeNil)
+			So(alert.OrgId, ShouldEqual, 1)
+			So(alert.DashboardId, ShouldEqual, 0)
+			So(alert.IsStandalone(), ShouldBeTrue)
+			So(alert.Name, ShouldEqual, "High CPU")
+			So(alert.Severity, ShouldEqual, "critical")
+			So(alert.Frequency, ShouldEqual, 30)
+		})
+
+		Convey("Should require name", func() {
+			_, err := parse(`{"frequency": "30s", "conditions": [{"type": "query"}]}`)
+			So(err, ShouldHaveSameTypeAs, ValidationError{})
+		})
+
+		Convey("Should require query model", func() {
+			_, err := parse(`{"name": "cpu", "conditions": [{"type": "query", "query": {"params": ["A", "5m", "now"], "datasourceId": 12}}]}`)
+			So(err, ShouldHaveSameTypeAs, ValidationError{})
+		})
+
+		Convey("Should require data source in same org", func() {
+			_, err := parse(`{"name": "cpu", "conditions": [{"type": "query", "query": {"params": ["A", "5m", "now"], "datasourceId": 13, "model": {}}}]}`)
+			So(err, ShouldHaveSameTypeAs, ValidationError{})
+		})
+
+		Convey("Should require conditions", func() {
+			_, err := parse(`{"name": "cpu"}`)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
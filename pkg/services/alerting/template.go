@@ -0,0 +1,194 @@
+package alerting
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// TemplateData is the model available in rule message and notification
+// channel templates.
+type TemplateData struct {
+	Title          string
+	Message        string
+	RuleId         int64
+	RuleName       string
+	RuleUrl        string
+	State          m.AlertStateType
+	PrevState      m.AlertStateType
+	StateText      string
+	EvalMatches    []*EvalMatch
+	Tags           map[string]string
+	Error          string
+	ImageUrl       string
+	OrgId          int64
+	DashboardId    int64
+	DashboardUid   string
+	DashboardSlug  string
+	DashboardTitle string
+	PanelId        int64
+	IsTestRun      bool
+}
+
+var templateFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"title":   strings.Title,
+	"replace": strings.Replace,
+	"default": func(def, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return def
+		}
+		return value
+	},
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("notification").Option("missingkey=zero").Funcs(templateFuncs).Parse(text)
+}
+
+// ValidateTemplate parses the template and executes it against
+// sample data so that references to unknown fields are caught on save.
+func ValidateTemplate(text string) error {
+	if text == "" {
+		return nil
+	}
+
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	return tmpl.Execute(&buf, createTemplateSampleData())
+}
+
+// validateMessageTemplate validates the message template of a rule that is
+// saved. Rules are not validated when loaded, so rules saved before
+// templates were supported keep working and send their message as is.
+func validateMessageTemplate(alert *m.Alert) error {
+	if err := ValidateTemplate(alert.Message); err != nil {
+		return ValidationError{Reason: "Invalid message template", Err: err, DashboardId: alert.DashboardId, Alertid: alert.Id, PanelId: alert.PanelId}
+	}
+
+	return nil
+}
+
+// ValidateNotificationTemplates validates the title and body templates
+// in the settings of a notification channel.
+func ValidateNotificationTemplates(settings *simplejson.Json) error {
+	if settings == nil {
+		return nil
+	}
+
+	for _, key := range []string{"titleTemplate", "bodyTemplate"} {
+		if err := ValidateTemplate(settings.Get(key).MustString()); err != nil {
+			return ValidationError{Reason: fmt.Sprintf("Invalid %s", key), Err: err}
+		}
+	}
+
+	return nil
+}
+
+func renderTemplate(text string, data *TemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func createTemplateSampleData() *TemplateData {
+	return &TemplateData{
+		Title:          "[Alerting] Sample rule",
+		Message:        "Sample message",
+		RuleId:         1,
+		RuleName:       "Sample rule",
+		State:          m.AlertStateAlerting,
+		PrevState:      m.AlertStateOK,
+		StateText:      "Alerting",
+		EvalMatches:    evalMatchesBasedOnState(),
+		Tags:           map[string]string{},
+		DashboardId:    1,
+		DashboardUid:   "sample",
+		DashboardSlug:  "sample-dashboard",
+		DashboardTitle: "Sample dashboard",
+	}
+}
+
+// GetTemplateData returns the template model for this evaluation.
+// The rule message is rendered before being added to the model.
+func (c *EvalContext) GetTemplateData() *TemplateData {
+	data := &TemplateData{
+		Title:       c.GetNotificationTitle(),
+		RuleId:      c.Rule.Id,
+		RuleName:    c.Rule.Name,
+		State:       c.Rule.State,
+		PrevState:   c.PrevAlertState,
+		StateText:   c.GetStateModel().Text,
+		EvalMatches: c.EvalMatches,
+		Tags:        make(map[string]string),
+		ImageUrl:    c.ImagePublicUrl,
+		OrgId:       c.Rule.OrgId,
+		DashboardId: c.Rule.DashboardId,
+		PanelId:     c.Rule.PanelId,
+		IsTestRun:   c.IsTestRun,
+	}
+
+	for _, match := range c.EvalMatches {
+		for key, value := range match.Tags {
+			data.Tags[key] = value
+		}
+	}
+
+	if c.Error != nil {
+		data.Error = c.Error.Error()
+	}
+
+	if ruleUrl, err := c.GetRuleUrl(); err == nil {
+		data.RuleUrl = ruleUrl
+	}
+
+	if c.Rule.DashboardId != 0 {
+		if ref, err := c.getDashboardRef(); err == nil {
+			data.DashboardUid = ref.Uid
+			data.DashboardSlug = ref.Slug
+			data.DashboardTitle = ref.Title
+		}
+	}
+
+	if message, err := renderTemplate(c.Rule.Message, data); err != nil {
+		c.log.Error("Failed to render alert message template", "ruleId", c.Rule.Id, "error", err)
+		data.Message = c.Rule.Message
+	} else {
+		data.Message = message
+	}
+
+	return data
+}
+
+// RenderTemplate renders text using the template model for this evaluation.
+func (c *EvalContext) RenderTemplate(text string) (string, error) {
+	return renderTemplate(text, c.GetTemplateData())
+}
+
+// GetNotificationMessage returns the rule message with any template
+// expressions rendered.
+func (c *EvalContext) GetNotificationMessage() string {
+	return c.GetTemplateData().Message
+}
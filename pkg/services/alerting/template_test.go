@@ -0,0 +1,120 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNotificationTemplates(t *testing.T) {
+	Convey("Notification templates", t, func() {
+		ctx := NewEvalContext(context.TODO(), &Rule{
+			Id:      3,
+			Name:    "cpu high",
+			Message: "{{ .RuleName }} on {{ .Tags.host }}",
+			State:   m.AlertStateAlerting,
+		})
+		ctx.IsTestRun = true
+		ctx.PrevAlertState = m.AlertStateOK
+		ctx.EvalMatches = []*EvalMatch{
+			{Metric: "cpu", Value: null.FloatFrom(95), Tags: map[string]string{"host": "web-01"}},
+		}
+
+		Convey("Should render rule message with series tags", func() {
+			So(ctx.GetNotificationMessage(), ShouldEqual, "cpu high on web-01")
+		})
+
+		Convey("Should render state and eval matches", func() {
+			text, err := ctx.RenderTemplate("{{ .PrevState }}->{{ .State }}{{ range .EvalMatches }} {{ .Metric }}={{ .Value }}{{ end }}")
+			So(err, ShouldBeNil)
+			So(text, ShouldEqual, "ok->alerting cpu=95.000")
+		})
+
+		Convey("Should leave plain messages untouched", func() {
+			ctx.Rule.Message = "plain { message }"
+			So(ctx.GetNotificationMessage(), ShouldEqual, "plain { message }")
+		})
+
+		Convey("Should fall back to raw message on render errors", func() {
+			ctx.Rule.Message = "{{ index .EvalMatches 5 }}"
+			So(ctx.GetNotificationMessage(), ShouldEqual, "{{ index .EvalMatches 5 }}")
+		})
+
+		Convey("Validate", func() {
+			So(ValidateTemplate(""), ShouldBeNil)
+			So(ValidateTemplate("{{ .RuleName | upper }}"), ShouldBeNil)
+			So(ValidateTemplate("{{ .RuleName "), ShouldNotBeNil)
+			So(ValidateTemplate("{{ .UnknownField }}"), ShouldNotBeNil)
+		})
+
+		Convey("Validate rule message on save", func() {
+			So(validateMessageTemplate(&m.Alert{Message: "{{ .RuleName }}"}), ShouldBeNil)
+			So(validateMessageTemplate(&m.Alert{Message: "literal {{ braces"}), ShouldNotBeNil)
+		})
+
+		Convey("Should load rules with invalid message templates", func() {
+			RegisterCondition("test", func(model *simplejson.Json, index int) (Condition, error) {
+				return &FakeCondition{}, nil
+			})
+
+			settings, _ := simplejson.NewJson([]byte(`{"frequency": "60s", "conditions": [{"type": "test"}]}`))
+			rule, err := NewRuleFromDBAlert(&m.Alert{Id: 1, Name: "old", Message: "literal {{ braces", Settings: settings})
+			So(err, ShouldBeNil)
+
+			ctx.Rule.Message = rule.Message
+			So(ctx.GetNotificationMessage(), ShouldEqual, "literal {{ braces")
+		})
+
+		Convey("Should look up the dashboard once per evaluation", func() {
+			lookups := 0
+			bus.AddHandler("test", func(query *m.GetDashboardRefByIdQuery) error {
+				lookups++
+				return m.ErrDashboardNotFound
+			})
+
+			ctx.IsTestRun = false
+			ctx.Rule.DashboardId = 1
+			ctx.GetTemplateData()
+			ctx.GetTemplateData()
+			So(lookups, ShouldEqual, 1)
+		})
+
+		Convey("Should add the dashboard of the rule", func() {
+			bus.AddHandler("test", func(query *m.GetDashboardRefByIdQuery) error {
+				query.Result = &m.DashboardRef{Uid: "abc", Slug: "servers", Title: "Servers"}
+				return nil
+			})
+
+			ctx.Rule.DashboardId = 1
+			text, err := ctx.RenderTemplate("{{ .DashboardTitle }} {{ .DashboardUid }} {{ .DashboardSlug }}")
+			So(err, ShouldBeNil)
+			So(text, ShouldEqual, "Servers abc servers")
+		})
+
+		Convey("Validate notification settings", func() {
+			settings := simplejson.New()
+			settings.Set("titleTemplate", "{{ .Title }}")
+			So(ValidateNotificationTemplates(settings), ShouldBeNil)
+
+			settings.Set("bodyTemplate", "{{ .Nope }}")
+			So(ValidateNotificationTemplates(settings), ShouldNotBeNil)
+		})
+
+		Convey("Preview test notification", func() {
+			settings := simplejson.New()
+			settings.Set("titleTemplate", "{{ .StateText }}: {{ .RuleName }}")
+			settings.Set("bodyTemplate", "{{ range .EvalMatches }}{{ .Tags.host }} {{ end }}on {{ .DashboardTitle }}")
+			cmd := &NotificationTestCommand{Settings: settings, Preview: true}
+
+			err := renderNotificationPreview(cmd, createTestEvalContext(cmd))
+			So(err, ShouldBeNil)
+			So(cmd.Result.Title, ShouldEqual, "Alerting: Test notification")
+			So(cmd.Result.Body, ShouldEqual, "server-01 server-02 on Test notification")
+		})
+	})
+}
@@ -15,6 +15,14 @@ type NotificationTestCommand struct {
 	Name     string
 	Type     string
 	Settings *simplejson.Json
+	Preview  bool
+
+	Result *NotificationPreview
+}
+
+type NotificationPreview struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
 }
 
 func init() {
@@ -31,6 +39,10 @@ func handleNotificationTestCommand(cmd *NotificationTestCommand) error {
 		Settings: cmd.Settings,
 	}
 
+	if err := ValidateNotificationSettings(cmd.Settings); err != nil {
+		return err
+	}
+
 	notifiers, err := notifier.createNotifierFor(model)
 
 	if err != nil {
@@ -38,7 +50,38 @@ func handleNotificationTestCommand(cmd *NotificationTestCommand) error {
 		return err
 	}
 
-	return notifier.sendNotifications(createTestEvalContext(cmd), []Notifier{notifiers})
+	evalContext := createTestEvalContext(cmd)
+	if cmd.Preview {
+		return renderNotificationPreview(cmd, evalContext)
+	}
+
+	return notifier.sendNotifications(evalContext, []Notifier{notifiers})
+}
+
+func renderNotificationPreview(cmd *NotificationTestCommand, evalContext *EvalContext) error {
+	data := evalContext.GetTemplateData()
+	cmd.Result = &NotificationPreview{
+		Title: data.Title,
+		Body:  data.Message,
+	}
+
+	if titleTemplate := cmd.Settings.Get("titleTemplate").MustString(); titleTemplate != "" {
+		title, err := renderTemplate(titleTemplate, data)
+		if err != nil {
+			return ValidationError{Reason: "Invalid titleTemplate", Err: err}
+		}
+		cmd.Result.Title = title
+	}
+
+	if bodyTemplate := cmd.Settings.Get("bodyTemplate").MustString(); bodyTemplate != "" {
+		body, err := renderTemplate(bodyTemplate, data)
+		if err != nil {
+			return ValidationError{Reason: "Invalid bodyTemplate", Err: err}
+		}
+		cmd.Result.Body = body
+	}
+
+	return nil
 }
 
 func createTestEvalContext(cmd *NotificationTestCommand) *EvalContext {
@@ -54,6 +97,10 @@ func createTestEvalContext(cmd *NotificationTestCommand) *EvalContext {
 	if cmd.Settings.Get("uploadImage").MustBool(true) {
 		ctx.ImagePublicUrl = "http://grafana.org/assets/img/blog/mixed_styles.png"
 	}
+	// the test rule has no dashboard, use a sample one so templates render
+	// the same way as for real notifications
+	ctx.dashboardRef = &m.DashboardRef{Uid: "test", Slug: "test-notification", Title: "Test notification"}
+	ctx.dashboardRefSet = true
 	ctx.IsTestRun = true
 	ctx.Firing = true
 	ctx.Error = nil
@@ -67,11 +114,13 @@ func evalMatchesBasedOnState() []*EvalMatch {
 	matches = append(matches, &EvalMatch{
 		Metric: "High value",
 		Value:  null.FloatFrom(100),
+		Tags:   map[string]string{"host": "server-01"},
 	})
 
 	matches = append(matches, &EvalMatch{
 		Metric: "Higher Value",
 		Value:  null.FloatFrom(200),
+		Tags:   map[string]string{"host": "server-02"},
 	})
 
 	return matches
@@ -49,8 +49,9 @@ func SetRepository(rep Repository) {
 type ItemType string
 
 const (
-	AlertType ItemType = "alert"
-	EventType ItemType = "event"
+	AlertType    ItemType = "alert"
+	AlertAckType ItemType = "alert_ack"
+	EventType    ItemType = "event"
 )
 
 type Item struct {
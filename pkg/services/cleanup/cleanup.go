@@ -15,6 +15,10 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 )
 
+// notificationDeliveryRetention is how long sent and failed alert
+// notification deliveries are kept for the delivery log.
+var notificationDeliveryRetention = time.Hour * 24 * 7
+
 type CleanUpService struct {
 	log log.Logger
 }
@@ -45,6 +49,7 @@ func (service *CleanUpService) start(ctx context.Context) error {
 		case <-ticker.C:
 			service.cleanUpTmpFiles()
 			service.deleteExpiredSnapshots()
+			service.deleteOldNotificationDeliveries()
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -83,3 +88,13 @@ func (service *CleanUpService) cleanUpTmpFiles() {
 func (service *CleanUpService) deleteExpiredSnapshots() {
 	bus.Dispatch(&m.DeleteExpiredSnapshotsCommand{})
 }
+
+func (service *CleanUpService) deleteOldNotificationDeliveries() {
+	cmd := &m.DeleteOldAlertNotificationDeliveriesCommand{OlderThan: time.Now().Add(-notificationDeliveryRetention)}
+	if err := bus.Dispatch(cmd); err != nil {
+		service.log.Error("Failed to delete old alert notification deliveries", "error", err)
+		return
+	}
+
+	service.log.Debug("Deleted old alert notification deliveries", "deleted", cmd.DeletedRows)
+}
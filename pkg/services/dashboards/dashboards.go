@@ -0,0 +1,74 @@
+package dashboards
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+// AlertsError is returned when the alerts of a dashboard are invalid or
+// could not be saved.
+type AlertsError struct {
+	Reason string
+	Err    error
+}
+
+func (e AlertsError) Error() string {
+	return e.Reason + ": " + e.Err.Error()
+}
+
+// SaveDashboard validates the alerts of the dashboard, saves it and then
+// saves its alerts.
+func SaveDashboard(cmd *m.SaveDashboardCommand) error {
+	if err := validateAlerts(cmd, cmd.GetDashboardModel()); err != nil {
+		return err
+	}
+
+	if err := bus.Dispatch(cmd); err != nil {
+		return err
+	}
+
+	return updateAlerts(cmd, cmd.Result)
+}
+
+// SaveProvisionedDashboard saves a dashboard read by a provisioning
+// provider the same way as SaveDashboard.
+func SaveProvisionedDashboard(cmd *m.SaveProvisionedDashboardCommand) error {
+	if err := validateAlerts(cmd.DashboardCmd, cmd.DashboardCmd.GetDashboardModel()); err != nil {
+		return err
+	}
+
+	if err := bus.Dispatch(cmd); err != nil {
+		return err
+	}
+
+	return updateAlerts(cmd.DashboardCmd, cmd.Result)
+}
+
+func validateAlerts(cmd *m.SaveDashboardCommand, dash *m.Dashboard) error {
+	validateCmd := alerting.ValidateDashboardAlertsCommand{
+		OrgId:     cmd.OrgId,
+		UserId:    cmd.UserId,
+		Dashboard: dash,
+	}
+
+	if err := bus.Dispatch(&validateCmd); err != nil {
+		return AlertsError{Reason: "Invalid alert data. Cannot save dashboard", Err: err}
+	}
+
+	return nil
+}
+
+func updateAlerts(cmd *m.SaveDashboardCommand, dash *m.Dashboard) error {
+	alertCmd := alerting.UpdateDashboardAlertsCommand{
+		OrgId:     cmd.OrgId,
+		UserId:    cmd.UserId,
+		Dashboard: dash,
+	}
+
+	if err := bus.Dispatch(&alertCmd); err != nil {
+		return AlertsError{Reason: "Failed to save alerts", Err: err}
+	}
+
+	return nil
+}
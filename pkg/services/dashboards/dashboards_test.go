@@ -0,0 +1,55 @@
+package dashboards
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSaveDashboard(t *testing.T) {
+	Convey("When saving a dashboard", t, func() {
+		var validateErr error
+		var saved bool
+		var alertsUpdated *m.Dashboard
+
+		bus.AddHandler("test", func(cmd *alerting.ValidateDashboardAlertsCommand) error {
+			return validateErr
+		})
+		bus.AddHandler("test", func(cmd *m.SaveDashboardCommand) error {
+			saved = true
+			cmd.Result = cmd.GetDashboardModel()
+			cmd.Result.Id = 5
+			return nil
+		})
+		bus.AddHandler("test", func(cmd *alerting.UpdateDashboardAlertsCommand) error {
+			alertsUpdated = cmd.Dashboard
+			return nil
+		})
+
+		cmd := &m.SaveDashboardCommand{
+			OrgId:     1,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "Disk"}),
+		}
+
+		Convey("Should save the alerts of the saved dashboard", func() {
+			err := SaveDashboard(cmd)
+			So(err, ShouldBeNil)
+			So(saved, ShouldBeTrue)
+			So(alertsUpdated.Id, ShouldEqual, 5)
+		})
+
+		Convey("Should not save the dashboard when its alerts are invalid", func() {
+			validateErr = errors.New("invalid")
+
+			err := SaveDashboard(cmd)
+			So(err, ShouldHaveSameTypeAs, AlertsError{})
+			So(saved, ShouldBeFalse)
+			So(alertsUpdated, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,205 @@
+package guardian
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// DashboardGuardian checks the permissions of a user on a dashboard or
+// folder. Org admins can do everything, other users get the permissions of
+// the ACL entries that match them, their teams or their org role, or of their
+// org role if neither the dashboard nor its folder has an ACL. A dashboard
+// with an empty ACL is only accessible to org admins.
+type DashboardGuardian struct {
+	user   *m.SignedInUser
+	dashId int64
+	orgId  int64
+	acl    []*m.DashboardAclInfoDTO
+	hasAcl bool
+	teams  map[int64]bool
+}
+
+func New(dashId int64, orgId int64, user *m.SignedInUser) *DashboardGuardian {
+	return &DashboardGuardian{
+		user:   user,
+		dashId: dashId,
+		orgId:  orgId,
+	}
+}
+
+func (g *DashboardGuardian) CanSave() (bool, error) {
+	return g.HasPermission(m.PERMISSION_EDIT)
+}
+
+// CanEdit returns true if the user can change the dashboard in the browser,
+// read only editors can do that without being able to save.
+func (g *DashboardGuardian) CanEdit() (bool, error) {
+	if g.user.OrgRole == m.ROLE_READ_ONLY_EDITOR {
+		return g.CanView()
+	}
+
+	return g.HasPermission(m.PERMISSION_EDIT)
+}
+
+func (g *DashboardGuardian) CanView() (bool, error) {
+	return g.HasPermission(m.PERMISSION_VIEW)
+}
+
+func (g *DashboardGuardian) CanAdmin() (bool, error) {
+	return g.HasPermission(m.PERMISSION_ADMIN)
+}
+
+func (g *DashboardGuardian) HasPermission(permission m.PermissionType) (bool, error) {
+	if g.user.OrgRole == m.ROLE_ADMIN {
+		return true, nil
+	}
+
+	acl, err := g.GetAcl()
+	if err != nil {
+		return false, err
+	}
+
+	if g.teams == nil && hasTeamEntries(acl) {
+		if g.teams, err = getUserTeamIds(g.user); err != nil {
+			return false, err
+		}
+	}
+
+	return hasPermission(g.user, g.teams, g.hasAcl, acl, permission), nil
+}
+
+// GetAcl returns the ACL entries that apply to the dashboard.
+func (g *DashboardGuardian) GetAcl() ([]*m.DashboardAclInfoDTO, error) {
+	if g.acl != nil {
+		return g.acl, nil
+	}
+
+	query := m.GetDashboardAclInfoListQuery{DashboardId: g.dashId, OrgId: g.orgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return nil, err
+	}
+
+	g.acl = query.Result
+	g.hasAcl = query.ResultHasAcl
+	return g.acl, nil
+}
+
+// FilterDashboards returns the ids of the dashboards on which the user has
+// the permission. Dashboards are given as a map of dashboard id to folder id.
+func FilterDashboards(user *m.SignedInUser, permission m.PermissionType, dashboards map[int64]int64) (map[int64]bool, error) {
+	allowed := make(map[int64]bool)
+	if user.OrgRole == m.ROLE_ADMIN {
+		for id := range dashboards {
+			allowed[id] = true
+		}
+		return allowed, nil
+	}
+
+	query := m.GetOrgDashboardAclsQuery{OrgId: user.OrgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return nil, err
+	}
+
+	aclByDashboard := make(map[int64][]*m.DashboardAclInfoDTO)
+	for _, item := range query.Result {
+		aclByDashboard[item.DashboardId] = append(aclByDashboard[item.DashboardId], item)
+	}
+
+	var teams map[int64]bool
+	if hasTeamEntries(query.Result) {
+		var err error
+		if teams, err = getUserTeamIds(user); err != nil {
+			return nil, err
+		}
+	}
+
+	for id, folderId := range dashboards {
+		aclId := id
+		if !query.ResultHasAcl[id] && folderId > 0 {
+			aclId = folderId
+		}
+
+		if hasPermission(user, teams, query.ResultHasAcl[aclId], aclByDashboard[aclId], permission) {
+			allowed[id] = true
+		}
+	}
+
+	return allowed, nil
+}
+
+func hasPermission(user *m.SignedInUser, teams map[int64]bool, hasAcl bool, acl []*m.DashboardAclInfoDTO, permission m.PermissionType) bool {
+	if !hasAcl {
+		return defaultPermission(user.OrgRole) >= permission
+	}
+
+	for _, item := range acl {
+		if item.Permission < permission {
+			continue
+		}
+
+		if item.UserId > 0 && item.UserId == user.UserId {
+			return true
+		}
+
+		if item.TeamId > 0 && teams[item.TeamId] {
+			return true
+		}
+
+		if item.Role != nil && roleRank(user.OrgRole) >= roleRank(*item.Role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasTeamEntries(acl []*m.DashboardAclInfoDTO) bool {
+	for _, item := range acl {
+		if item.TeamId > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getUserTeamIds returns the ids of the teams the user is a member of.
+func getUserTeamIds(user *m.SignedInUser) (map[int64]bool, error) {
+	query := m.GetTeamsByUserQuery{OrgId: user.OrgId, UserId: user.UserId}
+	if err := bus.Dispatch(&query); err != nil {
+		return nil, err
+	}
+
+	teams := make(map[int64]bool)
+	for _, team := range query.Result {
+		teams[team.Id] = true
+	}
+	return teams, nil
+}
+
+// roleRank orders org roles so that an entry for a role also matches the
+// roles above it. Read only editors are not included in the editor role.
+func roleRank(role m.RoleType) int {
+	switch role {
+	case m.ROLE_ADMIN:
+		return 3
+	case m.ROLE_EDITOR:
+		return 2
+	case m.ROLE_READ_ONLY_EDITOR:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// defaultPermission is the permission of an org role on dashboards without
+// an ACL.
+func defaultPermission(role m.RoleType) m.PermissionType {
+	switch role {
+	case m.ROLE_ADMIN:
+		return m.PERMISSION_ADMIN
+	case m.ROLE_EDITOR:
+		return m.PERMISSION_EDIT
+	default:
+		return m.PERMISSION_VIEW
+	}
+}
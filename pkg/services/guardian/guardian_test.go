@@ -0,0 +1,143 @@
+package guardian
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGuardian(t *testing.T) {
+	Convey("Given a dashboard guardian", t, func() {
+		var acl []*m.DashboardAclInfoDTO
+		hasAcl := true
+		bus.AddHandler("test", func(query *m.GetDashboardAclInfoListQuery) error {
+			query.Result = acl
+			query.ResultHasAcl = hasAcl
+			return nil
+		})
+
+		viewer := &m.SignedInUser{UserId: 1, OrgId: 1, OrgRole: m.ROLE_VIEWER}
+		editor := &m.SignedInUser{UserId: 2, OrgId: 1, OrgRole: m.ROLE_EDITOR}
+		readOnlyEditor := &m.SignedInUser{UserId: 3, OrgId: 1, OrgRole: m.ROLE_READ_ONLY_EDITOR}
+		admin := &m.SignedInUser{UserId: 4, OrgId: 1, OrgRole: m.ROLE_ADMIN}
+
+		Convey("When dashboard has no acl", func() {
+			acl = []*m.DashboardAclInfoDTO{}
+			hasAcl = false
+
+			Convey("should use org role permissions", func() {
+				canSave, _ := New(1, 1, viewer).CanSave()
+				So(canSave, ShouldBeFalse)
+
+				canView, _ := New(1, 1, viewer).CanView()
+				So(canView, ShouldBeTrue)
+
+				canSave, _ = New(1, 1, editor).CanSave()
+				So(canSave, ShouldBeTrue)
+
+				canAdmin, _ := New(1, 1, editor).CanAdmin()
+				So(canAdmin, ShouldBeFalse)
+
+				canEdit, _ := New(1, 1, readOnlyEditor).CanEdit()
+				So(canEdit, ShouldBeTrue)
+
+				canSave, _ = New(1, 1, readOnlyEditor).CanSave()
+				So(canSave, ShouldBeFalse)
+			})
+		})
+
+		Convey("When all acl entries of the dashboard are removed", func() {
+			acl = []*m.DashboardAclInfoDTO{}
+
+			Convey("should only let org admins access it", func() {
+				canView, _ := New(1, 1, viewer).CanView()
+				So(canView, ShouldBeFalse)
+
+				canView, _ = New(1, 1, editor).CanView()
+				So(canView, ShouldBeFalse)
+
+				canAdmin, _ := New(1, 1, admin).CanAdmin()
+				So(canAdmin, ShouldBeTrue)
+			})
+		})
+
+		Convey("When dashboard gives editors view and a viewer edit permission", func() {
+			editorRole := m.ROLE_EDITOR
+			acl = []*m.DashboardAclInfoDTO{
+				{DashboardId: 1, Role: &editorRole, Permission: m.PERMISSION_VIEW},
+				{DashboardId: 1, UserId: viewer.UserId, Permission: m.PERMISSION_EDIT},
+			}
+
+			Convey("should use the acl entries", func() {
+				canSave, _ := New(1, 1, viewer).CanSave()
+				So(canSave, ShouldBeTrue)
+
+				canSave, _ = New(1, 1, editor).CanSave()
+				So(canSave, ShouldBeFalse)
+
+				canView, _ := New(1, 1, editor).CanView()
+				So(canView, ShouldBeTrue)
+
+				canView, _ = New(1, 1, readOnlyEditor).CanView()
+				So(canView, ShouldBeFalse)
+			})
+
+			Convey("should let org admins do everything", func() {
+				canAdmin, _ := New(1, 1, admin).CanAdmin()
+				So(canAdmin, ShouldBeTrue)
+			})
+		})
+
+		Convey("When dashboard gives a team admin permission", func() {
+			acl = []*m.DashboardAclInfoDTO{
+				{DashboardId: 1, TeamId: 10, Permission: m.PERMISSION_ADMIN},
+			}
+
+			bus.AddHandler("test", func(query *m.GetTeamsByUserQuery) error {
+				query.Result = []*m.Team{}
+				if query.UserId == viewer.UserId {
+					query.Result = append(query.Result, &m.Team{Id: 10, OrgId: 1, Name: "finance"})
+				}
+				return nil
+			})
+
+			Convey("should give team members the team permission", func() {
+				canAdmin, _ := New(1, 1, viewer).CanAdmin()
+				So(canAdmin, ShouldBeTrue)
+
+				canView, _ := New(1, 1, editor).CanView()
+				So(canView, ShouldBeFalse)
+			})
+		})
+
+		Convey("When filtering dashboards", func() {
+			adminRole := m.ROLE_ADMIN
+			bus.AddHandler("test", func(query *m.GetOrgDashboardAclsQuery) error {
+				query.Result = []*m.DashboardAclInfoDTO{
+					{DashboardId: 10, Role: &adminRole, Permission: m.PERMISSION_ADMIN},
+					{DashboardId: 12, UserId: viewer.UserId, Permission: m.PERMISSION_VIEW},
+				}
+				query.ResultHasAcl = map[int64]bool{10: true, 12: true, 14: true}
+				return nil
+			})
+
+			// dashboard 11 is in folder 10, dashboard 13 has no folder and
+			// dashboard 14 has an empty acl
+			dashboards := map[int64]int64{10: 0, 11: 10, 12: 10, 13: 0, 14: 0}
+
+			Convey("should inherit folder entries", func() {
+				allowed, err := FilterDashboards(viewer, m.PERMISSION_VIEW, dashboards)
+				So(err, ShouldBeNil)
+				So(allowed, ShouldResemble, map[int64]bool{12: true, 13: true})
+			})
+
+			Convey("should allow org admins all dashboards", func() {
+				allowed, err := FilterDashboards(admin, m.PERMISSION_ADMIN, dashboards)
+				So(err, ShouldBeNil)
+				So(len(allowed), ShouldEqual, 5)
+			})
+		})
+	})
+}
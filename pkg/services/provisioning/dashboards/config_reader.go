@@ -0,0 +1,96 @@
+package dashboards
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const defaultUpdateIntervalSeconds = 10
+
+type configReader struct {
+	path string
+}
+
+// readConfig reads all yaml files in the config folder. A missing folder
+// is not an error, it just means that nothing is provisioned.
+func (cr *configReader) readConfig() ([]*DashboardsAsConfig, error) {
+	var dashboards []*DashboardsAsConfig
+
+	files, err := ioutil.ReadDir(cr.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return dashboards, nil
+		}
+		return nil, err
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !isYamlFile(file.Name()) {
+			continue
+		}
+
+		filename := filepath.Join(cr.path, file.Name())
+		yamlFile, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		var configs []*DashboardsAsConfig
+		if err := yaml.Unmarshal(yamlFile, &configs); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %v", filename, err)
+		}
+
+		for _, cfg := range configs {
+			if err := validateConfig(cfg); err != nil {
+				return nil, fmt.Errorf("Invalid dashboard provider in %s: %v", filename, err)
+			}
+		}
+
+		dashboards = append(dashboards, configs...)
+	}
+
+	names := make(map[string]bool)
+	for _, cfg := range dashboards {
+		if names[cfg.Name] {
+			return nil, fmt.Errorf("Dashboard provider name %s is used more than once", cfg.Name)
+		}
+		names[cfg.Name] = true
+	}
+
+	return dashboards, nil
+}
+
+// validateConfig checks the required fields of a provider and sets the
+// defaults of the optional ones.
+func validateConfig(cfg *DashboardsAsConfig) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if cfg.Type == "" {
+		cfg.Type = "file"
+	}
+
+	if cfg.Type != "file" {
+		return fmt.Errorf("unknown type %s for provider %s", cfg.Type, cfg.Name)
+	}
+
+	if cfg.OrgId == 0 {
+		cfg.OrgId = 1
+	}
+
+	if cfg.UpdateIntervalSeconds <= 0 {
+		cfg.UpdateIntervalSeconds = defaultUpdateIntervalSeconds
+	}
+
+	return nil
+}
+
+func isYamlFile(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
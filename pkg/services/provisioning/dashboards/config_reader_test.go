@@ -0,0 +1,58 @@
+package dashboards
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var (
+	simpleDashboardConfig = "./test-configs/dashboards-from-disk"
+	brokenConfigs         = "./test-configs/broken-configs"
+)
+
+func TestDashboardsAsConfig(t *testing.T) {
+	Convey("Dashboards as configuration", t, func() {
+
+		Convey("Can read config file", func() {
+			cfgProvider := configReader{path: simpleDashboardConfig}
+			cfg, err := cfgProvider.readConfig()
+			So(err, ShouldBeNil)
+
+			So(len(cfg), ShouldEqual, 2)
+
+			ws := cfg[0]
+			So(ws.Name, ShouldEqual, "general dashboards")
+			So(ws.Type, ShouldEqual, "file")
+			So(ws.OrgId, ShouldEqual, 2)
+			So(ws.Folder, ShouldEqual, "developers")
+			So(ws.Editable, ShouldBeTrue)
+			So(ws.AllowDeletion, ShouldBeTrue)
+			So(ws.UpdateIntervalSeconds, ShouldEqual, 15)
+			So(ws.Options["path"], ShouldEqual, "/var/lib/grafana/dashboards")
+
+			Convey("Should set defaults for optional fields", func() {
+				def := cfg[1]
+				So(def.Name, ShouldEqual, "default")
+				So(def.OrgId, ShouldEqual, 1)
+				So(def.Folder, ShouldEqual, "")
+				So(def.Editable, ShouldBeFalse)
+				So(def.AllowDeletion, ShouldBeFalse)
+				So(def.UpdateIntervalSeconds, ShouldEqual, defaultUpdateIntervalSeconds)
+			})
+		})
+
+		Convey("Should return error for providers without name", func() {
+			cfgProvider := configReader{path: brokenConfigs}
+			_, err := cfgProvider.readConfig()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Should not return error for missing config folder", func() {
+			cfgProvider := configReader{path: "/not/a/real/path"}
+			cfg, err := cfgProvider.readConfig()
+			So(err, ShouldBeNil)
+			So(len(cfg), ShouldEqual, 0)
+		})
+	})
+}
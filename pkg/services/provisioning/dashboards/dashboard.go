@@ -0,0 +1,46 @@
+package dashboards
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/log"
+)
+
+// DashboardProvisioner reads the dashboard providers from the config folder
+// and keeps the dashboards in the database in sync with the files on disk.
+type DashboardProvisioner struct {
+	cfgReader *configReader
+	log       log.Logger
+}
+
+func NewDashboardProvisioner(configDirectory string) *DashboardProvisioner {
+	return &DashboardProvisioner{
+		cfgReader: &configReader{path: configDirectory},
+		log:       log.New("provisioning.dashboard"),
+	}
+}
+
+// Provision syncs all providers once and keeps polling them for changes
+// until ctx is done.
+func (provider *DashboardProvisioner) Provision(ctx context.Context) error {
+	cfgs, err := provider.cfgReader.readConfig()
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range cfgs {
+		fileReader, err := NewDashboardFileReader(cfg)
+		if err != nil {
+			return fmt.Errorf("Failed to create dashboard provider %s: %v", cfg.Name, err)
+		}
+
+		if err := fileReader.startWalkingDisk(); err != nil {
+			provider.log.Error("Failed to provision dashboards", "name", cfg.Name, "error", err)
+		}
+
+		go fileReader.ReadAndListen(ctx)
+	}
+
+	return nil
+}
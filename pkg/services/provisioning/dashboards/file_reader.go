@@ -0,0 +1,233 @@
+package dashboards
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gosimple/slug"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// fileReader provisions the dashboards of one provider from a folder on disk.
+type fileReader struct {
+	Cfg  *DashboardsAsConfig
+	Path string
+	log  log.Logger
+}
+
+func NewDashboardFileReader(cfg *DashboardsAsConfig) (*fileReader, error) {
+	path, ok := cfg.Options["path"].(string)
+	if !ok || path == "" {
+		return nil, fmt.Errorf("options.path is required")
+	}
+
+	logger := log.New("provisioning.dashboard", "name", cfg.Name)
+
+	// walking a symlinked folder only visits the link itself
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	} else {
+		logger.Warn("Cannot read dashboard folder", "path", path, "error", err)
+	}
+
+	return &fileReader{
+		Cfg:  cfg,
+		Path: path,
+		log:  logger,
+	}, nil
+}
+
+// ReadAndListen polls the folder for changes until ctx is done.
+func (fr *fileReader) ReadAndListen(ctx context.Context) error {
+	ticker := time.NewTicker(fr.Cfg.updateInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := fr.startWalkingDisk(); err != nil {
+				fr.log.Error("Failed to provision dashboards", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// startWalkingDisk saves the dashboards that changed since the last run
+// and, if the provider allows it, deletes the dashboards whose files were
+// removed.
+func (fr *fileReader) startWalkingDisk() error {
+	if _, err := os.Stat(fr.Path); err != nil {
+		return err
+	}
+
+	folderId, err := getOrCreateFolderId(fr.Cfg)
+	if err != nil {
+		return err
+	}
+
+	query := &m.GetProvisionedDashboardDataQuery{Name: fr.Cfg.Name}
+	if err := bus.Dispatch(query); err != nil {
+		return err
+	}
+
+	provisioned := make(map[string]*m.DashboardProvisioning)
+	for _, data := range query.Result {
+		provisioned[data.ExternalId] = data
+	}
+
+	filesFoundOnDisk := make(map[string]os.FileInfo)
+	if err := filepath.Walk(fr.Path, createWalkFn(filesFoundOnDisk)); err != nil {
+		return err
+	}
+
+	fr.deleteDashboardsWithoutFile(provisioned, filesFoundOnDisk)
+
+	for path, fileInfo := range filesFoundOnDisk {
+		if err := fr.saveDashboard(path, folderId, fileInfo, provisioned[path]); err != nil {
+			fr.log.Error("Failed to save dashboard", "path", path, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (fr *fileReader) saveDashboard(path string, folderId int64, fileInfo os.FileInfo, provisioned *m.DashboardProvisioning) error {
+	if provisioned != nil && provisioned.Updated == fileInfo.ModTime().Unix() {
+		return nil
+	}
+
+	data, err := readDashboardFromFile(path)
+	if err != nil {
+		return err
+	}
+
+	if data.Get("title").MustString() == "" {
+		return m.ErrDashboardTitleEmpty
+	}
+
+	// the id in the file belongs to the instance the dashboard was exported
+	// from, a provisioned dashboard is found by its file or uid instead
+	data.Set("id", nil)
+	if provisioned != nil {
+		data.Set("id", provisioned.DashboardId)
+	}
+
+	cmd := &m.SaveProvisionedDashboardCommand{
+		DashboardCmd: &m.SaveDashboardCommand{
+			Dashboard: data,
+			OrgId:     fr.Cfg.OrgId,
+			FolderId:  folderId,
+			Overwrite: true,
+			Message:   "Provisioned from " + path,
+		},
+		DashboardProvisioning: &m.DashboardProvisioning{
+			Name:           fr.Cfg.Name,
+			ExternalId:     path,
+			AllowUiUpdates: fr.Cfg.Editable,
+			Updated:        fileInfo.ModTime().Unix(),
+		},
+	}
+
+	if err := dashboards.SaveProvisionedDashboard(cmd); err != nil {
+		return err
+	}
+
+	fr.log.Debug("Provisioned dashboard", "path", path, "id", cmd.Result.Id)
+	return nil
+}
+
+func (fr *fileReader) deleteDashboardsWithoutFile(provisioned map[string]*m.DashboardProvisioning, filesFoundOnDisk map[string]os.FileInfo) {
+	if !fr.Cfg.AllowDeletion {
+		return
+	}
+
+	for path, data := range provisioned {
+		if _, exists := filesFoundOnDisk[path]; exists {
+			continue
+		}
+
+		query := &m.GetDashboardQuery{Id: data.DashboardId, OrgId: fr.Cfg.OrgId}
+		if err := bus.Dispatch(query); err != nil {
+			fr.log.Error("Failed to find dashboard of removed file", "path", path, "error", err)
+			continue
+		}
+
+		cmd := &m.DeleteDashboardCommand{Slug: query.Result.Slug, OrgId: fr.Cfg.OrgId}
+		if err := bus.Dispatch(cmd); err != nil {
+			fr.log.Error("Failed to delete dashboard of removed file", "path", path, "error", err)
+			continue
+		}
+
+		fr.log.Info("Deleted dashboard of removed file", "path", path, "id", data.DashboardId)
+	}
+}
+
+// getOrCreateFolderId returns the id of the folder the provider puts its
+// dashboards in, the folder is created when it does not exist.
+func getOrCreateFolderId(cfg *DashboardsAsConfig) (int64, error) {
+	if cfg.Folder == "" {
+		return 0, nil
+	}
+
+	query := &m.GetDashboardQuery{Slug: slug.Make(strings.ToLower(cfg.Folder)), OrgId: cfg.OrgId}
+	err := bus.Dispatch(query)
+	if err != nil && err != m.ErrDashboardNotFound {
+		return 0, err
+	}
+
+	if err == nil {
+		if !query.Result.IsFolder {
+			return 0, fmt.Errorf("%s is a dashboard, not a folder", cfg.Folder)
+		}
+		return query.Result.Id, nil
+	}
+
+	cmd := &m.SaveDashboardCommand{
+		OrgId:    cfg.OrgId,
+		IsFolder: true,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{
+			"title": cfg.Folder,
+		}),
+	}
+
+	if err := bus.Dispatch(cmd); err != nil {
+		return 0, err
+	}
+
+	return cmd.Result.Id, nil
+}
+
+func createWalkFn(filesOnDisk map[string]os.FileInfo) filepath.WalkFunc {
+	return func(path string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !fileInfo.IsDir() && strings.HasSuffix(fileInfo.Name(), ".json") {
+			filesOnDisk[path] = fileInfo
+		}
+
+		return nil
+	}
+}
+
+func readDashboardFromFile(path string) (*simplejson.Json, error) {
+	reader, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return simplejson.NewFromReader(reader)
+}
@@ -0,0 +1,258 @@
+package dashboards
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var defaultDashboards = "./test-dashboards"
+
+func TestDashboardFileReader(t *testing.T) {
+	Convey("Dashboard file reader", t, func() {
+		bus.ClearBusHandlers()
+
+		fakeRepo := &fakeDashboardRepo{
+			provisioned: make(map[int64]*m.DashboardProvisioning),
+			dashboards:  make(map[int64]*m.Dashboard),
+		}
+		fakeRepo.register()
+
+		cfg := &DashboardsAsConfig{
+			Name:    "default",
+			Type:    "file",
+			OrgId:   1,
+			Options: map[string]interface{}{"path": defaultDashboards},
+		}
+
+		Convey("Can read default dashboards", func() {
+			reader, err := NewDashboardFileReader(cfg)
+			So(err, ShouldBeNil)
+
+			err = reader.startWalkingDisk()
+			So(err, ShouldBeNil)
+
+			So(len(fakeRepo.saved), ShouldEqual, 2)
+			for _, cmd := range fakeRepo.saved {
+				So(cmd.DashboardCmd.Overwrite, ShouldBeTrue)
+				So(cmd.DashboardCmd.FolderId, ShouldEqual, 0)
+				So(cmd.DashboardCmd.Dashboard.Get("id").Interface(), ShouldBeNil)
+				So(cmd.DashboardProvisioning.Name, ShouldEqual, "default")
+				So(cmd.DashboardProvisioning.AllowUiUpdates, ShouldBeFalse)
+			}
+
+			Convey("Should save the alerts of the dashboards", func() {
+				So(len(fakeRepo.alertsUpdated), ShouldEqual, 2)
+				So(fakeRepo.alertsUpdated[0].Dashboard.Id, ShouldNotEqual, 0)
+			})
+
+			Convey("Should not save unchanged dashboards again", func() {
+				err = reader.startWalkingDisk()
+				So(err, ShouldBeNil)
+				So(len(fakeRepo.saved), ShouldEqual, 2)
+			})
+		})
+
+		Convey("Should create the folder of the provider", func() {
+			cfg.Folder = "Team A"
+
+			reader, err := NewDashboardFileReader(cfg)
+			So(err, ShouldBeNil)
+
+			err = reader.startWalkingDisk()
+			So(err, ShouldBeNil)
+
+			So(len(fakeRepo.folders), ShouldEqual, 1)
+			So(fakeRepo.folders[0].Dashboard.Get("title").MustString(), ShouldEqual, "Team A")
+			So(len(fakeRepo.saved), ShouldEqual, 2)
+			So(fakeRepo.saved[0].DashboardCmd.FolderId, ShouldEqual, fakeRepo.folders[0].Result.Id)
+		})
+
+		Convey("Should not use a dashboard as folder", func() {
+			cfg.Folder = "Team B"
+			fakeRepo.dashboards[99] = &m.Dashboard{Id: 99, Slug: "team-b", OrgId: 1}
+
+			reader, err := NewDashboardFileReader(cfg)
+			So(err, ShouldBeNil)
+
+			err = reader.startWalkingDisk()
+			So(err, ShouldNotBeNil)
+			So(len(fakeRepo.saved), ShouldEqual, 0)
+		})
+
+		Convey("Should fail without a path", func() {
+			cfg.Options = map[string]interface{}{}
+
+			_, err := NewDashboardFileReader(cfg)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Should not save dashboards with invalid alerts", func() {
+			fakeRepo.invalidAlerts = true
+
+			reader, err := NewDashboardFileReader(cfg)
+			So(err, ShouldBeNil)
+
+			err = reader.startWalkingDisk()
+			So(err, ShouldBeNil)
+			So(len(fakeRepo.saved), ShouldEqual, 0)
+		})
+
+		Convey("Given a folder with a provisioned dashboard", func() {
+			dir, err := ioutil.TempDir("", "provisioning")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+
+			file := filepath.Join(dir, "dashboard.json")
+			err = ioutil.WriteFile(file, []byte(`{"title": "Disk"}`), 0644)
+			So(err, ShouldBeNil)
+
+			cfg.Options["path"] = dir
+			reader, err := NewDashboardFileReader(cfg)
+			So(err, ShouldBeNil)
+
+			err = reader.startWalkingDisk()
+			So(err, ShouldBeNil)
+			So(len(fakeRepo.saved), ShouldEqual, 1)
+			dashId := fakeRepo.saved[0].Result.Id
+
+			Convey("Should update the same dashboard when the file changes", func() {
+				modified := time.Now().Add(time.Minute)
+				err := os.Chtimes(file, modified, modified)
+				So(err, ShouldBeNil)
+
+				err = reader.startWalkingDisk()
+				So(err, ShouldBeNil)
+				So(len(fakeRepo.saved), ShouldEqual, 2)
+				So(fakeRepo.saved[1].DashboardCmd.Dashboard.Get("id").MustInt64(), ShouldEqual, dashId)
+			})
+
+			Convey("Should not overwrite a dashboard that was not provisioned when the file is retitled", func() {
+				fakeRepo.dashboards[99] = &m.Dashboard{Id: 99, Slug: "memory", Title: "Memory", OrgId: 1}
+
+				err := ioutil.WriteFile(file, []byte(`{"title": "Memory"}`), 0644)
+				So(err, ShouldBeNil)
+				modified := time.Now().Add(time.Minute)
+				err = os.Chtimes(file, modified, modified)
+				So(err, ShouldBeNil)
+
+				err = reader.startWalkingDisk()
+				So(err, ShouldBeNil)
+				So(len(fakeRepo.saved), ShouldEqual, 1)
+				So(fakeRepo.dashboards[99].Title, ShouldEqual, "Memory")
+				So(fakeRepo.provisioned[99], ShouldBeNil)
+				So(fakeRepo.dashboards[dashId].Title, ShouldEqual, "Disk")
+			})
+
+			Convey("Should delete the dashboard when the file is removed and deletion is allowed", func() {
+				cfg.AllowDeletion = true
+				err := os.Remove(file)
+				So(err, ShouldBeNil)
+
+				err = reader.startWalkingDisk()
+				So(err, ShouldBeNil)
+				So(fakeRepo.deleted, ShouldResemble, []string{"disk"})
+			})
+
+			Convey("Should keep the dashboard by default when the file is removed", func() {
+				err := os.Remove(file)
+				So(err, ShouldBeNil)
+
+				err = reader.startWalkingDisk()
+				So(err, ShouldBeNil)
+				So(len(fakeRepo.deleted), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+type fakeDashboardRepo struct {
+	saved         []*m.SaveProvisionedDashboardCommand
+	folders       []*m.SaveDashboardCommand
+	deleted       []string
+	provisioned   map[int64]*m.DashboardProvisioning
+	dashboards    map[int64]*m.Dashboard
+	alertsUpdated []*alerting.UpdateDashboardAlertsCommand
+	invalidAlerts bool
+}
+
+func (repo *fakeDashboardRepo) register() {
+	bus.AddHandler("test", func(cmd *alerting.ValidateDashboardAlertsCommand) error {
+		if repo.invalidAlerts {
+			return alerting.ValidationError{Reason: "invalid"}
+		}
+		return nil
+	})
+
+	bus.AddHandler("test", func(cmd *alerting.UpdateDashboardAlertsCommand) error {
+		repo.alertsUpdated = append(repo.alertsUpdated, cmd)
+		return nil
+	})
+
+	bus.AddHandler("test", func(cmd *m.SaveProvisionedDashboardCommand) error {
+		dash := cmd.DashboardCmd.GetDashboardModel()
+		for id, existing := range repo.dashboards {
+			if id != dash.Id && existing.Slug == dash.Slug && repo.provisioned[id] == nil {
+				return m.ErrDashboardNotProvisioned
+			}
+		}
+
+		if dash.Id == 0 {
+			dash.Id = int64(len(repo.dashboards) + 1)
+		}
+		repo.dashboards[dash.Id] = dash
+
+		cmd.DashboardProvisioning.DashboardId = dash.Id
+		repo.provisioned[dash.Id] = cmd.DashboardProvisioning
+
+		cmd.Result = dash
+		repo.saved = append(repo.saved, cmd)
+		return nil
+	})
+
+	bus.AddHandler("test", func(cmd *m.SaveDashboardCommand) error {
+		cmd.Result = cmd.GetDashboardModel()
+		cmd.Result.Id = int64(len(repo.dashboards) + 1)
+		repo.dashboards[cmd.Result.Id] = cmd.Result
+		repo.folders = append(repo.folders, cmd)
+		return nil
+	})
+
+	bus.AddHandler("test", func(query *m.GetProvisionedDashboardDataQuery) error {
+		query.Result = make([]*m.DashboardProvisioning, 0)
+		for _, data := range repo.provisioned {
+			if data.Name == query.Name {
+				query.Result = append(query.Result, data)
+			}
+		}
+		return nil
+	})
+
+	bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+		for _, dash := range repo.dashboards {
+			if (query.Id != 0 && dash.Id == query.Id) || (query.Slug != "" && dash.Slug == query.Slug) {
+				query.Result = dash
+				return nil
+			}
+		}
+		return m.ErrDashboardNotFound
+	})
+
+	bus.AddHandler("test", func(cmd *m.DeleteDashboardCommand) error {
+		for id, dash := range repo.dashboards {
+			if dash.Slug == cmd.Slug {
+				delete(repo.dashboards, id)
+				delete(repo.provisioned, id)
+			}
+		}
+		repo.deleted = append(repo.deleted, cmd.Slug)
+		return nil
+	})
+}
@@ -0,0 +1,23 @@
+package dashboards
+
+import (
+	"time"
+)
+
+// DashboardsAsConfig is a dashboard provider read from the provisioning
+// config. Each provider reads the dashboards of one folder on disk into a
+// folder of an org.
+type DashboardsAsConfig struct {
+	Name                  string                 `yaml:"name"`
+	Type                  string                 `yaml:"type"`
+	OrgId                 int64                  `yaml:"orgId"`
+	Folder                string                 `yaml:"folder"`
+	Editable              bool                   `yaml:"editable"`
+	AllowDeletion         bool                   `yaml:"allowDeletion"`
+	UpdateIntervalSeconds int64                  `yaml:"updateIntervalSeconds"`
+	Options               map[string]interface{} `yaml:"options"`
+}
+
+func (cfg *DashboardsAsConfig) updateInterval() time.Duration {
+	return time.Duration(cfg.UpdateIntervalSeconds) * time.Second
+}
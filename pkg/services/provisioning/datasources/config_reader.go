@@ -0,0 +1,113 @@
+package datasources
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+type configReader struct{}
+
+// readConfig reads all yaml files in the config folder. A missing folder
+// is not an error, it just means that nothing is provisioned.
+func (cr *configReader) readConfig(path string) ([]*DatasourcesAsConfig, error) {
+	var datasources []*DatasourcesAsConfig
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return datasources, nil
+		}
+		return nil, err
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !isYamlFile(file.Name()) {
+			continue
+		}
+
+		filename := filepath.Join(path, file.Name())
+		yamlFile, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		var cfg DatasourcesAsConfig
+		if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %v", filename, err)
+		}
+
+		if err := validateConfig(&cfg); err != nil {
+			return nil, fmt.Errorf("Invalid datasource config in %s: %v", filename, err)
+		}
+
+		datasources = append(datasources, &cfg)
+	}
+
+	if err := validateDefaultUniqueness(datasources); err != nil {
+		return nil, err
+	}
+
+	return datasources, nil
+}
+
+// validateConfig checks the required fields of the datasources and sets
+// the defaults of the optional ones.
+func validateConfig(cfg *DatasourcesAsConfig) error {
+	for _, ds := range cfg.Datasources {
+		if ds.Name == "" {
+			return fmt.Errorf("name is required")
+		}
+
+		if ds.Type == "" {
+			return fmt.Errorf("type is required for datasource %s", ds.Name)
+		}
+
+		if ds.OrgId == 0 {
+			ds.OrgId = 1
+		}
+
+		if ds.Access == "" {
+			ds.Access = "proxy"
+		}
+
+		ds.interpolate()
+	}
+
+	for _, ds := range cfg.DeleteDatasources {
+		if ds.Name == "" {
+			return fmt.Errorf("name is required for deleted datasources")
+		}
+
+		if ds.OrgId == 0 {
+			ds.OrgId = 1
+		}
+	}
+
+	return nil
+}
+
+func validateDefaultUniqueness(datasources []*DatasourcesAsConfig) error {
+	defaultCount := make(map[int64]int)
+
+	for _, cfg := range datasources {
+		for _, ds := range cfg.Datasources {
+			if ds.IsDefault {
+				defaultCount[ds.OrgId] = defaultCount[ds.OrgId] + 1
+				if defaultCount[ds.OrgId] > 1 {
+					return ErrInvalidConfigToManyDefault
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func isYamlFile(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
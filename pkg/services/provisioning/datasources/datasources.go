@@ -0,0 +1,142 @@
+package datasources
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+var (
+	ErrInvalidConfigToManyDefault = errors.New("datasource.yaml config is invalid. Only one datasource per organization can be marked as default")
+)
+
+// Provision applies the datasource config files in configDirectory.
+func Provision(configDirectory string) error {
+	dc := newDatasourceProvisioner(log.New("provisioning.datasources"))
+	return dc.applyChanges(configDirectory)
+}
+
+// DatasourceProvisioner creates, updates and deletes datasources by name
+// so they match the provisioning config. Datasources that were not created
+// by provisioning are never changed, provisioned datasources that are no
+// longer in the config become regular datasources.
+type DatasourceProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+}
+
+func newDatasourceProvisioner(log log.Logger) DatasourceProvisioner {
+	return DatasourceProvisioner{
+		log:         log,
+		cfgProvider: &configReader{},
+	}
+}
+
+type datasourceKey struct {
+	orgId int64
+	name  string
+}
+
+func (dc *DatasourceProvisioner) apply(cfg *DatasourcesAsConfig, configured map[datasourceKey]bool) error {
+	if err := dc.deleteDatasources(cfg.DeleteDatasources); err != nil {
+		return err
+	}
+
+	for _, ds := range cfg.Datasources {
+		if err := validateOrg(ds.OrgId); err != nil {
+			return err
+		}
+
+		query := &m.GetDataSourceByNameQuery{OrgId: ds.OrgId, Name: ds.Name}
+		err := bus.Dispatch(query)
+		if err != nil && err != m.ErrDataSourceNotFound {
+			return err
+		}
+
+		configured[datasourceKey{orgId: ds.OrgId, name: ds.Name}] = true
+
+		if err == m.ErrDataSourceNotFound {
+			dc.log.Info("inserting datasource from configuration", "name", ds.Name, "orgId", ds.OrgId)
+			if err := bus.Dispatch(createInsertCommand(ds)); err != nil {
+				return err
+			}
+		} else if !query.Result.Provisioned {
+			return fmt.Errorf("datasource %s in organization %d was not created by provisioning and cannot be overwritten", ds.Name, ds.OrgId)
+		} else {
+			dc.log.Debug("updating datasource from configuration", "name", ds.Name, "orgId", ds.OrgId)
+			if err := bus.Dispatch(createUpdateCommand(ds, query.Result.Id)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (dc *DatasourceProvisioner) applyChanges(configPath string) error {
+	configs, err := dc.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	configured := make(map[datasourceKey]bool)
+	for _, cfg := range configs {
+		if err := dc.apply(cfg, configured); err != nil {
+			return err
+		}
+	}
+
+	return dc.releaseRemovedDatasources(configured)
+}
+
+// releaseRemovedDatasources makes provisioned datasources that are no
+// longer in the config editable again.
+func (dc *DatasourceProvisioner) releaseRemovedDatasources(configured map[datasourceKey]bool) error {
+	query := &m.GetProvisionedDataSourcesQuery{}
+	if err := bus.Dispatch(query); err != nil {
+		return err
+	}
+
+	for _, ds := range query.Result {
+		if configured[datasourceKey{orgId: ds.OrgId, name: ds.Name}] {
+			continue
+		}
+
+		cmd := &m.ReleaseProvisionedDataSourceCommand{Id: ds.Id, OrgId: ds.OrgId}
+		if err := bus.Dispatch(cmd); err != nil {
+			return err
+		}
+
+		dc.log.Info("released datasource removed from configuration", "name", ds.Name, "orgId", ds.OrgId)
+	}
+
+	return nil
+}
+
+func (dc *DatasourceProvisioner) deleteDatasources(dsToDelete []*DeleteDatasourceConfig) error {
+	for _, ds := range dsToDelete {
+		cmd := &m.DeleteDataSourceByNameCommand{OrgId: ds.OrgId, Name: ds.Name}
+		if err := bus.Dispatch(cmd); err != nil {
+			return err
+		}
+
+		dc.log.Info("deleted datasource based on configuration", "name", ds.Name, "orgId", ds.OrgId)
+	}
+
+	return nil
+}
+
+func validateOrg(orgId int64) error {
+	query := &m.GetOrgByIdQuery{Id: orgId}
+	if err := bus.Dispatch(query); err != nil {
+		if err == m.ErrOrgNotFound {
+			return fmt.Errorf("organization %d of datasource does not exist", orgId)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package datasources
+
+import (
+	"os"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var (
+	logger log.Logger = log.New("fake.log")
+
+	allProperties     = "./test-configs/all-properties"
+	twoDatasourcesCfg = "./test-configs/insert-two-delete-two"
+	doubleDefaultCfg  = "./test-configs/double-default"
+	brokenYaml        = "./test-configs/broken-yaml"
+)
+
+func TestDatasourceAsConfig(t *testing.T) {
+	Convey("Testing datasource as configuration", t, func() {
+		bus.ClearBusHandlers()
+
+		fakeRepo := &fakeRepository{}
+		fakeRepo.register()
+
+		Convey("One configured datasource", func() {
+			Convey("no datasource in database", func() {
+				dc := newDatasourceProvisioner(logger)
+				err := dc.applyChanges(twoDatasourcesCfg)
+				So(err, ShouldBeNil)
+
+				So(len(fakeRepo.inserted), ShouldEqual, 2)
+				So(len(fakeRepo.updated), ShouldEqual, 0)
+				So(len(fakeRepo.deleted), ShouldEqual, 2)
+				So(fakeRepo.inserted[0].ReadOnly, ShouldBeTrue)
+			})
+
+			Convey("One provisioned datasource in database with same name", func() {
+				fakeRepo.loadAll = []*m.DataSource{
+					{Name: "Graphite", OrgId: 1, Id: 1, Provisioned: true},
+				}
+
+				dc := newDatasourceProvisioner(logger)
+				err := dc.applyChanges(twoDatasourcesCfg)
+				So(err, ShouldBeNil)
+
+				So(len(fakeRepo.inserted), ShouldEqual, 1)
+				So(len(fakeRepo.updated), ShouldEqual, 1)
+				So(fakeRepo.updated[0].Id, ShouldEqual, 1)
+				So(fakeRepo.updated[0].ReadOnly, ShouldBeTrue)
+			})
+
+			Convey("One datasource in database with same name that was not provisioned", func() {
+				fakeRepo.loadAll = []*m.DataSource{
+					{Name: "Graphite", OrgId: 1, Id: 1},
+				}
+
+				dc := newDatasourceProvisioner(logger)
+				err := dc.applyChanges(twoDatasourcesCfg)
+				So(err, ShouldNotBeNil)
+				So(len(fakeRepo.updated), ShouldEqual, 0)
+			})
+
+			Convey("Provisioned datasources removed from the config are released", func() {
+				fakeRepo.loadAll = []*m.DataSource{
+					{Name: "Graphite", OrgId: 1, Id: 1, Provisioned: true},
+					{Name: "Removed", OrgId: 1, Id: 2, Provisioned: true, ReadOnly: true},
+					{Name: "Manual", OrgId: 1, Id: 3},
+				}
+
+				dc := newDatasourceProvisioner(logger)
+				err := dc.applyChanges(twoDatasourcesCfg)
+				So(err, ShouldBeNil)
+
+				So(len(fakeRepo.released), ShouldEqual, 1)
+				So(fakeRepo.released[0].Id, ShouldEqual, 2)
+			})
+
+			Convey("Datasources are deleted before they are inserted", func() {
+				So(len(fakeRepo.deleted), ShouldEqual, 0)
+
+				dc := newDatasourceProvisioner(logger)
+				err := dc.applyChanges(twoDatasourcesCfg)
+				So(err, ShouldBeNil)
+
+				So(fakeRepo.deleted[0].Name, ShouldEqual, "GraphiteOld")
+				So(fakeRepo.deleted[1].Name, ShouldEqual, "PrometheusOld")
+				So(fakeRepo.deleted[1].OrgId, ShouldEqual, 1)
+			})
+
+			Convey("Datasources in missing orgs are not inserted", func() {
+				fakeRepo.missingOrgs = true
+
+				dc := newDatasourceProvisioner(logger)
+				err := dc.applyChanges(twoDatasourcesCfg)
+				So(err, ShouldNotBeNil)
+				So(len(fakeRepo.inserted), ShouldEqual, 0)
+			})
+		})
+
+		Convey("Multiple datasources in different organizations with isDefault in each organization", func() {
+			dc := newDatasourceProvisioner(logger)
+			err := dc.applyChanges(doubleDefaultCfg)
+
+			Convey("should raise error", func() {
+				So(err, ShouldEqual, ErrInvalidConfigToManyDefault)
+			})
+		})
+
+		Convey("Broken yaml should raise error", func() {
+			dc := newDatasourceProvisioner(logger)
+			err := dc.applyChanges(brokenYaml)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Missing config folder should not raise error", func() {
+			dc := newDatasourceProvisioner(logger)
+			err := dc.applyChanges("/not/a/real/path")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("can read all properties", func() {
+			os.Setenv("TEST_DS_BASIC_AUTH_PASSWORD", "secret")
+			os.Setenv("TEST_DS_URL", "http://nested")
+			defer os.Unsetenv("TEST_DS_BASIC_AUTH_PASSWORD")
+			defer os.Unsetenv("TEST_DS_URL")
+
+			cfgReader := &configReader{}
+			cfg, err := cfgReader.readConfig(allProperties)
+			So(err, ShouldBeNil)
+			So(len(cfg), ShouldEqual, 1)
+
+			dsCfg := cfg[0]
+			ds := dsCfg.Datasources[0]
+
+			So(ds.Name, ShouldEqual, "name")
+			So(ds.Type, ShouldEqual, "type")
+			So(ds.Access, ShouldEqual, "proxy")
+			So(ds.OrgId, ShouldEqual, 2)
+			So(ds.Url, ShouldEqual, "url")
+			So(ds.User, ShouldEqual, "user")
+			So(ds.Password, ShouldEqual, "password")
+			So(ds.Database, ShouldEqual, "database")
+			So(ds.BasicAuth, ShouldBeTrue)
+			So(ds.BasicAuthUser, ShouldEqual, "basic_auth_user")
+			So(ds.BasicAuthPassword, ShouldEqual, "secret")
+			So(ds.WithCredentials, ShouldBeTrue)
+			So(ds.IsDefault, ShouldBeTrue)
+			So(ds.Editable, ShouldBeTrue)
+
+			cmd := createInsertCommand(ds)
+			So(cmd.ReadOnly, ShouldBeFalse)
+			So(cmd.JsonData.Get("graphiteVersion").MustString(), ShouldEqual, "1.1")
+			So(cmd.JsonData.Get("tlsAuth").MustBool(), ShouldBeTrue)
+			So(cmd.JsonData.Get("tlsAuthWithCACert").MustBool(), ShouldBeTrue)
+			So(cmd.JsonData.GetPath("nested", "url").MustString(), ShouldEqual, "http://nested")
+
+			_, err = cmd.JsonData.Encode()
+			So(err, ShouldBeNil)
+
+			So(len(ds.SecureJsonData), ShouldBeGreaterThan, 2)
+			So(ds.SecureJsonData["tlsCACert"], ShouldEqual, "MjNOcW9RdkbUDHZmpco2HCYzVq9dE+i6Yi+gmUJotq5CDA==")
+			So(ds.SecureJsonData["tlsClientCert"], ShouldEqual, "ckN0dGlyMXN503YNfjTcf9CV+GGQneN+xmAclQ==")
+			So(ds.SecureJsonData["tlsClientKey"], ShouldEqual, "ZkN4aG1aNkja/gKAB1wlnKFIsy2SRDq4slrM0A==")
+
+			Convey("should set defaults", func() {
+				ds2 := dsCfg.Datasources[1]
+				So(ds2.OrgId, ShouldEqual, 1)
+				So(ds2.Access, ShouldEqual, "proxy")
+				So(ds2.Editable, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+type fakeRepository struct {
+	inserted    []*m.AddDataSourceCommand
+	deleted     []*m.DeleteDataSourceByNameCommand
+	updated     []*m.UpdateDataSourceCommand
+	released    []*m.ReleaseProvisionedDataSourceCommand
+	loadAll     []*m.DataSource
+	missingOrgs bool
+}
+
+func (repo *fakeRepository) register() {
+	bus.AddHandler("test", func(cmd *m.DeleteDataSourceByNameCommand) error {
+		repo.deleted = append(repo.deleted, cmd)
+		return nil
+	})
+
+	bus.AddHandler("test", func(cmd *m.UpdateDataSourceCommand) error {
+		repo.updated = append(repo.updated, cmd)
+		return nil
+	})
+
+	bus.AddHandler("test", func(cmd *m.AddDataSourceCommand) error {
+		repo.inserted = append(repo.inserted, cmd)
+		return nil
+	})
+
+	bus.AddHandler("test", func(query *m.GetDataSourceByNameQuery) error {
+		for _, v := range repo.loadAll {
+			if query.Name == v.Name && query.OrgId == v.OrgId {
+				query.Result = v
+				return nil
+			}
+		}
+
+		return m.ErrDataSourceNotFound
+	})
+
+	bus.AddHandler("test", func(query *m.GetProvisionedDataSourcesQuery) error {
+		query.Result = make([]*m.DataSource, 0)
+		for _, v := range repo.loadAll {
+			if v.Provisioned {
+				query.Result = append(query.Result, v)
+			}
+		}
+		return nil
+	})
+
+	bus.AddHandler("test", func(cmd *m.ReleaseProvisionedDataSourceCommand) error {
+		repo.released = append(repo.released, cmd)
+		return nil
+	})
+
+	bus.AddHandler("test", func(query *m.GetOrgByIdQuery) error {
+		if repo.missingOrgs {
+			return m.ErrOrgNotFound
+		}
+
+		query.Result = &m.Org{Id: query.Id}
+		return nil
+	})
+}
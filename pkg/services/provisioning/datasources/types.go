@@ -0,0 +1,131 @@
+package datasources
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// DatasourcesAsConfig is the content of one datasource provisioning file.
+type DatasourcesAsConfig struct {
+	Datasources       []*DataSourceFromConfig   `yaml:"datasources"`
+	DeleteDatasources []*DeleteDatasourceConfig `yaml:"deleteDatasources"`
+}
+
+type DeleteDatasourceConfig struct {
+	OrgId int64  `yaml:"orgId"`
+	Name  string `yaml:"name"`
+}
+
+type DataSourceFromConfig struct {
+	OrgId int64 `yaml:"orgId"`
+
+	Name              string                 `yaml:"name"`
+	Type              string                 `yaml:"type"`
+	Access            string                 `yaml:"access"`
+	Url               string                 `yaml:"url"`
+	Password          string                 `yaml:"password"`
+	User              string                 `yaml:"user"`
+	Database          string                 `yaml:"database"`
+	BasicAuth         bool                   `yaml:"basicAuth"`
+	BasicAuthUser     string                 `yaml:"basicAuthUser"`
+	BasicAuthPassword string                 `yaml:"basicAuthPassword"`
+	WithCredentials   bool                   `yaml:"withCredentials"`
+	IsDefault         bool                   `yaml:"isDefault"`
+	JsonData          map[string]interface{} `yaml:"jsonData"`
+	SecureJsonData    map[string]string      `yaml:"secureJsonData"`
+	Editable          bool                   `yaml:"editable"`
+}
+
+// interpolate replaces ${VAR} expressions in all string values with the
+// value of the environment variable.
+func (ds *DataSourceFromConfig) interpolate() {
+	for _, value := range []*string{&ds.Url, &ds.Password, &ds.User, &ds.Database, &ds.BasicAuthUser, &ds.BasicAuthPassword} {
+		*value = setting.EvalEnvVarExpression(*value)
+	}
+
+	if ds.JsonData == nil {
+		ds.JsonData = make(map[string]interface{})
+	}
+	ds.JsonData = interpolateValue(ds.JsonData).(map[string]interface{})
+
+	for key, value := range ds.SecureJsonData {
+		ds.SecureJsonData[key] = setting.EvalEnvVarExpression(value)
+	}
+}
+
+// interpolateValue interpolates the strings in a value decoded from yaml.
+// Nested yaml maps have interface{} keys, they are converted to string keys
+// so the value can be encoded as json.
+func interpolateValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return setting.EvalEnvVarExpression(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = interpolateValue(item)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = interpolateValue(item)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = interpolateValue(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+func createInsertCommand(ds *DataSourceFromConfig) *m.AddDataSourceCommand {
+	return &m.AddDataSourceCommand{
+		OrgId:             ds.OrgId,
+		Name:              ds.Name,
+		Type:              ds.Type,
+		Access:            m.DsAccess(ds.Access),
+		Url:               ds.Url,
+		Password:          ds.Password,
+		User:              ds.User,
+		Database:          ds.Database,
+		BasicAuth:         ds.BasicAuth,
+		BasicAuthUser:     ds.BasicAuthUser,
+		BasicAuthPassword: ds.BasicAuthPassword,
+		WithCredentials:   ds.WithCredentials,
+		IsDefault:         ds.IsDefault,
+		JsonData:          simplejson.NewFromAny(ds.JsonData),
+		SecureJsonData:    ds.SecureJsonData,
+		ReadOnly:          !ds.Editable,
+		Provisioned:       true,
+	}
+}
+
+func createUpdateCommand(ds *DataSourceFromConfig, id int64) *m.UpdateDataSourceCommand {
+	return &m.UpdateDataSourceCommand{
+		Id:                id,
+		OrgId:             ds.OrgId,
+		Name:              ds.Name,
+		Type:              ds.Type,
+		Access:            m.DsAccess(ds.Access),
+		Url:               ds.Url,
+		Password:          ds.Password,
+		User:              ds.User,
+		Database:          ds.Database,
+		BasicAuth:         ds.BasicAuth,
+		BasicAuthUser:     ds.BasicAuthUser,
+		BasicAuthPassword: ds.BasicAuthPassword,
+		WithCredentials:   ds.WithCredentials,
+		IsDefault:         ds.IsDefault,
+		JsonData:          simplejson.NewFromAny(ds.JsonData),
+		SecureJsonData:    ds.SecureJsonData,
+		ReadOnly:          !ds.Editable,
+	}
+}
@@ -0,0 +1,23 @@
+package provisioning
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+)
+
+// Init applies the provisioning config found in provisioningPath and starts
+// watching the configured dashboard folders until ctx is done.
+func Init(ctx context.Context, provisioningPath string) error {
+	datasourcePath := filepath.Join(provisioningPath, "datasources")
+	if err := datasources.Provision(datasourcePath); err != nil {
+		return err
+	}
+
+	dashboardPath := filepath.Join(provisioningPath, "dashboards")
+	dashProvisioner := dashboards.NewDashboardProvisioner(dashboardPath)
+
+	return dashProvisioner.Provision(ctx)
+}
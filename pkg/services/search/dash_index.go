@@ -0,0 +1,476 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// weights of the dashboard fields used to rank search results
+const (
+	weightTitle       = 10
+	weightTag         = 6
+	weightDescription = 4
+	weightPanel       = 3
+	weightVariable    = 2
+	weightDatasource  = 1
+)
+
+// indexReloadInterval is how often the index checks the database for
+// dashboards saved or deleted by other instances.
+var indexReloadInterval = time.Second * 30
+
+// DashIndex is an in memory full text index of the dashboards in the
+// database. It is built on startup and kept up to date from the dashboard
+// events published by the sql store. Other instances sharing the database
+// do not publish events here, so the index is also reloaded when the
+// dashboards in the database change.
+type DashIndex struct {
+	mutex sync.RWMutex
+	items map[int64]*DashIndexItem
+	state *m.DashboardsState
+	log   log.Logger
+}
+
+type DashIndexItem struct {
+	Id          int64
+	Uid         string
+	OrgId       int64
+	FolderId    int64
+	IsFolder    bool
+	Title       string
+	Slug        string
+	Updated     time.Time
+	Views       int64
+	Tags        []string
+	Datasources []string
+	PanelTypes  []string
+
+	titleLower string
+	terms      map[string]int
+}
+
+func NewDashIndex() *DashIndex {
+	return &DashIndex{
+		items: make(map[int64]*DashIndexItem),
+		log:   log.New("search.index"),
+	}
+}
+
+// Load fills the index with all dashboards in the database.
+func (index *DashIndex) Load() error {
+	// read the state first so changes made while loading cause a reload
+	stateQuery := m.GetDashboardsStateQuery{}
+	if err := bus.Dispatch(&stateQuery); err != nil {
+		return err
+	}
+
+	dashQuery := m.GetAllDashboardsQuery{}
+	if err := bus.Dispatch(&dashQuery); err != nil {
+		return err
+	}
+
+	viewsQuery := m.GetDashboardViewsQuery{}
+	if err := bus.Dispatch(&viewsQuery); err != nil {
+		return err
+	}
+
+	defaultDatasources := make(map[int64]string)
+	items := make(map[int64]*DashIndexItem)
+	for _, dash := range dashQuery.Result {
+		defaultDatasource, ok := defaultDatasources[dash.OrgId]
+		if !ok {
+			var err error
+			if defaultDatasource, err = getDefaultDatasource(dash.OrgId); err != nil {
+				return err
+			}
+			defaultDatasources[dash.OrgId] = defaultDatasource
+		}
+
+		item := newDashIndexItem(dash, defaultDatasource)
+		item.Views = viewsQuery.Result[dash.Id]
+		items[dash.Id] = item
+	}
+
+	index.mutex.Lock()
+	index.items = items
+	index.state = stateQuery.Result
+	index.mutex.Unlock()
+
+	index.log.Info("Dashboard search index loaded", "dashboards", len(items))
+	return nil
+}
+
+// ReloadLoop reloads the index when the dashboards in the database have
+// changed until ctx is done.
+func (index *DashIndex) ReloadLoop(ctx context.Context) {
+	ticker := time.NewTicker(indexReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := index.reloadIfChanged(); err != nil {
+				index.log.Error("Failed to reload dashboard search index", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (index *DashIndex) reloadIfChanged() error {
+	query := m.GetDashboardsStateQuery{}
+	if err := bus.Dispatch(&query); err != nil {
+		return err
+	}
+
+	index.mutex.RLock()
+	current := index.state
+	index.mutex.RUnlock()
+
+	if current != nil && current.Count == query.Result.Count && current.LastUpdated.Equal(query.Result.LastUpdated) {
+		return nil
+	}
+
+	return index.Load()
+}
+
+// Listen registers the event listeners that keep the index up to date.
+func (index *DashIndex) Listen() {
+	bus.AddEventListener(index.onDashboardSaved)
+	bus.AddEventListener(index.onDashboardDeleted)
+	bus.AddEventListener(index.onDashboardViewed)
+	bus.AddEventListener(index.onOrgDeleted)
+}
+
+func (index *DashIndex) onDashboardSaved(evt *events.DashboardSaved) error {
+	query := m.GetDashboardQuery{Id: evt.Id, OrgId: evt.OrgId}
+	if err := bus.Dispatch(&query); err != nil {
+		index.log.Error("Failed to index dashboard", "id", evt.Id, "error", err)
+		return nil
+	}
+
+	defaultDatasource, err := getDefaultDatasource(evt.OrgId)
+	if err != nil {
+		index.log.Error("Failed to index dashboard", "id", evt.Id, "error", err)
+		return nil
+	}
+
+	item := newDashIndexItem(query.Result, defaultDatasource)
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	if existing, ok := index.items[item.Id]; ok {
+		item.Views = existing.Views
+	}
+	index.items[item.Id] = item
+
+	return nil
+}
+
+func (index *DashIndex) onDashboardDeleted(evt *events.DashboardDeleted) error {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	delete(index.items, evt.Id)
+	return nil
+}
+
+func (index *DashIndex) onDashboardViewed(evt *events.DashboardViewed) error {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	if item, ok := index.items[evt.Id]; ok {
+		item.Views++
+	}
+	return nil
+}
+
+func (index *DashIndex) onOrgDeleted(evt *events.OrgDeleted) error {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	for id, item := range index.items {
+		if item.OrgId == evt.Id {
+			delete(index.items, id)
+		}
+	}
+	return nil
+}
+
+// Search returns the dashboards and folders of the org matching the query,
+// with the relevance of each hit as score. Starred filters on the given
+// dashboard ids, it is ignored when the query is not for starred dashboards.
+func (index *DashIndex) Search(query *Query, starred map[int64]bool) HitList {
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+
+	queryText := strings.ToLower(strings.TrimSpace(query.Title))
+	queryTerms := tokenize(queryText)
+
+	hits := make(HitList, 0)
+	for _, item := range index.items {
+		if !item.matchesFilters(query, starred) {
+			continue
+		}
+
+		score, ok := item.score(queryText, queryTerms)
+		if !ok {
+			continue
+		}
+
+		hit := &Hit{
+			Id:       item.Id,
+			Uid:      item.Uid,
+			Title:    item.Title,
+			Uri:      "db/" + item.Slug,
+			Type:     DashHitDB,
+			Tags:     append([]string{}, item.Tags...),
+			FolderId: item.FolderId,
+			Score:    score,
+			Updated:  item.Updated,
+			Views:    item.Views,
+		}
+
+		if item.IsFolder {
+			hit.Type = DashHitFolder
+		}
+
+		if folder, ok := index.items[item.FolderId]; ok && item.FolderId > 0 {
+			hit.FolderTitle = folder.Title
+		}
+
+		hits = append(hits, hit)
+	}
+
+	return hits
+}
+
+func (item *DashIndexItem) matchesFilters(query *Query, starred map[int64]bool) bool {
+	if item.OrgId != query.OrgId {
+		return false
+	}
+
+	if query.IsStarred && !starred[item.Id] {
+		return false
+	}
+
+	if len(query.DashboardIds) > 0 && !containsId(query.DashboardIds, item.Id) {
+		return false
+	}
+
+	if len(query.FolderIds) > 0 && !containsFolderId(query.FolderIds, item.FolderId) {
+		return false
+	}
+
+	for _, datasource := range query.Datasources {
+		if !stringInSlice(datasource, item.Datasources) {
+			return false
+		}
+	}
+
+	for _, panelType := range query.PanelTypes {
+		if !stringInSlice(panelType, item.PanelTypes) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// score ranks the item for the query. Every query term has to match a term
+// of the item, exact matches rank higher than prefix and substring matches.
+// A title containing the whole query text gets an extra boost.
+func (item *DashIndexItem) score(queryText string, queryTerms []string) (float64, bool) {
+	if queryText == "" {
+		return 0, true
+	}
+
+	titleMatch := strings.Contains(item.titleLower, queryText)
+
+	var score float64
+	for _, queryTerm := range queryTerms {
+		var best float64
+		for term, weight := range item.terms {
+			switch {
+			case term == queryTerm:
+				best = maxFloat(best, float64(weight)*2)
+			case strings.HasPrefix(term, queryTerm):
+				best = maxFloat(best, float64(weight))
+			case strings.Contains(term, queryTerm):
+				best = maxFloat(best, float64(weight)/2)
+			}
+		}
+
+		if best == 0 && !titleMatch {
+			return 0, false
+		}
+		score += best
+	}
+
+	if titleMatch {
+		score += weightTitle * 2
+	}
+
+	return score, true
+}
+
+// getDefaultDatasource returns the name of the default datasource of the org,
+// or an empty string when the org has no default datasource.
+func getDefaultDatasource(orgId int64) (string, error) {
+	query := m.GetDataSourcesQuery{OrgId: orgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return "", err
+	}
+
+	for _, ds := range query.Result {
+		if ds.IsDefault {
+			return ds.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// newDashIndexItem indexes the dashboard. Query variables, annotations and
+// panels with targets that have no datasource use the default datasource of
+// the org.
+func newDashIndexItem(dash *m.Dashboard, defaultDatasource string) *DashIndexItem {
+	item := &DashIndexItem{
+		Id:          dash.Id,
+		Uid:         dash.Uid,
+		OrgId:       dash.OrgId,
+		FolderId:    dash.FolderId,
+		IsFolder:    dash.IsFolder,
+		Title:       dash.Title,
+		Slug:        dash.Slug,
+		Updated:     dash.Updated,
+		Tags:        dash.GetTags(),
+		Datasources: make([]string, 0),
+		PanelTypes:  make([]string, 0),
+		titleLower:  strings.ToLower(dash.Title),
+		terms:       make(map[string]int),
+	}
+
+	data := dash.Data
+	if data == nil {
+		data = simplejson.New()
+	}
+
+	item.addTerms(dash.Title, weightTitle)
+	item.addTerms(data.Get("description").MustString(), weightDescription)
+
+	for _, tag := range item.Tags {
+		item.addTerms(tag, weightTag)
+	}
+
+	for _, variable := range data.Get("templating").Get("list").MustArray() {
+		variableJson := simplejson.NewFromAny(variable)
+		item.addTerms(variableJson.Get("name").MustString(), weightVariable)
+		item.addTerms(variableJson.Get("label").MustString(), weightVariable)
+		if variableJson.Get("type").MustString() == "query" {
+			item.addDatasource(variableJson.Get("datasource"), defaultDatasource)
+		} else {
+			item.addDatasource(variableJson.Get("datasource"), "")
+		}
+	}
+
+	for _, annotation := range data.Get("annotations").Get("list").MustArray() {
+		item.addDatasource(simplejson.NewFromAny(annotation).Get("datasource"), defaultDatasource)
+	}
+
+	for _, row := range data.Get("rows").MustArray() {
+		for _, panel := range simplejson.NewFromAny(row).Get("panels").MustArray() {
+			panelJson := simplejson.NewFromAny(panel)
+			item.addTerms(panelJson.Get("title").MustString(), weightPanel)
+			targets := panelJson.Get("targets").MustArray()
+			if len(targets) > 0 {
+				item.addDatasource(panelJson.Get("datasource"), defaultDatasource)
+			} else {
+				item.addDatasource(panelJson.Get("datasource"), "")
+			}
+
+			if panelType := panelJson.Get("type").MustString(); panelType != "" && !stringInSlice(panelType, item.PanelTypes) {
+				item.PanelTypes = append(item.PanelTypes, panelType)
+			}
+
+			for _, target := range targets {
+				item.addDatasource(simplejson.NewFromAny(target).Get("datasource"), "")
+			}
+		}
+	}
+
+	for _, datasource := range item.Datasources {
+		item.addTerms(datasource, weightDatasource)
+	}
+
+	sort.Strings(item.PanelTypes)
+	sort.Strings(item.Datasources)
+
+	return item
+}
+
+// addDatasource records a datasource referenced by name. References without
+// name use the default datasource, it is not recorded when empty.
+func (item *DashIndexItem) addDatasource(value *simplejson.Json, defaultDatasource string) {
+	name := value.MustString()
+	if name == "" {
+		name = defaultDatasource
+	}
+	if name == "" || name == "-- Mixed --" || stringInSlice(name, item.Datasources) {
+		return
+	}
+
+	item.Datasources = append(item.Datasources, name)
+}
+
+func (item *DashIndexItem) addTerms(text string, weight int) {
+	for _, term := range tokenize(text) {
+		if weight > item.terms[term] {
+			item.terms[term] = weight
+		}
+	}
+}
+
+// tokenize splits text into lower case words.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func containsId(ids []int, id int64) bool {
+	for _, i := range ids {
+		if int64(i) == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFolderId(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,244 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/events"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDashIndex(t *testing.T) {
+	Convey("Given a dashboard index", t, func() {
+		bus.ClearBusHandlers()
+
+		dashboards := []*m.Dashboard{
+			indexTestDashboard(1, 1, 0, `{
+				"title": "Web servers",
+				"tags": ["web", "prod"],
+				"description": "Request rates of the nginx servers",
+				"templating": {"list": [{"name": "host", "datasource": "graphite"}]},
+				"rows": [{"panels": [
+					{"type": "graph", "title": "Requests", "datasource": "graphite"},
+					{"type": "singlestat", "title": "Errors", "datasource": "-- Mixed --", "targets": [{"datasource": "influx"}]}
+				]}]
+			}`),
+			indexTestDashboard(2, 1, 3, `{
+				"title": "Database",
+				"description": "Slow queries of the web backend",
+				"templating": {"list": [{"name": "interval", "type": "interval", "datasource": null}]},
+				"rows": [{"panels": [
+					{"type": "table", "title": "Queries", "datasource": "mysql"},
+					{"type": "text", "title": "Notes"}
+				]}]
+			}`),
+			indexTestDashboard(3, 1, 0, `{"title": "Backend"}`),
+			indexTestDashboard(4, 2, 0, `{
+				"title": "Web servers",
+				"annotations": {"list": [{"name": "Deploys", "datasource": null}]},
+				"rows": [{"panels": [{"type": "graph", "title": "Load", "datasource": null, "targets": [{"refId": "A"}]}]}]
+			}`),
+		}
+		dashboards[2].IsFolder = true
+
+		bus.AddHandler("test", func(query *m.GetAllDashboardsQuery) error {
+			query.Result = dashboards
+			return nil
+		})
+
+		bus.AddHandler("test", func(query *m.GetDashboardViewsQuery) error {
+			query.Result = map[int64]int64{2: 10}
+			return nil
+		})
+
+		state := m.DashboardsState{Count: 4, LastUpdated: time.Unix(4, 0)}
+		bus.AddHandler("test", func(query *m.GetDashboardsStateQuery) error {
+			result := state
+			query.Result = &result
+			return nil
+		})
+
+		bus.AddHandler("test", func(query *m.GetDataSourcesQuery) error {
+			query.Result = []*m.DataSource{{Name: "influx"}, {Name: "elastic", IsDefault: true}}
+			if query.OrgId == 2 {
+				query.Result = []*m.DataSource{{Name: "prometheus", IsDefault: true}}
+			}
+			return nil
+		})
+
+		index := NewDashIndex()
+		So(index.Load(), ShouldBeNil)
+		index.Listen()
+
+		search := func(query *Query) HitList {
+			if query.OrgId == 0 {
+				query.OrgId = 1
+			}
+			return index.Search(query, nil)
+		}
+
+		Convey("Should index the dashboard json", func() {
+			item := index.items[1]
+			So(item.Datasources, ShouldResemble, []string{"graphite", "influx"})
+			So(item.PanelTypes, ShouldResemble, []string{"graph", "singlestat"})
+			So(item.terms["servers"], ShouldEqual, weightTitle)
+			So(item.terms["web"], ShouldEqual, weightTitle)
+			So(item.terms["nginx"], ShouldEqual, weightDescription)
+			So(item.terms["requests"], ShouldEqual, weightPanel)
+			So(item.terms["host"], ShouldEqual, weightVariable)
+			So(item.terms["influx"], ShouldEqual, weightDatasource)
+			So(index.items[2].Views, ShouldEqual, 10)
+		})
+
+		Convey("Should use the default datasource for panels and annotations without datasource", func() {
+			So(index.items[2].Datasources, ShouldResemble, []string{"mysql"})
+			So(index.items[4].Datasources, ShouldResemble, []string{"prometheus"})
+			So(hitIds(search(&Query{OrgId: 2, Datasources: []string{"prometheus"}})), ShouldResemble, []int64{4})
+		})
+
+		Convey("Should return all dashboards of the org without query", func() {
+			hits := search(&Query{})
+			So(len(hits), ShouldEqual, 3)
+		})
+
+		Convey("Should match description, panel titles and variables", func() {
+			So(hitIds(search(&Query{Title: "nginx"})), ShouldResemble, []int64{1})
+			So(hitIds(search(&Query{Title: "errors"})), ShouldResemble, []int64{1})
+			So(hitIds(search(&Query{Title: "host"})), ShouldResemble, []int64{1})
+			So(hitIds(search(&Query{Title: "slow"})), ShouldResemble, []int64{2})
+		})
+
+		Convey("Should require all query terms to match", func() {
+			So(hitIds(search(&Query{Title: "web nginx"})), ShouldResemble, []int64{1})
+			So(len(search(&Query{Title: "web mysql"})), ShouldEqual, 1)
+			So(len(search(&Query{Title: "web unknown"})), ShouldEqual, 0)
+		})
+
+		Convey("Should match parts of words", func() {
+			So(hitIds(search(&Query{Title: "serv"})), ShouldResemble, []int64{1})
+			So(hitIds(search(&Query{Title: "abase"})), ShouldResemble, []int64{2})
+		})
+
+		Convey("Should rank title matches higher", func() {
+			hits := search(&Query{Title: "web"})
+			So(len(hits), ShouldEqual, 2)
+
+			sortHits(hits, SortRelevance)
+			So(hits[0].Id, ShouldEqual, 1)
+			So(hits[0].Score, ShouldBeGreaterThan, hits[1].Score)
+		})
+
+		Convey("Should filter by datasource and panel type", func() {
+			So(hitIds(search(&Query{Datasources: []string{"influx"}})), ShouldResemble, []int64{1})
+			So(hitIds(search(&Query{Datasources: []string{"mysql"}})), ShouldResemble, []int64{2})
+			So(hitIds(search(&Query{PanelTypes: []string{"table"}})), ShouldResemble, []int64{2})
+			So(len(search(&Query{PanelTypes: []string{"table", "graph"}})), ShouldEqual, 0)
+		})
+
+		Convey("Should filter by starred, dashboard and folder ids", func() {
+			So(hitIds(index.Search(&Query{OrgId: 1, IsStarred: true}, map[int64]bool{2: true})), ShouldResemble, []int64{2})
+			So(hitIds(search(&Query{DashboardIds: []int{1}})), ShouldResemble, []int64{1})
+			So(hitIds(search(&Query{FolderIds: []int64{3}})), ShouldResemble, []int64{2})
+		})
+
+		Convey("Should set folder title and type", func() {
+			hits := search(&Query{Title: "database"})
+			So(hits[0].FolderTitle, ShouldEqual, "Backend")
+
+			hits = search(&Query{Title: "backend"})
+			sortHits(hits, SortRelevance)
+			So(hits[0].Type, ShouldEqual, DashHitFolder)
+		})
+
+		Convey("When a dashboard is saved", func() {
+			bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+				query.Result = indexTestDashboard(query.Id, query.OrgId, 0, `{"title": "Queues", "rows": [{"panels": [{"type": "heatmap"}]}]}`)
+				return nil
+			})
+
+			err := bus.Publish(&events.DashboardSaved{Id: 2, OrgId: 1})
+			So(err, ShouldBeNil)
+
+			Convey("Should update the dashboard and keep its views", func() {
+				So(hitIds(search(&Query{Title: "queues"})), ShouldResemble, []int64{2})
+				So(len(search(&Query{Title: "slow"})), ShouldEqual, 0)
+				So(index.items[2].PanelTypes, ShouldResemble, []string{"heatmap"})
+				So(index.items[2].Views, ShouldEqual, 10)
+			})
+		})
+
+		Convey("When a dashboard is deleted", func() {
+			err := bus.Publish(&events.DashboardDeleted{Id: 1, OrgId: 1})
+			So(err, ShouldBeNil)
+
+			Convey("Should remove the dashboard", func() {
+				So(len(search(&Query{Title: "web"})), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When a dashboard is viewed", func() {
+			err := bus.Publish(&events.DashboardViewed{Id: 1, OrgId: 1})
+			So(err, ShouldBeNil)
+
+			Convey("Should count the view", func() {
+				So(index.items[1].Views, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When another instance changes the dashboards", func() {
+			dashboards = append(dashboards, indexTestDashboard(5, 1, 0, `{"title": "Queues"}`))
+
+			Convey("Should not reload while the state is unchanged", func() {
+				So(index.reloadIfChanged(), ShouldBeNil)
+				So(len(search(&Query{Title: "queues"})), ShouldEqual, 0)
+			})
+
+			Convey("Should reload when a dashboard was added", func() {
+				state.Count = 5
+				So(index.reloadIfChanged(), ShouldBeNil)
+				So(hitIds(search(&Query{Title: "queues"})), ShouldResemble, []int64{5})
+			})
+
+			Convey("Should reload when a dashboard was updated", func() {
+				state.LastUpdated = time.Unix(5, 0)
+				So(index.reloadIfChanged(), ShouldBeNil)
+				So(hitIds(search(&Query{Title: "queues"})), ShouldResemble, []int64{5})
+			})
+		})
+
+		Convey("When an org is deleted", func() {
+			err := bus.Publish(&events.OrgDeleted{Id: 2})
+			So(err, ShouldBeNil)
+
+			Convey("Should remove the dashboards of the org", func() {
+				So(len(index.items), ShouldEqual, 3)
+				So(len(search(&Query{OrgId: 2})), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func indexTestDashboard(id int64, orgId int64, folderId int64, data string) *m.Dashboard {
+	json, err := simplejson.NewJson([]byte(data))
+	So(err, ShouldBeNil)
+
+	dash := m.NewDashboardFromJson(json)
+	dash.Id = id
+	dash.OrgId = orgId
+	dash.FolderId = folderId
+	dash.Updated = time.Unix(id, 0)
+	return dash
+}
+
+func hitIds(hits HitList) []int64 {
+	sortHits(hits, SortTitle)
+
+	ids := make([]int64, 0)
+	for _, hit := range hits {
+		ids = append(ids, hit.Id)
+	}
+	return ids
+}
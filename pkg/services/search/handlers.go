@@ -1,25 +1,37 @@
 package search
 
 import (
-	"log"
+	"context"
+	"errors"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/bus"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 var jsonDashIndex *JsonDashIndex
+var dashIndex *DashIndex
 
-func Init() {
+// Init registers the search handler and loads the dashboard search index,
+// the index is reloaded in the background until ctx is done.
+func Init(ctx context.Context) error {
 	bus.AddHandler("search", searchHandler)
 
+	dashIndex = NewDashIndex()
+	if err := dashIndex.Load(); err != nil {
+		return err
+	}
+	dashIndex.Listen()
+	go dashIndex.ReloadLoop(ctx)
+
 	jsonIndexCfg, _ := setting.Cfg.GetSection("dashboards.json")
 
 	if jsonIndexCfg == nil {
-		log.Fatal("Config section missing: dashboards.json")
-		return
+		return errors.New("Config section missing: dashboards.json")
 	}
 
 	jsonIndexEnabled := jsonIndexCfg.Key("enabled").MustBool(false)
@@ -33,26 +45,38 @@ func Init() {
 		jsonDashIndex = NewJsonDashIndex(jsonFilesPath)
 		go jsonDashIndex.updateLoop()
 	}
+
+	return nil
 }
 
 func searchHandler(query *Query) error {
+	sortBy, err := resolveSort(query)
+	if err != nil {
+		return err
+	}
+
+	starsQuery := m.GetUserStarsQuery{UserId: query.UserId}
+	if err := bus.Dispatch(&starsQuery); err != nil {
+		return err
+	}
+
 	hits := make(HitList, 0)
 
-	dashQuery := FindPersistedDashboardsQuery{
-		Title:        query.Title,
-		UserId:       query.UserId,
-		IsStarred:    query.IsStarred,
-		OrgId:        query.OrgId,
-		DashboardIds: query.DashboardIds,
+	persisted, err := findPersistedDashboards(query, starsQuery.Result)
+	if err != nil {
+		return err
 	}
 
-	if err := bus.Dispatch(&dashQuery); err != nil {
+	viewable, err := filterViewableHits(query.SignedInUser, persisted)
+	if err != nil {
 		return err
 	}
 
-	hits = append(hits, dashQuery.Result...)
+	hits = append(hits, viewable...)
 
-	if jsonDashIndex != nil {
+	// json dashboards are never in a folder and are not indexed by
+	// datasource or panel type
+	if jsonDashIndex != nil && len(query.FolderIds) == 0 && len(query.Datasources) == 0 && len(query.PanelTypes) == 0 {
 		jsonHits, err := jsonDashIndex.Search(query)
 		if err != nil {
 			return err
@@ -73,26 +97,137 @@ func searchHandler(query *Query) error {
 	}
 
 	// sort main result array
-	sort.Sort(hits)
+	sortHits(hits, sortBy)
 
 	if len(hits) > query.Limit {
 		hits = hits[0:query.Limit]
 	}
 
+	hits = paginateHits(hits, query.Page, query.PerPage)
+
 	// sort tags
 	for _, hit := range hits {
 		sort.Strings(hit.Tags)
 	}
 
 	// add isStarred info
-	if err := setIsStarredFlagOnSearchResults(query.UserId, hits); err != nil {
-		return err
-	}
+	setIsStarredFlagOnSearchResults(starsQuery.Result, hits)
 
 	query.Result = hits
 	return nil
 }
 
+// findPersistedDashboards searches the dashboards in the database, using
+// the search index when it has been initialized.
+func findPersistedDashboards(query *Query, starred map[int64]bool) (HitList, error) {
+	if dashIndex != nil {
+		return dashIndex.Search(query, starred), nil
+	}
+
+	dashQuery := FindPersistedDashboardsQuery{
+		Title:        query.Title,
+		UserId:       query.UserId,
+		IsStarred:    query.IsStarred,
+		OrgId:        query.OrgId,
+		DashboardIds: query.DashboardIds,
+		FolderIds:    query.FolderIds,
+	}
+
+	if err := bus.Dispatch(&dashQuery); err != nil {
+		return nil, err
+	}
+
+	return dashQuery.Result, nil
+}
+
+// resolveSort validates the sort option of the query. Without sort option
+// text searches are sorted by relevance and other searches by title.
+func resolveSort(query *Query) (string, error) {
+	switch query.Sort {
+	case "":
+		if strings.TrimSpace(query.Title) != "" {
+			return SortRelevance, nil
+		}
+		return SortTitle, nil
+	case SortTitle, SortRelevance, SortUpdated, SortViews:
+		return query.Sort, nil
+	}
+
+	return "", ErrInvalidSort
+}
+
+// sortHits sorts the hits by title first so hits with the same relevance,
+// update time or views stay in title order.
+func sortHits(hits HitList, sortBy string) {
+	sort.Sort(hits)
+
+	switch sortBy {
+	case SortRelevance:
+		sort.SliceStable(hits, func(i, j int) bool {
+			return hits[i].Score > hits[j].Score
+		})
+	case SortUpdated:
+		sort.SliceStable(hits, func(i, j int) bool {
+			return hits[i].Updated.After(hits[j].Updated)
+		})
+	case SortViews:
+		sort.SliceStable(hits, func(i, j int) bool {
+			return hits[i].Views > hits[j].Views
+		})
+	}
+}
+
+// paginateHits returns the hits of a page, pages start at 1. All hits are
+// returned when perPage is 0.
+func paginateHits(hits HitList, page int, perPage int) HitList {
+	if perPage <= 0 {
+		return hits
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	start := (page - 1) * perPage
+	if start >= len(hits) {
+		return HitList{}
+	}
+
+	end := start + perPage
+	if end > len(hits) {
+		end = len(hits)
+	}
+
+	return hits[start:end]
+}
+
+// filterViewableHits removes the dashboards and folders the user is not
+// allowed to view. Hits are not filtered for internal searches without a user.
+func filterViewableHits(user *m.SignedInUser, hits HitList) (HitList, error) {
+	if user == nil || len(hits) == 0 {
+		return hits, nil
+	}
+
+	dashboards := make(map[int64]int64)
+	for _, hit := range hits {
+		dashboards[hit.Id] = hit.FolderId
+	}
+
+	allowed, err := guardian.FilterDashboards(user, m.PERMISSION_VIEW, dashboards)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make(HitList, 0)
+	for _, hit := range hits {
+		if allowed[hit.Id] {
+			filtered = append(filtered, hit)
+		}
+	}
+
+	return filtered, nil
+}
+
 func stringInSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
@@ -112,19 +247,12 @@ func hasRequiredTags(queryTags, hitTags []string) bool {
 	return true
 }
 
-func setIsStarredFlagOnSearchResults(userId int64, hits []*Hit) error {
-	query := m.GetUserStarsQuery{UserId: userId}
-	if err := bus.Dispatch(&query); err != nil {
-		return err
-	}
-
+func setIsStarredFlagOnSearchResults(starred map[int64]bool, hits []*Hit) {
 	for _, dash := range hits {
-		if _, exists := query.Result[dash.Id]; exists {
+		if _, exists := starred[dash.Id]; exists {
 			dash.IsStarred = true
 		}
 	}
-
-	return nil
 }
 
 func GetDashboardFromJsonIndex(filename string) *m.Dashboard {
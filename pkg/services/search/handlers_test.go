@@ -1,7 +1,9 @@
 package search
 
 import (
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
 	m "github.com/grafana/grafana/pkg/models"
@@ -16,9 +18,9 @@ func TestSearch(t *testing.T) {
 
 		bus.AddHandler("test", func(query *FindPersistedDashboardsQuery) error {
 			query.Result = HitList{
-				&Hit{Id: 16, Title: "CCAA", Tags: []string{"BB", "AA"}},
-				&Hit{Id: 10, Title: "AABB", Tags: []string{"CC", "AA"}},
-				&Hit{Id: 15, Title: "BBAA", Tags: []string{"EE", "AA", "BB"}},
+				&Hit{Id: 16, Title: "CCAA", Tags: []string{"BB", "AA"}, Views: 5, Updated: time.Unix(2, 0)},
+				&Hit{Id: 10, Title: "AABB", Tags: []string{"CC", "AA"}, Views: 1, Updated: time.Unix(3, 0)},
+				&Hit{Id: 15, Title: "BBAA", Tags: []string{"EE", "AA", "BB"}, Views: 5, Updated: time.Unix(1, 0)},
 			}
 			return nil
 		})
@@ -45,6 +47,81 @@ func TestSearch(t *testing.T) {
 			})
 		})
 
+		Convey("That is made by a viewer", func() {
+			viewer := m.ROLE_VIEWER
+			bus.AddHandler("test", func(query *m.GetOrgDashboardAclsQuery) error {
+				query.Result = []*m.DashboardAclInfoDTO{
+					{DashboardId: 10, UserId: 2, Permission: m.PERMISSION_VIEW},
+					{DashboardId: 15, Role: &viewer, Permission: m.PERMISSION_VIEW},
+				}
+				query.ResultHasAcl = map[int64]bool{10: true, 15: true}
+				return nil
+			})
+
+			jsonDashIndex = nil
+			query.SignedInUser = &m.SignedInUser{UserId: 1, OrgId: 1, OrgRole: m.ROLE_VIEWER}
+			err := searchHandler(&query)
+			So(err, ShouldBeNil)
+
+			Convey("should only return dashboards the viewer can view", func() {
+				So(len(query.Result), ShouldEqual, 2)
+				So(query.Result[0].Title, ShouldEqual, "BBAA")
+				So(query.Result[1].Title, ShouldEqual, "CCAA")
+			})
+		})
+
+		Convey("That sorts by views", func() {
+			query.Sort = SortViews
+			err := searchHandler(&query)
+			So(err, ShouldBeNil)
+
+			Convey("should return most viewed first and same views by title", func() {
+				So(query.Result[0].Title, ShouldEqual, "BBAA")
+				So(query.Result[1].Title, ShouldEqual, "CCAA")
+				So(query.Result[2].Title, ShouldEqual, "AABB")
+			})
+		})
+
+		Convey("That sorts by updated", func() {
+			query.Sort = SortUpdated
+			err := searchHandler(&query)
+			So(err, ShouldBeNil)
+
+			Convey("should return last updated first", func() {
+				So(query.Result[0].Title, ShouldEqual, "AABB")
+				So(query.Result[1].Title, ShouldEqual, "CCAA")
+				So(query.Result[2].Title, ShouldEqual, "BBAA")
+			})
+		})
+
+		Convey("That has an invalid sort option", func() {
+			query.Sort = "stars"
+			err := searchHandler(&query)
+			So(err, ShouldEqual, ErrInvalidSort)
+		})
+
+		Convey("That requests a page", func() {
+			jsonDashIndex = nil
+			query.PerPage = 2
+			query.Page = 2
+			err := searchHandler(&query)
+			So(err, ShouldBeNil)
+
+			Convey("should return the hits of the page", func() {
+				So(len(query.Result), ShouldEqual, 1)
+				So(query.Result[0].Title, ShouldEqual, "CCAA")
+			})
+		})
+
+		Convey("That requests a page after the last hit", func() {
+			jsonDashIndex = nil
+			query.PerPage = 2
+			query.Page = 3
+			err := searchHandler(&query)
+			So(err, ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+		})
+
 		Convey("That filters by tag", func() {
 			query.Tags = []string{"BB", "AA"}
 			err := searchHandler(&query)
@@ -58,4 +135,24 @@ func TestSearch(t *testing.T) {
 
 		})
 	})
+
+	Convey("Given search results in folders", t, func() {
+		hits := HitList{
+			&Hit{Title: "revenue", FolderTitle: "finance"},
+			&Hit{Title: "finance", Type: DashHitFolder},
+			&Hit{Title: "web", Type: DashHitDB},
+			&Hit{Title: "costs", FolderTitle: "finance"},
+			&Hit{Title: "backend", Type: DashHitFolder},
+		}
+
+		Convey("should group dashboards after their folder", func() {
+			sort.Sort(hits)
+
+			So(hits[0].Title, ShouldEqual, "web")
+			So(hits[1].Title, ShouldEqual, "backend")
+			So(hits[2].Title, ShouldEqual, "finance")
+			So(hits[3].Title, ShouldEqual, "costs")
+			So(hits[4].Title, ShouldEqual, "revenue")
+		})
+	})
 }
@@ -1,5 +1,12 @@
 package search
 
+import (
+	"errors"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+)
+
 type HitType string
 
 const (
@@ -7,22 +14,59 @@ const (
 	DashHitHome     HitType = "dash-home"
 	DashHitJson     HitType = "dash-json"
 	DashHitScripted HitType = "dash-scripted"
+	DashHitFolder   HitType = "dash-folder"
 )
 
+// Sort options of search results. Results are sorted by relevance when
+// searching for text and by title otherwise.
+const (
+	SortTitle     = "title"
+	SortRelevance = "relevance"
+	SortUpdated   = "updated"
+	SortViews     = "views"
+)
+
+var ErrInvalidSort = errors.New("Invalid sort option, valid options are title, relevance, updated and views")
+
 type Hit struct {
-	Id        int64    `json:"id"`
-	Title     string   `json:"title"`
-	Uri       string   `json:"uri"`
-	Type      HitType  `json:"type"`
-	Tags      []string `json:"tags"`
-	IsStarred bool     `json:"isStarred"`
+	Id          int64    `json:"id"`
+	Uid         string   `json:"uid,omitempty"`
+	Title       string   `json:"title"`
+	Uri         string   `json:"uri"`
+	Type        HitType  `json:"type"`
+	Tags        []string `json:"tags"`
+	IsStarred   bool     `json:"isStarred"`
+	FolderId    int64    `json:"folderId"`
+	FolderTitle string   `json:"folderTitle,omitempty"`
+
+	Score   float64   `json:"-"`
+	Updated time.Time `json:"-"`
+	Views   int64     `json:"-"`
 }
 
 type HitList []*Hit
 
-func (s HitList) Len() int           { return len(s) }
-func (s HitList) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
-func (s HitList) Less(i, j int) bool { return s[i].Title < s[j].Title }
+func (s HitList) Len() int      { return len(s) }
+func (s HitList) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s HitList) Less(i, j int) bool {
+	// group dashboards under their folder, dashboards without a folder first
+	if groupI, groupJ := s[i].folderGroup(), s[j].folderGroup(); groupI != groupJ {
+		return groupI < groupJ
+	}
+
+	if isFolderI, isFolderJ := s[i].Type == DashHitFolder, s[j].Type == DashHitFolder; isFolderI != isFolderJ {
+		return isFolderI
+	}
+
+	return s[i].Title < s[j].Title
+}
+
+func (h *Hit) folderGroup() string {
+	if h.Type == DashHitFolder {
+		return h.Title
+	}
+	return h.FolderTitle
+}
 
 type Query struct {
 	Title        string
@@ -30,8 +74,15 @@ type Query struct {
 	OrgId        int64
 	UserId       int64
 	Limit        int
+	Page         int
+	PerPage      int
+	Sort         string
 	IsStarred    bool
 	DashboardIds []int
+	FolderIds    []int64
+	Datasources  []string
+	PanelTypes   []string
+	SignedInUser *m.SignedInUser
 
 	Result HitList
 }
@@ -42,6 +93,7 @@ type FindPersistedDashboardsQuery struct {
 	UserId       int64
 	IsStarred    bool
 	DashboardIds []int
+	FolderIds    []int64
 
 	Result HitList
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-xorm/xorm"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	m "github.com/grafana/grafana/pkg/models"
 )
 
@@ -21,6 +22,10 @@ func init() {
 	bus.AddHandler("sql", GetAlertStatesForDashboard)
 	bus.AddHandler("sql", PauseAlert)
 	bus.AddHandler("sql", PauseAllAlerts)
+	bus.AddHandler("sql", SetAlertAcknowledgement)
+	bus.AddHandler("sql", CreateAlert)
+	bus.AddHandler("sql", UpdateAlert)
+	bus.AddHandler("sql", SaveImportedAlertRules)
 }
 
 func GetAlertById(query *m.GetAlertByIdQuery) error {
@@ -59,6 +64,10 @@ func deleteAlertByIdInternal(alertId int64, reason string, sess *xorm.Session) e
 		return err
 	}
 
+	if _, err := sess.Exec("DELETE FROM alert_notification_delivery WHERE alert_id = ?", alertId); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -84,6 +93,11 @@ func HandleAlertsQuery(query *m.GetAlertsQuery) error {
 		params = append(params, query.DashboardId)
 	}
 
+	if query.FolderId != 0 {
+		sql.WriteString(` AND dashboard_id IN (SELECT id FROM dashboard WHERE folder_id = ?)`)
+		params = append(params, query.FolderId)
+	}
+
 	if query.PanelId != 0 {
 		sql.WriteString(` AND panel_id = ?`)
 		params = append(params, query.PanelId)
@@ -197,6 +211,132 @@ func upsertAlerts(existingAlerts []*m.Alert, cmd *m.SaveAlertsCommand, sess *xor
 	return nil
 }
 
+func CreateAlert(cmd *m.CreateAlertCommand) error {
+	return inTransaction(func(sess *xorm.Session) error {
+		return createAlertInternal(cmd, sess)
+	})
+}
+
+func createAlertInternal(cmd *m.CreateAlertCommand, sess *xorm.Session) error {
+	alert := cmd.Alert
+	alert.Id = 0
+	alert.DashboardId = 0
+	alert.PanelId = 0
+	alert.Updated = time.Now()
+	alert.Created = time.Now()
+	alert.State = m.AlertStatePending
+	alert.NewStateDate = time.Now()
+
+	if _, err := sess.Insert(alert); err != nil {
+		return err
+	}
+
+	sqlog.Debug("Standalone alert inserted", "name", alert.Name, "id", alert.Id)
+	cmd.Result = alert
+	return nil
+}
+
+func UpdateAlert(cmd *m.UpdateAlertCommand) error {
+	return inTransaction(func(sess *xorm.Session) error {
+		return updateAlertInternal(cmd, sess)
+	})
+}
+
+func updateAlertInternal(cmd *m.UpdateAlertCommand, sess *xorm.Session) error {
+	existing := m.Alert{}
+
+	if has, err := sess.Where("id = ? AND org_id = ?", cmd.Alert.Id, cmd.Alert.OrgId).Get(&existing); err != nil {
+		return err
+	} else if !has {
+		return m.ErrAlertNotFound
+	}
+
+	if !existing.IsStandalone() {
+		return m.ErrAlertManagedByDashboard
+	}
+
+	existing.Name = cmd.Alert.Name
+	existing.Message = cmd.Alert.Message
+	existing.Severity = cmd.Alert.Severity
+	existing.Frequency = cmd.Alert.Frequency
+	existing.Settings = cmd.Alert.Settings
+	existing.Updated = time.Now()
+
+	if _, err := sess.Id(existing.Id).Cols("name", "message", "severity", "frequency", "settings", "updated").Update(&existing); err != nil {
+		return err
+	}
+
+	sqlog.Debug("Standalone alert updated", "name", existing.Name, "id", existing.Id)
+	cmd.Result = &existing
+	return nil
+}
+
+func SaveImportedAlertRules(cmd *m.SaveImportedAlertRulesCommand) error {
+	return inTransaction(func(sess *xorm.Session) error {
+		for _, createCmd := range cmd.CreateNotifications {
+			if err := createAlertNotificationInternal(createCmd, sess); err != nil {
+				return err
+			}
+		}
+
+		for _, updateCmd := range cmd.UpdateNotifications {
+			if err := updateAlertNotificationInternal(updateCmd, sess); err != nil {
+				return err
+			}
+		}
+
+		for _, createCmd := range cmd.CreateAlerts {
+			if err := resolveAlertNotificationNames(createCmd.Alert, sess); err != nil {
+				return err
+			}
+			if err := createAlertInternal(createCmd, sess); err != nil {
+				return err
+			}
+		}
+
+		for _, updateCmd := range cmd.UpdateAlerts {
+			if err := resolveAlertNotificationNames(updateCmd.Alert, sess); err != nil {
+				return err
+			}
+			if err := updateAlertInternal(updateCmd, sess); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// resolveAlertNotificationNames sets the id of channels that an alert
+// still references by name because they are created in the same
+// transaction.
+func resolveAlertNotificationNames(alert *m.Alert, sess *xorm.Session) error {
+	for _, item := range alert.Settings.Get("notifications").MustArray() {
+		notification := simplejson.NewFromAny(item)
+		if _, err := notification.Get("id").Int64(); err == nil {
+			continue
+		}
+
+		name := notification.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+
+		query := &m.GetAlertNotificationsQuery{OrgId: alert.OrgId, Name: name}
+		if err := getAlertNotificationInternal(query, sess); err != nil {
+			return err
+		}
+
+		if query.Result == nil {
+			return fmt.Errorf("Could not find notification channel %s", name)
+		}
+
+		notification.Set("id", query.Result.Id)
+	}
+
+	return nil
+}
+
 func deleteMissingAlerts(alerts []*m.Alert, cmd *m.SaveAlertsCommand, sess *xorm.Session) error {
 	for _, missingAlert := range alerts {
 		missing := true
@@ -245,6 +385,7 @@ func SetAlertState(cmd *m.SetAlertStateCommand) error {
 			return m.ErrRequiresNewState
 		}
 
+		prevState := alert.State
 		alert.State = cmd.State
 		alert.StateChanges += 1
 		alert.NewStateDate = time.Now()
@@ -256,11 +397,79 @@ func SetAlertState(cmd *m.SetAlertStateCommand) error {
 			alert.ExecutionError = cmd.Error
 		}
 
-		sess.Id(alert.Id).Update(&alert)
+		// only update from the state that was read, another server evaluating
+		// the same rule may have changed it already
+		if affected, err := sess.Id(alert.Id).And("state = ?", prevState).Update(&alert); err != nil {
+			return err
+		} else if affected == 0 {
+			return m.ErrRequiresNewState
+		}
+
+		// acknowledgements only last until the alert recovers
+		if alert.Acknowledged && alert.State == m.AlertStateOK {
+			if err := clearAlertAcknowledgement(sess, &alert); err != nil {
+				return err
+			}
+		}
+
+		cmd.Result = &alert
 		return nil
 	})
 }
 
+func SetAlertAcknowledgement(cmd *m.SetAlertAcknowledgementCommand) error {
+	return inTransaction(func(sess *xorm.Session) error {
+		alert := m.Alert{}
+
+		if has, err := sess.Where("id = ? AND org_id = ?", cmd.AlertId, cmd.OrgId).Get(&alert); err != nil {
+			return err
+		} else if !has {
+			return m.ErrAlertNotFound
+		}
+
+		if !cmd.Acknowledged {
+			cmd.Result = &alert
+			if !alert.Acknowledged {
+				return nil
+			}
+
+			if err := clearAlertAcknowledgement(sess, &alert); err != nil {
+				return err
+			}
+
+			cmd.Changed = true
+			return nil
+		}
+
+		if alert.State != m.AlertStateAlerting {
+			return m.ErrCannotAcknowledgeAlert
+		}
+
+		alert.Acknowledged = true
+		alert.AckLogin = cmd.Login
+		alert.AckComment = cmd.Comment
+		alert.AckDate = time.Now()
+
+		if _, err := sess.Id(alert.Id).Cols("acknowledged", "ack_login", "ack_comment", "ack_date").Update(&alert); err != nil {
+			return err
+		}
+
+		cmd.Result = &alert
+		cmd.Changed = true
+		return nil
+	})
+}
+
+func clearAlertAcknowledgement(sess *xorm.Session, alert *m.Alert) error {
+	alert.Acknowledged = false
+	alert.AckLogin = ""
+	alert.AckComment = ""
+	alert.AckDate = time.Time{}
+
+	_, err := sess.Id(alert.Id).Cols("acknowledged", "ack_login", "ack_comment", "ack_date").Update(alert)
+	return err
+}
+
 func PauseAlert(cmd *m.PauseAlertCommand) error {
 	return inTransaction(func(sess *xorm.Session) error {
 		if len(cmd.AlertIds) == 0 {
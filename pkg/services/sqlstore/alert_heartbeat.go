@@ -0,0 +1,55 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/go-xorm/xorm"
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", HeartBeat)
+}
+
+func HeartBeat(cmd *m.HeartBeatCommand) error {
+	return inTransaction(func(sess *xorm.Session) error {
+		now := time.Now()
+		heartbeat := m.HeartBeat{}
+
+		has, err := sess.Table("alert_heartbeat").Where("server_id = ?", cmd.ServerId).Get(&heartbeat)
+		if err != nil {
+			return err
+		}
+
+		if has {
+			heartbeat.Updated = now
+			if _, err := sess.Table("alert_heartbeat").Id(heartbeat.Id).Cols("updated").Update(&heartbeat); err != nil {
+				return err
+			}
+		} else {
+			heartbeat = m.HeartBeat{ServerId: cmd.ServerId, Created: now, Updated: now}
+			if _, err := sess.Table("alert_heartbeat").Insert(&heartbeat); err != nil {
+				return err
+			}
+		}
+
+		if _, err := sess.Exec("DELETE FROM alert_heartbeat WHERE updated < ?", now.Add(-cmd.Timeout)); err != nil {
+			return err
+		}
+
+		servers := make([]*m.HeartBeat, 0)
+		if err := sess.Table("alert_heartbeat").Asc("id").Find(&servers); err != nil {
+			return err
+		}
+
+		cmd.Result = m.AlertingClusterInfo{ServerId: cmd.ServerId, ClusterSize: len(servers)}
+		for position, server := range servers {
+			if server.ServerId == cmd.ServerId {
+				cmd.Result.UptimePosition = position
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertHeartbeat(t *testing.T) {
+	Convey("Testing alerting heartbeats", t, func() {
+		InitTestDB(t)
+
+		heartbeat := func(serverId string) m.AlertingClusterInfo {
+			cmd := &m.HeartBeatCommand{ServerId: serverId, Timeout: time.Minute}
+			So(HeartBeat(cmd), ShouldBeNil)
+			return cmd.Result
+		}
+
+		Convey("Single server is alone in cluster", func() {
+			info := heartbeat("server-1")
+			So(info.ClusterSize, ShouldEqual, 1)
+			So(info.UptimePosition, ShouldEqual, 0)
+		})
+
+		Convey("Servers get positions in the order they joined", func() {
+			heartbeat("server-1")
+			heartbeat("server-2")
+
+			So(heartbeat("server-1"), ShouldResemble, m.AlertingClusterInfo{ServerId: "server-1", ClusterSize: 2, UptimePosition: 0})
+			So(heartbeat("server-2"), ShouldResemble, m.AlertingClusterInfo{ServerId: "server-2", ClusterSize: 2, UptimePosition: 1})
+
+			Convey("Servers without recent heartbeat are removed", func() {
+				_, err := x.Exec("UPDATE alert_heartbeat SET updated = ? WHERE server_id = ?", time.Now().Add(-time.Hour), "server-1")
+				So(err, ShouldBeNil)
+
+				So(heartbeat("server-2"), ShouldResemble, m.AlertingClusterInfo{ServerId: "server-2", ClusterSize: 1, UptimePosition: 0})
+			})
+		})
+	})
+}
@@ -29,6 +29,10 @@ func DeleteAlertNotification(cmd *m.DeleteAlertNotificationCommand) error {
 			return err
 		}
 
+		if _, err := sess.Exec("DELETE FROM alert_notification_delivery WHERE org_id = ? AND notifier_id = ?", cmd.OrgId, cmd.Id); err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
@@ -132,69 +136,77 @@ func getAlertNotificationInternal(query *m.GetAlertNotificationsQuery, sess *xor
 
 func CreateAlertNotificationCommand(cmd *m.CreateAlertNotificationCommand) error {
 	return inTransaction(func(sess *xorm.Session) error {
-		existingQuery := &m.GetAlertNotificationsQuery{OrgId: cmd.OrgId, Name: cmd.Name}
-		err := getAlertNotificationInternal(existingQuery, sess)
+		return createAlertNotificationInternal(cmd, sess)
+	})
+}
 
-		if err != nil {
-			return err
-		}
+func createAlertNotificationInternal(cmd *m.CreateAlertNotificationCommand, sess *xorm.Session) error {
+	existingQuery := &m.GetAlertNotificationsQuery{OrgId: cmd.OrgId, Name: cmd.Name}
+	err := getAlertNotificationInternal(existingQuery, sess)
 
-		if existingQuery.Result != nil {
-			return fmt.Errorf("Alert notification name %s already exists", cmd.Name)
-		}
+	if err != nil {
+		return err
+	}
 
-		alertNotification := &m.AlertNotification{
-			OrgId:     cmd.OrgId,
-			Name:      cmd.Name,
-			Type:      cmd.Type,
-			Settings:  cmd.Settings,
-			Created:   time.Now(),
-			Updated:   time.Now(),
-			IsDefault: cmd.IsDefault,
-		}
+	if existingQuery.Result != nil {
+		return fmt.Errorf("Alert notification name %s already exists", cmd.Name)
+	}
 
-		if _, err = sess.Insert(alertNotification); err != nil {
-			return err
-		}
+	alertNotification := &m.AlertNotification{
+		OrgId:     cmd.OrgId,
+		Name:      cmd.Name,
+		Type:      cmd.Type,
+		Settings:  cmd.Settings,
+		Created:   time.Now(),
+		Updated:   time.Now(),
+		IsDefault: cmd.IsDefault,
+	}
 
-		cmd.Result = alertNotification
-		return nil
-	})
+	if _, err = sess.Insert(alertNotification); err != nil {
+		return err
+	}
+
+	cmd.Result = alertNotification
+	return nil
 }
 
 func UpdateAlertNotification(cmd *m.UpdateAlertNotificationCommand) error {
-	return inTransaction(func(sess *xorm.Session) (err error) {
-		current := m.AlertNotification{}
+	return inTransaction(func(sess *xorm.Session) error {
+		return updateAlertNotificationInternal(cmd, sess)
+	})
+}
 
-		if _, err = sess.Id(cmd.Id).Get(&current); err != nil {
-			return err
-		}
+func updateAlertNotificationInternal(cmd *m.UpdateAlertNotificationCommand, sess *xorm.Session) (err error) {
+	current := m.AlertNotification{}
 
-		// check if name exists
-		sameNameQuery := &m.GetAlertNotificationsQuery{OrgId: cmd.OrgId, Name: cmd.Name}
-		if err := getAlertNotificationInternal(sameNameQuery, sess); err != nil {
-			return err
-		}
+	if _, err = sess.Id(cmd.Id).Get(&current); err != nil {
+		return err
+	}
 
-		if sameNameQuery.Result != nil && sameNameQuery.Result.Id != current.Id {
-			return fmt.Errorf("Alert notification name %s already exists", cmd.Name)
-		}
+	// check if name exists
+	sameNameQuery := &m.GetAlertNotificationsQuery{OrgId: cmd.OrgId, Name: cmd.Name}
+	if err := getAlertNotificationInternal(sameNameQuery, sess); err != nil {
+		return err
+	}
 
-		current.Updated = time.Now()
-		current.Settings = cmd.Settings
-		current.Name = cmd.Name
-		current.Type = cmd.Type
-		current.IsDefault = cmd.IsDefault
+	if sameNameQuery.Result != nil && sameNameQuery.Result.Id != current.Id {
+		return fmt.Errorf("Alert notification name %s already exists", cmd.Name)
+	}
 
-		sess.UseBool("is_default")
+	current.Updated = time.Now()
+	current.Settings = cmd.Settings
+	current.Name = cmd.Name
+	current.Type = cmd.Type
+	current.IsDefault = cmd.IsDefault
 
-		if affected, err := sess.Id(cmd.Id).Update(current); err != nil {
-			return err
-		} else if affected == 0 {
-			return fmt.Errorf("Could not find alert notification")
-		}
+	sess.UseBool("is_default")
 
-		cmd.Result = &current
-		return nil
-	})
+	if affected, err := sess.Id(cmd.Id).Update(current); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("Could not find alert notification")
+	}
+
+	cmd.Result = &current
+	return nil
 }
@@ -0,0 +1,156 @@
+package sqlstore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-xorm/xorm"
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", CreateAlertNotificationDelivery)
+	bus.AddHandler("sql", UpdateAlertNotificationDelivery)
+	bus.AddHandler("sql", GetPendingAlertNotificationDeliveries)
+	bus.AddHandler("sql", GetAlertNotificationDeliveries)
+	bus.AddHandler("sql", DeleteOldAlertNotificationDeliveries)
+}
+
+func CreateAlertNotificationDelivery(cmd *m.CreateAlertNotificationDeliveryCommand) error {
+	if cmd.DedupKey != "" && alertNotificationDeliveryExists(cmd) {
+		return m.ErrNotificationAlreadySent
+	}
+
+	err := inTransaction(func(sess *xorm.Session) error {
+		delivery := &m.AlertNotificationDelivery{
+			OrgId:       cmd.OrgId,
+			AlertId:     cmd.AlertId,
+			NotifierId:  cmd.NotifierId,
+			State:       cmd.State,
+			Status:      m.DeliveryStatusPending,
+			Payload:     cmd.Payload,
+			NextAttempt: cmd.NextAttempt,
+			DedupKey:    cmd.DedupKey,
+			Created:     time.Now(),
+			Updated:     time.Now(),
+		}
+
+		// leave the key null so deliveries without one never conflict
+		if cmd.DedupKey == "" {
+			sess.Omit("dedup_key")
+		}
+
+		if _, err := sess.Insert(delivery); err != nil {
+			return err
+		}
+
+		// a stale state change must not be retried after a newer one
+		superseded := &m.AlertNotificationDelivery{Status: m.DeliveryStatusSuperseded, Updated: time.Now()}
+		_, err := sess.Where("alert_id = ? AND notifier_id = ? AND status = ? AND id != ?", cmd.AlertId, cmd.NotifierId, m.DeliveryStatusPending, delivery.Id).
+			Cols("status", "updated").
+			Update(superseded)
+		if err != nil {
+			return err
+		}
+
+		cmd.Result = delivery
+		return nil
+	})
+
+	// another server created the delivery between the check and the insert
+	if err != nil && cmd.DedupKey != "" && alertNotificationDeliveryExists(cmd) {
+		return m.ErrNotificationAlreadySent
+	}
+
+	return err
+}
+
+func alertNotificationDeliveryExists(cmd *m.CreateAlertNotificationDeliveryCommand) bool {
+	count, err := x.Where("notifier_id = ? AND dedup_key = ?", cmd.NotifierId, cmd.DedupKey).Count(&m.AlertNotificationDelivery{})
+	return err == nil && count > 0
+}
+
+func UpdateAlertNotificationDelivery(cmd *m.UpdateAlertNotificationDeliveryCommand) error {
+	return inTransaction(func(sess *xorm.Session) error {
+		// an attempt that was in flight when the delivery was superseded
+		// must not schedule it again
+		if cmd.Status == m.DeliveryStatusPending {
+			current := m.AlertNotificationDelivery{}
+			if has, err := sess.Id(cmd.Id).Get(&current); err != nil {
+				return err
+			} else if has && current.Status == m.DeliveryStatusSuperseded {
+				return nil
+			}
+		}
+
+		delivery := &m.AlertNotificationDelivery{
+			Status:      cmd.Status,
+			Attempts:    cmd.Attempts,
+			LastError:   cmd.LastError,
+			NextAttempt: cmd.NextAttempt,
+			Updated:     time.Now(),
+		}
+
+		affected, err := sess.Id(cmd.Id).Cols("status", "attempts", "last_error", "next_attempt", "updated").Update(delivery)
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return fmt.Errorf("Could not find alert notification delivery")
+		}
+
+		return nil
+	})
+}
+
+func DeleteOldAlertNotificationDeliveries(cmd *m.DeleteOldAlertNotificationDeliveriesCommand) error {
+	return inTransaction(func(sess *xorm.Session) error {
+		res, err := sess.Exec("DELETE FROM alert_notification_delivery WHERE status != ? AND updated < ?", m.DeliveryStatusPending, cmd.OlderThan)
+		if err != nil {
+			return err
+		}
+
+		cmd.DeletedRows, _ = res.RowsAffected()
+		return nil
+	})
+}
+
+func GetPendingAlertNotificationDeliveries(query *m.GetPendingAlertNotificationDeliveriesQuery) error {
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+
+	results := make([]*m.AlertNotificationDelivery, 0)
+	sess := x.Limit(query.Limit, 0).Where("status = ? AND next_attempt <= ?", m.DeliveryStatusPending, query.Before).Asc("next_attempt")
+	if err := sess.Find(&results); err != nil {
+		return err
+	}
+
+	query.Result = results
+	return nil
+}
+
+func GetAlertNotificationDeliveries(query *m.GetAlertNotificationDeliveriesQuery) error {
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+
+	sess := x.Limit(query.Limit, 0).Where("org_id = ?", query.OrgId)
+	if query.NotifierId != 0 {
+		sess.And("notifier_id = ?", query.NotifierId)
+	}
+
+	if query.AlertId != 0 {
+		sess.And("alert_id = ?", query.AlertId)
+	}
+
+	results := make([]*m.AlertNotificationDelivery, 0)
+	if err := sess.Desc("id").Find(&results); err != nil {
+		return err
+	}
+
+	query.Result = results
+	return nil
+}
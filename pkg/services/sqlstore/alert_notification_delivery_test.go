@@ -0,0 +1,138 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertNotificationDeliverySQLAccess(t *testing.T) {
+	Convey("Testing Alert notification delivery sql access", t, func() {
+		InitTestDB(t)
+
+		cmd := &m.CreateAlertNotificationDeliveryCommand{
+			OrgId:       1,
+			AlertId:     2,
+			NotifierId:  3,
+			State:       m.AlertStateAlerting,
+			Payload:     simplejson.NewFromAny(map[string]interface{}{"prevState": "ok"}),
+			NextAttempt: time.Now().Add(-time.Minute),
+		}
+
+		err := CreateAlertNotificationDelivery(cmd)
+		So(err, ShouldBeNil)
+		So(cmd.Result.Id, ShouldNotEqual, 0)
+		So(cmd.Result.Status, ShouldEqual, m.DeliveryStatusPending)
+
+		Convey("Can find pending deliveries", func() {
+			query := &m.GetPendingAlertNotificationDeliveriesQuery{Before: time.Now()}
+			err := GetPendingAlertNotificationDeliveries(query)
+			So(err, ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.Result[0].Payload.Get("prevState").MustString(), ShouldEqual, "ok")
+		})
+
+		Convey("Can update delivery status", func() {
+			err := UpdateAlertNotificationDelivery(&m.UpdateAlertNotificationDeliveryCommand{
+				Id:          cmd.Result.Id,
+				Status:      m.DeliveryStatusPending,
+				Attempts:    1,
+				LastError:   "connection refused",
+				NextAttempt: time.Now().Add(time.Hour),
+			})
+			So(err, ShouldBeNil)
+
+			pending := &m.GetPendingAlertNotificationDeliveriesQuery{Before: time.Now()}
+			So(GetPendingAlertNotificationDeliveries(pending), ShouldBeNil)
+			So(len(pending.Result), ShouldEqual, 0)
+
+			query := &m.GetAlertNotificationDeliveriesQuery{OrgId: 1, NotifierId: 3}
+			So(GetAlertNotificationDeliveries(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.Result[0].Attempts, ShouldEqual, 1)
+			So(query.Result[0].LastError, ShouldEqual, "connection refused")
+		})
+
+		Convey("Deliveries are scoped by org and notifier", func() {
+			query := &m.GetAlertNotificationDeliveriesQuery{OrgId: 2, NotifierId: 3}
+			So(GetAlertNotificationDeliveries(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+
+			query = &m.GetAlertNotificationDeliveriesQuery{OrgId: 1, NotifierId: 4}
+			So(GetAlertNotificationDeliveries(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+		})
+
+		Convey("Deliveries with same dedup key are only created once", func() {
+			dedupCmd := &m.CreateAlertNotificationDeliveryCommand{OrgId: 1, AlertId: 2, NotifierId: 3, DedupKey: "2-1-alerting"}
+			So(CreateAlertNotificationDelivery(dedupCmd), ShouldBeNil)
+			So(CreateAlertNotificationDelivery(dedupCmd), ShouldEqual, m.ErrNotificationAlreadySent)
+
+			otherNotifier := &m.CreateAlertNotificationDeliveryCommand{OrgId: 1, AlertId: 2, NotifierId: 4, DedupKey: "2-1-alerting"}
+			So(CreateAlertNotificationDelivery(otherNotifier), ShouldBeNil)
+
+			withoutKey := &m.CreateAlertNotificationDeliveryCommand{OrgId: 1, AlertId: 2, NotifierId: 3}
+			So(CreateAlertNotificationDelivery(withoutKey), ShouldBeNil)
+		})
+
+		Convey("A newer delivery supersedes pending deliveries of the alert", func() {
+			newer := &m.CreateAlertNotificationDeliveryCommand{OrgId: 1, AlertId: 2, NotifierId: 3, State: m.AlertStateOK, NextAttempt: time.Now().Add(-time.Minute)}
+			So(CreateAlertNotificationDelivery(newer), ShouldBeNil)
+
+			otherNotifier := &m.CreateAlertNotificationDeliveryCommand{OrgId: 1, AlertId: 2, NotifierId: 4, NextAttempt: time.Now().Add(-time.Minute)}
+			So(CreateAlertNotificationDelivery(otherNotifier), ShouldBeNil)
+
+			pending := &m.GetPendingAlertNotificationDeliveriesQuery{Before: time.Now()}
+			So(GetPendingAlertNotificationDeliveries(pending), ShouldBeNil)
+			So(len(pending.Result), ShouldEqual, 2)
+			So(pending.Result[0].Id, ShouldNotEqual, cmd.Result.Id)
+			So(pending.Result[1].Id, ShouldNotEqual, cmd.Result.Id)
+
+			Convey("Should not retry a superseded delivery", func() {
+				err := UpdateAlertNotificationDelivery(&m.UpdateAlertNotificationDeliveryCommand{
+					Id:          cmd.Result.Id,
+					Status:      m.DeliveryStatusPending,
+					Attempts:    1,
+					NextAttempt: time.Now().Add(-time.Minute),
+				})
+				So(err, ShouldBeNil)
+
+				query := &m.GetAlertNotificationDeliveriesQuery{OrgId: 1, NotifierId: 3}
+				So(GetAlertNotificationDeliveries(query), ShouldBeNil)
+				So(query.Result[1].Id, ShouldEqual, cmd.Result.Id)
+				So(query.Result[1].Status, ShouldEqual, m.DeliveryStatusSuperseded)
+			})
+		})
+
+		Convey("Can delete old deliveries that are no longer pending", func() {
+			delivered := &m.CreateAlertNotificationDeliveryCommand{OrgId: 1, AlertId: 5, NotifierId: 3}
+			So(CreateAlertNotificationDelivery(delivered), ShouldBeNil)
+			So(UpdateAlertNotificationDelivery(&m.UpdateAlertNotificationDeliveryCommand{Id: delivered.Result.Id, Status: m.DeliveryStatusDelivered, Attempts: 1}), ShouldBeNil)
+
+			deleteCmd := &m.DeleteOldAlertNotificationDeliveriesCommand{OlderThan: time.Now().Add(-time.Hour)}
+			So(DeleteOldAlertNotificationDeliveries(deleteCmd), ShouldBeNil)
+			So(deleteCmd.DeletedRows, ShouldEqual, 0)
+
+			deleteCmd = &m.DeleteOldAlertNotificationDeliveriesCommand{OlderThan: time.Now().Add(time.Hour)}
+			So(DeleteOldAlertNotificationDeliveries(deleteCmd), ShouldBeNil)
+			So(deleteCmd.DeletedRows, ShouldEqual, 1)
+
+			query := &m.GetAlertNotificationDeliveriesQuery{OrgId: 1, NotifierId: 3}
+			So(GetAlertNotificationDeliveries(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.Result[0].Status, ShouldEqual, m.DeliveryStatusPending)
+		})
+
+		Convey("Deleting the notification removes its deliveries", func() {
+			err := DeleteAlertNotification(&m.DeleteAlertNotificationCommand{OrgId: 1, Id: 3})
+			So(err, ShouldBeNil)
+
+			query := &m.GetAlertNotificationDeliveriesQuery{OrgId: 1, NotifierId: 3}
+			So(GetAlertNotificationDeliveries(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+		})
+	})
+}
@@ -70,6 +70,172 @@ func TestAlertingDataAccess(t *testing.T) {
 			})
 		})
 
+		Convey("Can acknowledge alerts", func() {
+			ackCmd := &m.SetAlertAcknowledgementCommand{
+				OrgId:        1,
+				AlertId:      1,
+				Acknowledged: true,
+				Login:        "admin",
+				Comment:      "on it",
+			}
+
+			Convey("cannot acknowledge alert that is not firing", func() {
+				err = SetAlertAcknowledgement(ackCmd)
+				So(err, ShouldEqual, m.ErrCannotAcknowledgeAlert)
+			})
+
+			Convey("removing missing acknowledgement changes nothing", func() {
+				unackCmd := &m.SetAlertAcknowledgementCommand{OrgId: 1, AlertId: 1}
+				So(SetAlertAcknowledgement(unackCmd), ShouldBeNil)
+				So(unackCmd.Changed, ShouldBeFalse)
+			})
+
+			Convey("cannot acknowledge alert in other org", func() {
+				ackCmd.OrgId = 2
+				err = SetAlertAcknowledgement(ackCmd)
+				So(err, ShouldEqual, m.ErrAlertNotFound)
+			})
+
+			Convey("firing alert", func() {
+				err = SetAlertState(&m.SetAlertStateCommand{AlertId: 1, State: m.AlertStateAlerting})
+				So(err, ShouldBeNil)
+
+				err = SetAlertAcknowledgement(ackCmd)
+				So(err, ShouldBeNil)
+				So(ackCmd.Changed, ShouldBeTrue)
+
+				query := &m.GetAlertByIdQuery{Id: 1}
+				So(GetAlertById(query), ShouldBeNil)
+				So(query.Result.Acknowledged, ShouldBeTrue)
+				So(query.Result.AckLogin, ShouldEqual, "admin")
+				So(query.Result.AckComment, ShouldEqual, "on it")
+
+				Convey("acknowledgement can be removed", func() {
+					unackCmd := &m.SetAlertAcknowledgementCommand{OrgId: 1, AlertId: 1}
+					err = SetAlertAcknowledgement(unackCmd)
+					So(err, ShouldBeNil)
+					So(unackCmd.Changed, ShouldBeTrue)
+
+					So(GetAlertById(query), ShouldBeNil)
+					So(query.Result.Acknowledged, ShouldBeFalse)
+					So(query.Result.AckLogin, ShouldEqual, "")
+				})
+
+				Convey("acknowledgement is kept while alert is not ok", func() {
+					stateCmd := &m.SetAlertStateCommand{AlertId: 1, State: m.AlertStateNoData}
+					So(SetAlertState(stateCmd), ShouldBeNil)
+					So(stateCmd.Result.Acknowledged, ShouldBeTrue)
+				})
+
+				Convey("acknowledgement is cleared when alert returns to ok", func() {
+					So(SetAlertState(&m.SetAlertStateCommand{AlertId: 1, State: m.AlertStateOK}), ShouldBeNil)
+
+					So(GetAlertById(query), ShouldBeNil)
+					So(query.Result.Acknowledged, ShouldBeFalse)
+					So(query.Result.AckComment, ShouldEqual, "")
+				})
+			})
+		})
+
+		Convey("Can manage standalone alerts", func() {
+			createCmd := &m.CreateAlertCommand{
+				Alert: &m.Alert{
+					OrgId:     1,
+					Name:      "standalone",
+					Message:   "cpu is high",
+					Severity:  "critical",
+					Frequency: 60,
+					Settings:  simplejson.New(),
+				},
+			}
+
+			err = CreateAlert(createCmd)
+			So(err, ShouldBeNil)
+			So(createCmd.Result.Id, ShouldNotEqual, 0)
+			So(createCmd.Result.DashboardId, ShouldEqual, 0)
+			So(createCmd.Result.State, ShouldEqual, m.AlertStatePending)
+
+			Convey("can update standalone alert", func() {
+				updateCmd := &m.UpdateAlertCommand{
+					Alert: &m.Alert{
+						Id:        createCmd.Result.Id,
+						OrgId:     1,
+						Name:      "renamed",
+						Message:   "",
+						Frequency: 10,
+						Settings:  simplejson.New(),
+					},
+				}
+
+				err = UpdateAlert(updateCmd)
+				So(err, ShouldBeNil)
+
+				query := &m.GetAlertByIdQuery{Id: createCmd.Result.Id}
+				So(GetAlertById(query), ShouldBeNil)
+				So(query.Result.Name, ShouldEqual, "renamed")
+				So(query.Result.Message, ShouldEqual, "")
+				So(query.Result.Frequency, ShouldEqual, 10)
+				So(query.Result.State, ShouldEqual, m.AlertStatePending)
+			})
+
+			Convey("cannot update standalone alert in other org", func() {
+				err = UpdateAlert(&m.UpdateAlertCommand{Alert: &m.Alert{Id: createCmd.Result.Id, OrgId: 2}})
+				So(err, ShouldEqual, m.ErrAlertNotFound)
+			})
+
+			Convey("cannot update dashboard alert", func() {
+				err = UpdateAlert(&m.UpdateAlertCommand{Alert: &m.Alert{Id: 1, OrgId: 1, Name: "changed"}})
+				So(err, ShouldEqual, m.ErrAlertManagedByDashboard)
+			})
+
+			Convey("saving dashboard alerts keeps standalone alerts", func() {
+				err = SaveAlerts(&m.SaveAlertsCommand{DashboardId: testDash.Id, OrgId: 1})
+				So(err, ShouldBeNil)
+
+				query := &m.GetAlertByIdQuery{Id: createCmd.Result.Id}
+				So(GetAlertById(query), ShouldBeNil)
+				So(query.Result.IsStandalone(), ShouldBeTrue)
+			})
+
+			Convey("can save imported channels and alerts together", func() {
+				settings, _ := simplejson.NewJson([]byte(`{"notifications": [{"name": "imported"}]}`))
+				saveCmd := &m.SaveImportedAlertRulesCommand{
+					CreateNotifications: []*m.CreateAlertNotificationCommand{
+						{OrgId: 1, Name: "imported", Type: "email", Settings: simplejson.New()},
+					},
+					CreateAlerts: []*m.CreateAlertCommand{
+						{Alert: &m.Alert{OrgId: 1, Name: "imported alert", Frequency: 60, Settings: settings}},
+					},
+				}
+
+				err = SaveImportedAlertRules(saveCmd)
+				So(err, ShouldBeNil)
+
+				channelId := saveCmd.CreateNotifications[0].Result.Id
+				query := &m.GetAlertByIdQuery{Id: saveCmd.CreateAlerts[0].Result.Id}
+				So(GetAlertById(query), ShouldBeNil)
+				So(query.Result.Settings.Get("notifications").GetIndex(0).Get("id").MustInt64(), ShouldEqual, channelId)
+			})
+
+			Convey("saving imported rules is rolled back on failure", func() {
+				saveCmd := &m.SaveImportedAlertRulesCommand{
+					CreateNotifications: []*m.CreateAlertNotificationCommand{
+						{OrgId: 1, Name: "rolled back", Type: "email", Settings: simplejson.New()},
+					},
+					UpdateAlerts: []*m.UpdateAlertCommand{
+						{Alert: &m.Alert{Id: 1, OrgId: 1, Name: "changed", Settings: simplejson.New()}},
+					},
+				}
+
+				err = SaveImportedAlertRules(saveCmd)
+				So(err, ShouldEqual, m.ErrAlertManagedByDashboard)
+
+				channelQuery := &m.GetAlertNotificationsQuery{OrgId: 1, Name: "rolled back"}
+				So(GetAlertNotifications(channelQuery), ShouldBeNil)
+				So(channelQuery.Result, ShouldBeNil)
+			})
+		})
+
 		Convey("Can read properties", func() {
 			alertQuery := m.GetAlertsQuery{DashboardId: testDash.Id, PanelId: 1, OrgId: 1}
 			err2 := HandleAlertsQuery(&alertQuery)
@@ -2,112 +2,276 @@ package sqlstore
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-xorm/xorm"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/dashschema"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/metrics"
 	m "github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func init() {
 	bus.AddHandler("sql", SaveDashboard)
 	bus.AddHandler("sql", GetDashboard)
+	bus.AddHandler("sql", GetAllDashboards)
+	bus.AddHandler("sql", GetDashboardsState)
 	bus.AddHandler("sql", GetDashboards)
 	bus.AddHandler("sql", DeleteDashboard)
 	bus.AddHandler("sql", SearchDashboards)
 	bus.AddHandler("sql", GetDashboardTags)
 	bus.AddHandler("sql", GetDashboardSlugById)
+	bus.AddHandler("sql", GetDashboardRefById)
 	bus.AddHandler("sql", GetDashboardsByPluginId)
+	bus.AddHandler("sql", MoveDashboard)
+	bus.AddHandler("sql", GetFolders)
 }
 
 func SaveDashboard(cmd *m.SaveDashboardCommand) error {
-	return inTransaction(func(sess *xorm.Session) error {
-		dash := cmd.GetDashboardModel()
+	return inTransaction2(func(sess *session) error {
+		return saveDashboard(sess, cmd)
+	})
+}
 
-		// try get existing dashboard
-		var existing, sameTitle m.Dashboard
+func saveDashboard(sess *session, cmd *m.SaveDashboardCommand) error {
+	dash := cmd.GetDashboardModel()
 
-		if dash.Id > 0 {
-			dashWithIdExists, err := sess.Where("id=? AND org_id=?", dash.Id, dash.OrgId).Get(&existing)
-			if err != nil {
-				return err
-			}
-			if !dashWithIdExists {
-				return m.ErrDashboardNotFound
-			}
+	// try get existing dashboard
+	var existing, sameTitle m.Dashboard
 
-			// check for is someone else has written in between
-			if dash.Version != existing.Version {
-				if cmd.Overwrite {
-					dash.Version = existing.Version
-				} else {
-					return m.ErrDashboardVersionMismatch
-				}
-			}
+	if dash.Id > 0 {
+		dashWithIdExists, err := sess.Where("id=? AND org_id=?", dash.Id, dash.OrgId).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !dashWithIdExists {
+			return m.ErrDashboardNotFound
+		}
 
-			// do not allow plugin dashboard updates without overwrite flag
-			if existing.PluginId != "" && cmd.Overwrite == false {
-				return m.UpdatePluginDashboardError{PluginId: existing.PluginId}
+		// check for is someone else has written in between
+		if dash.Version != existing.Version {
+			if cmd.Overwrite {
+				dash.Version = existing.Version
+			} else {
+				return m.ErrDashboardVersionMismatch
 			}
 		}
 
-		sameTitleExists, err := sess.Where("org_id=? AND slug=?", dash.OrgId, dash.Slug).Get(&sameTitle)
-		if err != nil {
+		// do not allow plugin dashboard updates without overwrite flag
+		if existing.PluginId != "" && cmd.Overwrite == false {
+			return m.UpdatePluginDashboardError{PluginId: existing.PluginId}
+		}
+
+		if existing.IsFolder != dash.IsFolder {
+			return m.ErrDashboardTypeMismatch
+		}
+	}
+
+	if dash.FolderId > 0 {
+		if dash.IsFolder {
+			return m.ErrDashboardFolderNesting
+		}
+
+		if err := validateFolder(sess.Session, dash.OrgId, dash.FolderId); err != nil {
 			return err
 		}
+	}
 
-		if sameTitleExists {
-			// another dashboard with same name
-			if dash.Id != sameTitle.Id {
-				if cmd.Overwrite {
-					dash.Id = sameTitle.Id
-				} else {
-					return m.ErrDashboardWithSameNameExists
-				}
+	sameUidExists, err := resolveDashboardUid(sess.Session, dash, cmd.Overwrite)
+	if err != nil {
+		return err
+	}
+
+	sameTitleExists, err := sess.Where("org_id=? AND slug=?", dash.OrgId, dash.Slug).Get(&sameTitle)
+	if err != nil {
+		return err
+	}
+
+	if sameTitleExists {
+		// another dashboard with same name, a dashboard found by its uid
+		// is never swapped for the one with the same name
+		if dash.Id != sameTitle.Id {
+			if cmd.Overwrite && sameTitle.IsFolder == dash.IsFolder && !sameUidExists {
+				dash.Id = sameTitle.Id
+				dash.Version = sameTitle.Version
+			} else {
+				return m.ErrDashboardWithSameNameExists
 			}
 		}
+	}
+
+	if err := setDashboardUid(sess.Session, dash); err != nil {
+		return err
+	}
 
-		affectedRows := int64(0)
+	parentVersion := dash.Version
+	affectedRows := int64(0)
+
+	if dash.Id == 0 {
+		dash.Version = 1
+		dash.Data.Set("version", dash.Version)
+		metrics.M_Models_Dashboard_Insert.Inc(1)
+		affectedRows, err = sess.Insert(dash)
+	} else {
+		dash.Version += 1
+		dash.Data.Set("version", dash.Version)
+		affectedRows, err = sess.Id(dash.Id).MustCols("folder_id").Update(dash)
+	}
+
+	if err != nil {
+		return err
+	}
 
-		if dash.Id == 0 {
-			metrics.M_Models_Dashboard_Insert.Inc(1)
-			affectedRows, err = sess.Insert(dash)
-		} else {
-			dash.Version += 1
-			dash.Data.Set("version", dash.Version)
-			affectedRows, err = sess.Id(dash.Id).Update(dash)
+	if affectedRows == 0 {
+		return m.ErrDashboardNotFound
+	}
+
+	dashVersion := &m.DashboardVersion{
+		DashboardId:   dash.Id,
+		ParentVersion: parentVersion,
+		RestoredFrom:  cmd.RestoredFrom,
+		Version:       dash.Version,
+		Created:       time.Now(),
+		CreatedBy:     dash.UpdatedBy,
+		Message:       cmd.Message,
+		Data:          dash.Data,
+	}
+
+	// insert version entry
+	if affectedRows, err = sess.Insert(dashVersion); err != nil {
+		return err
+	} else if affectedRows == 0 {
+		return m.ErrDashboardNotFound
+	}
+
+	// delete existing tabs
+	_, err = sess.Exec("DELETE FROM dashboard_tag WHERE dashboard_id=?", dash.Id)
+	if err != nil {
+		return err
+	}
+
+	// insert new tags
+	tags := dash.GetTags()
+	if len(tags) > 0 {
+		for _, tag := range tags {
+			if _, err := sess.Insert(&DashboardTag{DashboardId: dash.Id, Term: tag}); err != nil {
+				return err
+			}
 		}
+	}
 
-		if affectedRows == 0 {
-			return m.ErrDashboardNotFound
+	cmd.Result = dash
+
+	sess.publishAfterCommit(&events.DashboardSaved{
+		Timestamp: time.Now(),
+		Id:        dash.Id,
+		Uid:       dash.Uid,
+		OrgId:     dash.OrgId,
+		Title:     dash.Title,
+	})
+
+	return err
+}
+
+// resolveDashboardUid validates the uid of a dashboard that is saved and
+// checks it against the existing dashboards. Uids are unique across orgs.
+// With overwrite a new dashboard takes the place of the dashboard with the
+// same uid. Returns true if a dashboard with the uid exists.
+func resolveDashboardUid(sess *xorm.Session, dash *m.Dashboard, overwrite bool) (bool, error) {
+	if dash.Uid == "" {
+		return false, nil
+	}
+
+	if len(dash.Uid) > util.MaxShortUidSize {
+		return false, m.ErrDashboardUidToLong
+	}
+
+	if !util.IsValidShortUid(dash.Uid) {
+		return false, m.ErrDashboardInvalidUid
+	}
+
+	var sameUid m.Dashboard
+	exists, err := sess.Where("uid=?", dash.Uid).Get(&sameUid)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	if sameUid.OrgId != dash.OrgId {
+		return true, m.ErrDashboardUidInOtherOrg
+	}
+
+	if dash.Id == sameUid.Id {
+		return true, nil
+	}
+
+	if dash.Id == 0 && overwrite && sameUid.IsFolder == dash.IsFolder {
+		dash.Id = sameUid.Id
+		dash.Version = sameUid.Version
+		return true, nil
+	}
+
+	return true, m.ErrDashboardWithSameUIDExists
+}
+
+// setDashboardUid keeps the uid of an existing dashboard that is saved
+// without one and generates a uid for new dashboards.
+func setDashboardUid(sess *xorm.Session, dash *m.Dashboard) error {
+	if dash.Uid == "" && dash.Id > 0 {
+		var existing m.Dashboard
+		if _, err := sess.Where("id=?", dash.Id).Cols("uid").Get(&existing); err != nil {
+			return err
 		}
+		dash.Uid = existing.Uid
+	}
 
-		// delete existing tabs
-		_, err = sess.Exec("DELETE FROM dashboard_tag WHERE dashboard_id=?", dash.Id)
+	for i := 0; dash.Uid == "" && i < 3; i++ {
+		uid := util.GenerateShortUid()
+		exists, err := sess.Where("uid=?", uid).Get(&m.Dashboard{})
 		if err != nil {
 			return err
 		}
 
-		// insert new tags
-		tags := dash.GetTags()
-		if len(tags) > 0 {
-			for _, tag := range tags {
-				if _, err := sess.Insert(&DashboardTag{DashboardId: dash.Id, Term: tag}); err != nil {
-					return err
-				}
-			}
+		if !exists {
+			dash.Uid = uid
 		}
+	}
+
+	if dash.Uid == "" {
+		return errors.New("Failed to generate unique dashboard uid")
+	}
+
+	dash.Data.Set("uid", dash.Uid)
+	return nil
+}
 
-		cmd.Result = dash
+func GetAllDashboards(query *m.GetAllDashboardsQuery) error {
+	query.Result = make([]*m.Dashboard, 0)
+	return x.Find(&query.Result)
+}
 
+func GetDashboardsState(query *m.GetDashboardsStateQuery) error {
+	count, err := x.Count(&m.Dashboard{})
+	if err != nil {
 		return err
-	})
+	}
+
+	var last m.Dashboard
+	if _, err := x.Desc("updated").Limit(1).Cols("updated").Get(&last); err != nil {
+		return err
+	}
+
+	query.Result = &m.DashboardsState{Count: count, LastUpdated: last.Updated}
+	return nil
 }
 
 func GetDashboard(query *m.GetDashboardQuery) error {
-	dashboard := m.Dashboard{Slug: query.Slug, OrgId: query.OrgId}
+	dashboard := m.Dashboard{Slug: query.Slug, OrgId: query.OrgId, Id: query.Id, Uid: query.Uid}
 	has, err := x.Get(&dashboard)
 	if err != nil {
 		return err
@@ -115,17 +279,23 @@ func GetDashboard(query *m.GetDashboardQuery) error {
 		return m.ErrDashboardNotFound
 	}
 
+	dashschema.Migrate(dashboard.Data)
 	dashboard.Data.Set("id", dashboard.Id)
+	dashboard.Data.Set("uid", dashboard.Uid)
 	query.Result = &dashboard
 
 	return nil
 }
 
 type DashboardSearchProjection struct {
-	Id    int64
-	Title string
-	Slug  string
-	Term  string
+	Id          int64
+	Uid         string
+	Title       string
+	Slug        string
+	Term        string
+	IsFolder    bool
+	FolderId    int64
+	FolderTitle string
 }
 
 func SearchDashboards(query *search.FindPersistedDashboardsQuery) error {
@@ -134,10 +304,15 @@ func SearchDashboards(query *search.FindPersistedDashboardsQuery) error {
 
 	sql.WriteString(`SELECT
 					  dashboard.id,
+					  dashboard.uid,
 					  dashboard.title,
 					  dashboard.slug,
-					  dashboard_tag.term
+					  dashboard_tag.term,
+					  dashboard.is_folder,
+					  dashboard.folder_id,
+					  folder.title as folder_title
 					FROM dashboard
+					LEFT OUTER JOIN dashboard folder on folder.id = dashboard.folder_id
 					LEFT OUTER JOIN dashboard_tag on dashboard_tag.dashboard_id = dashboard.id`)
 
 	if query.IsStarred {
@@ -166,6 +341,13 @@ func SearchDashboards(query *search.FindPersistedDashboardsQuery) error {
 		sql.WriteString(")")
 	}
 
+	if len(query.FolderIds) > 0 {
+		sql.WriteString(" AND dashboard.folder_id IN (?" + strings.Repeat(",?", len(query.FolderIds)-1) + ")")
+		for _, folderId := range query.FolderIds {
+			params = append(params, folderId)
+		}
+	}
+
 	if len(query.Title) > 0 {
 		sql.WriteString(" AND dashboard.title " + dialect.LikeStr() + " ?")
 		params = append(params, "%"+query.Title+"%")
@@ -187,11 +369,17 @@ func SearchDashboards(query *search.FindPersistedDashboardsQuery) error {
 		hit, exists := hits[item.Id]
 		if !exists {
 			hit = &search.Hit{
-				Id:    item.Id,
-				Title: item.Title,
-				Uri:   "db/" + item.Slug,
-				Type:  search.DashHitDB,
-				Tags:  []string{},
+				Id:          item.Id,
+				Uid:         item.Uid,
+				Title:       item.Title,
+				Uri:         "db/" + item.Slug,
+				Type:        search.DashHitDB,
+				Tags:        []string{},
+				FolderId:    item.FolderId,
+				FolderTitle: item.FolderTitle,
+			}
+			if item.IsFolder {
+				hit.Type = search.DashHitFolder
 			}
 			query.Result = append(query.Result, hit)
 			hits[item.Id] = hit
@@ -229,28 +417,113 @@ func DeleteDashboard(cmd *m.DeleteDashboardCommand) error {
 			return m.ErrDashboardNotFound
 		}
 
-		deletes := []string{
-			"DELETE FROM dashboard_tag WHERE dashboard_id = ? ",
-			"DELETE FROM star WHERE dashboard_id = ? ",
-			"DELETE FROM dashboard WHERE id = ?",
-			"DELETE FROM playlist_item WHERE type = 'dashboard_by_id' AND value = ?",
+		// deleting a folder deletes the dashboards in it
+		if dashboard.IsFolder {
+			var children []*m.Dashboard
+			if err := sess.Where("folder_id=? AND org_id=?", dashboard.Id, dashboard.OrgId).Find(&children); err != nil {
+				return err
+			}
+
+			for _, child := range children {
+				if err := deleteDashboardInternal(child, sess); err != nil {
+					return err
+				}
+			}
+		}
+
+		return deleteDashboardInternal(&dashboard, sess)
+	})
+}
+
+func deleteDashboardInternal(dashboard *m.Dashboard, sess *session) error {
+	deletes := []string{
+		"DELETE FROM dashboard_tag WHERE dashboard_id = ? ",
+		"DELETE FROM star WHERE dashboard_id = ? ",
+		"DELETE FROM dashboard WHERE id = ?",
+		"DELETE FROM dashboard_version WHERE dashboard_id = ?",
+		"DELETE FROM dashboard_acl WHERE dashboard_id = ?",
+		"DELETE FROM playlist_item WHERE type = 'dashboard_by_id' AND value = ?",
+		"DELETE FROM dashboard_provisioning WHERE dashboard_id = ?",
+		"DELETE FROM dashboard_view WHERE dashboard_id = ?",
+	}
+
+	for _, sql := range deletes {
+		_, err := sess.Exec(sql, dashboard.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := DeleteAlertDefinition(dashboard.Id, sess.Session); err != nil {
+		return nil
+	}
+
+	sess.publishAfterCommit(&events.DashboardDeleted{
+		Timestamp: time.Now(),
+		Id:        dashboard.Id,
+		Uid:       dashboard.Uid,
+		OrgId:     dashboard.OrgId,
+	})
+
+	return nil
+}
+
+// validateFolder checks that the folder exists in the org.
+func validateFolder(sess *xorm.Session, orgId int64, folderId int64) error {
+	var folder m.Dashboard
+	exists, err := sess.Where("id=? AND org_id=?", folderId, orgId).Get(&folder)
+	if err != nil {
+		return err
+	}
+
+	if !exists || !folder.IsFolder {
+		return m.ErrFolderNotFound
+	}
+
+	return nil
+}
+
+func MoveDashboard(cmd *m.MoveDashboardCommand) error {
+	return inTransaction2(func(sess *session) error {
+		var dashboard m.Dashboard
+		exists, err := sess.Where("id=? AND org_id=?", cmd.DashboardId, cmd.OrgId).Get(&dashboard)
+		if err != nil {
+			return err
+		} else if !exists {
+			return m.ErrDashboardNotFound
 		}
 
-		for _, sql := range deletes {
-			_, err := sess.Exec(sql, dashboard.Id)
-			if err != nil {
+		if cmd.FolderId > 0 {
+			if dashboard.IsFolder {
+				return m.ErrDashboardFolderNesting
+			}
+
+			if err := validateFolder(sess.Session, cmd.OrgId, cmd.FolderId); err != nil {
 				return err
 			}
 		}
 
-		if err := DeleteAlertDefinition(dashboard.Id, sess.Session); err != nil {
-			return nil
+		if _, err = sess.Exec("UPDATE dashboard SET folder_id=? WHERE id=?", cmd.FolderId, dashboard.Id); err != nil {
+			return err
 		}
 
+		sess.publishAfterCommit(&events.DashboardSaved{
+			Timestamp: time.Now(),
+			Id:        dashboard.Id,
+			Uid:       dashboard.Uid,
+			OrgId:     dashboard.OrgId,
+			Title:     dashboard.Title,
+		})
+
 		return nil
 	})
 }
 
+func GetFolders(query *m.GetFoldersQuery) error {
+	query.Result = make([]*m.Dashboard, 0)
+	return x.Where("org_id=? AND is_folder=?", query.OrgId, dialect.BooleanStr(true)).Asc("title").Find(&query.Result)
+}
+
 func GetDashboards(query *m.GetDashboardsQuery) error {
 	if len(query.DashboardIds) == 0 {
 		return m.ErrCommandValidationFailed
@@ -300,3 +573,16 @@ func GetDashboardSlugById(query *m.GetDashboardSlugByIdQuery) error {
 	query.Result = slug.Slug
 	return nil
 }
+
+func GetDashboardRefById(query *m.GetDashboardRefByIdQuery) error {
+	var ref m.DashboardRef
+	exists, err := x.Sql(`SELECT uid, slug, title FROM dashboard WHERE id=?`, query.Id).Get(&ref)
+	if err != nil {
+		return err
+	} else if !exists {
+		return m.ErrDashboardNotFound
+	}
+
+	query.Result = &ref
+	return nil
+}
@@ -0,0 +1,197 @@
+package sqlstore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-xorm/xorm"
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", UpdateDashboardAcl)
+	bus.AddHandler("sql", GetDashboardAclInfoList)
+	bus.AddHandler("sql", GetOrgDashboardAcls)
+}
+
+func UpdateDashboardAcl(cmd *m.UpdateDashboardAclCommand) error {
+	return inTransaction(func(sess *xorm.Session) error {
+		var dashboard m.Dashboard
+		exists, err := sess.Where("id=? AND org_id=?", cmd.DashboardId, cmd.OrgId).Get(&dashboard)
+		if err != nil {
+			return err
+		} else if !exists {
+			return m.ErrDashboardNotFound
+		}
+
+		if err := validateDashboardAcl(sess, cmd); err != nil {
+			return err
+		}
+
+		if _, err := sess.Exec("DELETE FROM dashboard_acl WHERE dashboard_id=?", cmd.DashboardId); err != nil {
+			return err
+		}
+
+		// an empty ACL restricts the dashboard instead of restoring the defaults
+		if _, err := sess.Exec("UPDATE dashboard SET has_acl=? WHERE id=?", dialect.BooleanStr(true), cmd.DashboardId); err != nil {
+			return err
+		}
+
+		for _, item := range cmd.Items {
+			item.Id = 0
+			item.OrgId = cmd.OrgId
+			item.DashboardId = cmd.DashboardId
+			item.Created = time.Now()
+			item.Updated = time.Now()
+
+			if _, err := sess.Insert(item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func validateDashboardAcl(sess *xorm.Session, cmd *m.UpdateDashboardAclCommand) error {
+	users := make(map[int64]bool)
+	teams := make(map[int64]bool)
+	roles := make(map[m.RoleType]bool)
+
+	for _, item := range cmd.Items {
+		if !item.Permission.IsValid() {
+			return m.ErrDashboardAclInvalid
+		}
+
+		switch {
+		case item.UserId > 0 && item.TeamId == 0 && item.Role == nil:
+			if users[item.UserId] {
+				return m.ErrDashboardAclDuplicate
+			}
+			users[item.UserId] = true
+
+			res, err := sess.Query("SELECT 1 FROM org_user WHERE org_id=? AND user_id=?", cmd.OrgId, item.UserId)
+			if err != nil {
+				return err
+			} else if len(res) == 0 {
+				return m.ErrOrgUserNotFound
+			}
+		case item.TeamId > 0 && item.UserId == 0 && item.Role == nil:
+			if teams[item.TeamId] {
+				return m.ErrDashboardAclDuplicate
+			}
+			teams[item.TeamId] = true
+
+			res, err := sess.Query("SELECT 1 FROM team WHERE org_id=? AND id=?", cmd.OrgId, item.TeamId)
+			if err != nil {
+				return err
+			} else if len(res) == 0 {
+				return m.ErrTeamNotFound
+			}
+		case item.UserId == 0 && item.TeamId == 0 && item.Role != nil && item.Role.IsValid():
+			if roles[*item.Role] {
+				return m.ErrDashboardAclDuplicate
+			}
+			roles[*item.Role] = true
+		default:
+			return m.ErrDashboardAclInvalid
+		}
+	}
+
+	return nil
+}
+
+func GetDashboardAclInfoList(query *m.GetDashboardAclInfoListQuery) error {
+	var dashboard m.Dashboard
+	exists, err := x.Where("id=? AND org_id=?", query.DashboardId, query.OrgId).Get(&dashboard)
+	if err != nil {
+		return err
+	} else if !exists {
+		return m.ErrDashboardNotFound
+	}
+
+	if dashboard.HasAcl {
+		query.ResultHasAcl = true
+		query.Result, err = getDashboardAclInfo(query.OrgId, dashboard.Id)
+		return err
+	}
+
+	query.Result = make([]*m.DashboardAclInfoDTO, 0)
+
+	// dashboards without an ACL inherit the ACL of their folder
+	if dashboard.FolderId == 0 {
+		return nil
+	}
+
+	var folder m.Dashboard
+	if exists, err := x.Where("id=? AND org_id=?", dashboard.FolderId, query.OrgId).Get(&folder); err != nil {
+		return err
+	} else if !exists || !folder.HasAcl {
+		return nil
+	}
+
+	acl, err := getDashboardAclInfo(query.OrgId, folder.Id)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range acl {
+		item.Inherited = true
+	}
+
+	query.ResultHasAcl = true
+	query.Result = acl
+	return nil
+}
+
+func getDashboardAclInfo(orgId int64, dashboardId int64) ([]*m.DashboardAclInfoDTO, error) {
+	rawSql := fmt.Sprintf(`SELECT
+		dashboard_acl.id,
+		dashboard_acl.org_id,
+		dashboard_acl.dashboard_id,
+		dashboard_acl.user_id,
+		dashboard_acl.team_id,
+		dashboard_acl.role,
+		dashboard_acl.permission,
+		dashboard_acl.created,
+		dashboard_acl.updated,
+		u.login AS user_login,
+		u.email AS user_email,
+		team.name AS team
+		FROM dashboard_acl
+		LEFT OUTER JOIN %s AS u ON u.id = dashboard_acl.user_id
+		LEFT OUTER JOIN team ON team.id = dashboard_acl.team_id
+		WHERE dashboard_acl.org_id = ? AND dashboard_acl.dashboard_id = ?
+		ORDER BY dashboard_acl.id ASC`, dialect.Quote("user"))
+
+	acl := make([]*m.DashboardAclInfoDTO, 0)
+	if err := x.Sql(rawSql, orgId, dashboardId).Find(&acl); err != nil {
+		return nil, err
+	}
+
+	for _, item := range acl {
+		item.PermissionName = item.Permission.String()
+	}
+
+	return acl, nil
+}
+
+func GetOrgDashboardAcls(query *m.GetOrgDashboardAclsQuery) error {
+	query.Result = make([]*m.DashboardAclInfoDTO, 0)
+	if err := x.Sql(`SELECT id, org_id, dashboard_id, user_id, team_id, role, permission, created, updated
+		FROM dashboard_acl WHERE org_id = ?`, query.OrgId).Find(&query.Result); err != nil {
+		return err
+	}
+
+	var ids []int64
+	if err := x.Table("dashboard").Where("org_id=? AND has_acl=?", query.OrgId, dialect.BooleanStr(true)).Cols("id").Find(&ids); err != nil {
+		return err
+	}
+
+	query.ResultHasAcl = make(map[int64]bool)
+	for _, id := range ids {
+		query.ResultHasAcl[id] = true
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package sqlstore
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func TestDashboardAclDataAccess(t *testing.T) {
+
+	Convey("Testing DB", t, func() {
+		InitTestDB(t)
+
+		Convey("Given a user and a dashboard in a folder", func() {
+			userCmd := m.CreateUserCommand{Login: "finance", Email: "finance@test.com"}
+			err := CreateUser(&userCmd)
+			So(err, ShouldBeNil)
+			user := userCmd.Result
+
+			folder := insertTestFolder("finance", user.OrgId)
+			dash := insertTestDashboardInFolder("revenue", user.OrgId, folder.Id)
+
+			Convey("Dashboard without entries should have an empty ACL", func() {
+				query := m.GetDashboardAclInfoListQuery{DashboardId: dash.Id, OrgId: user.OrgId}
+				err := GetDashboardAclInfoList(&query)
+				So(err, ShouldBeNil)
+				So(len(query.Result), ShouldEqual, 0)
+				So(query.ResultHasAcl, ShouldBeFalse)
+			})
+
+			Convey("Given ACL entries on the folder", func() {
+				viewer := m.ROLE_VIEWER
+				err := UpdateDashboardAcl(&m.UpdateDashboardAclCommand{
+					DashboardId: folder.Id,
+					OrgId:       user.OrgId,
+					Items: []*m.DashboardAcl{
+						{UserId: user.Id, Permission: m.PERMISSION_EDIT},
+						{Role: &viewer, Permission: m.PERMISSION_VIEW},
+					},
+				})
+				So(err, ShouldBeNil)
+
+				Convey("Folder should return its entries", func() {
+					query := m.GetDashboardAclInfoListQuery{DashboardId: folder.Id, OrgId: user.OrgId}
+					err := GetDashboardAclInfoList(&query)
+					So(err, ShouldBeNil)
+
+					So(len(query.Result), ShouldEqual, 2)
+					So(query.Result[0].UserId, ShouldEqual, user.Id)
+					So(query.Result[0].UserLogin, ShouldEqual, "finance")
+					So(query.Result[0].PermissionName, ShouldEqual, "Edit")
+					So(*query.Result[1].Role, ShouldEqual, m.ROLE_VIEWER)
+					So(query.Result[1].Inherited, ShouldBeFalse)
+				})
+
+				Convey("Dashboard should inherit the folder entries", func() {
+					query := m.GetDashboardAclInfoListQuery{DashboardId: dash.Id, OrgId: user.OrgId}
+					err := GetDashboardAclInfoList(&query)
+					So(err, ShouldBeNil)
+
+					So(len(query.Result), ShouldEqual, 2)
+					So(query.Result[0].Inherited, ShouldBeTrue)
+					So(query.ResultHasAcl, ShouldBeTrue)
+				})
+
+				Convey("Removing all folder entries should keep the folder restricted", func() {
+					err := UpdateDashboardAcl(&m.UpdateDashboardAclCommand{DashboardId: folder.Id, OrgId: user.OrgId})
+					So(err, ShouldBeNil)
+
+					query := m.GetDashboardAclInfoListQuery{DashboardId: dash.Id, OrgId: user.OrgId}
+					err = GetDashboardAclInfoList(&query)
+					So(err, ShouldBeNil)
+					So(len(query.Result), ShouldEqual, 0)
+					So(query.ResultHasAcl, ShouldBeTrue)
+
+					orgQuery := m.GetOrgDashboardAclsQuery{OrgId: user.OrgId}
+					err = GetOrgDashboardAcls(&orgQuery)
+					So(err, ShouldBeNil)
+					So(orgQuery.ResultHasAcl, ShouldResemble, map[int64]bool{folder.Id: true})
+				})
+
+				Convey("Dashboard entries should replace the folder entries", func() {
+					err := UpdateDashboardAcl(&m.UpdateDashboardAclCommand{
+						DashboardId: dash.Id,
+						OrgId:       user.OrgId,
+						Items:       []*m.DashboardAcl{{UserId: user.Id, Permission: m.PERMISSION_ADMIN}},
+					})
+					So(err, ShouldBeNil)
+
+					query := m.GetDashboardAclInfoListQuery{DashboardId: dash.Id, OrgId: user.OrgId}
+					err = GetDashboardAclInfoList(&query)
+					So(err, ShouldBeNil)
+
+					So(len(query.Result), ShouldEqual, 1)
+					So(query.Result[0].Permission, ShouldEqual, m.PERMISSION_ADMIN)
+					So(query.Result[0].Inherited, ShouldBeFalse)
+				})
+
+				Convey("Should list all entries of the org", func() {
+					query := m.GetOrgDashboardAclsQuery{OrgId: user.OrgId}
+					err := GetOrgDashboardAcls(&query)
+					So(err, ShouldBeNil)
+					So(len(query.Result), ShouldEqual, 2)
+				})
+
+				Convey("Deleting the folder should delete its entries", func() {
+					err := DeleteDashboard(&m.DeleteDashboardCommand{Slug: folder.Slug, OrgId: user.OrgId})
+					So(err, ShouldBeNil)
+
+					query := m.GetOrgDashboardAclsQuery{OrgId: user.OrgId}
+					err = GetOrgDashboardAcls(&query)
+					So(err, ShouldBeNil)
+					So(len(query.Result), ShouldEqual, 0)
+				})
+
+				Convey("Deleting the user should delete the user entries", func() {
+					err := DeleteUser(&m.DeleteUserCommand{UserId: user.Id})
+					So(err, ShouldBeNil)
+
+					query := m.GetOrgDashboardAclsQuery{OrgId: user.OrgId}
+					err = GetOrgDashboardAcls(&query)
+					So(err, ShouldBeNil)
+					So(len(query.Result), ShouldEqual, 1)
+				})
+			})
+
+			Convey("Should not add duplicate entries", func() {
+				err := UpdateDashboardAcl(&m.UpdateDashboardAclCommand{
+					DashboardId: dash.Id,
+					OrgId:       user.OrgId,
+					Items: []*m.DashboardAcl{
+						{UserId: user.Id, Permission: m.PERMISSION_VIEW},
+						{UserId: user.Id, Permission: m.PERMISSION_EDIT},
+					},
+				})
+				So(err, ShouldEqual, m.ErrDashboardAclDuplicate)
+			})
+
+			Convey("Should not add entries without a user or role", func() {
+				err := UpdateDashboardAcl(&m.UpdateDashboardAclCommand{
+					DashboardId: dash.Id,
+					OrgId:       user.OrgId,
+					Items:       []*m.DashboardAcl{{Permission: m.PERMISSION_VIEW}},
+				})
+				So(err, ShouldEqual, m.ErrDashboardAclInvalid)
+			})
+
+			Convey("Should not add entries for users outside the org", func() {
+				err := UpdateDashboardAcl(&m.UpdateDashboardAclCommand{
+					DashboardId: dash.Id,
+					OrgId:       user.OrgId,
+					Items:       []*m.DashboardAcl{{UserId: user.Id + 100, Permission: m.PERMISSION_VIEW}},
+				})
+				So(err, ShouldEqual, m.ErrOrgUserNotFound)
+			})
+		})
+	})
+}
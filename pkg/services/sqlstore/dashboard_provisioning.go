@@ -0,0 +1,110 @@
+package sqlstore
+
+import (
+	"github.com/go-xorm/xorm"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", SaveProvisionedDashboard)
+	bus.AddHandler("sql", GetProvisionedDashboardData)
+	bus.AddHandler("sql", GetProvisionedDashboardDataById)
+}
+
+func SaveProvisionedDashboard(cmd *m.SaveProvisionedDashboardCommand) error {
+	return inTransaction2(func(sess *session) error {
+		if err := checkOverwritesProvisioned(sess.Session, cmd.DashboardCmd); err != nil {
+			return err
+		}
+
+		if err := saveDashboard(sess, cmd.DashboardCmd); err != nil {
+			return err
+		}
+
+		cmd.Result = cmd.DashboardCmd.Result
+		return saveProvisionedData(sess.Session, cmd.DashboardProvisioning, cmd.Result)
+	})
+}
+
+// checkOverwritesProvisioned refuses to let a provisioned dashboard
+// overwrite another dashboard with the same uid or title that was not
+// provisioned. A dashboard that was provisioned before is saved by its id
+// and never takes over another dashboard, even a provisioned one.
+func checkOverwritesProvisioned(sess *xorm.Session, cmd *m.SaveDashboardCommand) error {
+	dash := cmd.GetDashboardModel()
+
+	var existing []*m.Dashboard
+	query := sess.Where("org_id=? AND id<>? AND slug=?", dash.OrgId, dash.Id, dash.Slug)
+	if dash.Uid != "" {
+		query = sess.Where("org_id=? AND id<>? AND (slug=? OR uid=?)", dash.OrgId, dash.Id, dash.Slug, dash.Uid)
+	}
+	if err := query.Find(&existing); err != nil {
+		return err
+	}
+
+	for _, item := range existing {
+		var data m.DashboardProvisioning
+		provisioned, err := sess.Where("dashboard_id=?", item.Id).Get(&data)
+		if err != nil {
+			return err
+		}
+
+		if !provisioned {
+			return m.ErrDashboardNotProvisioned
+		}
+
+		if dash.Id > 0 {
+			return m.ErrDashboardWithSameNameExists
+		}
+	}
+
+	return nil
+}
+
+func saveProvisionedData(sess *xorm.Session, provisioning *m.DashboardProvisioning, dashboard *m.Dashboard) error {
+	var existing m.DashboardProvisioning
+	exists, err := sess.Where("dashboard_id=?", dashboard.Id).Get(&existing)
+	if err != nil {
+		return err
+	}
+
+	provisioning.DashboardId = dashboard.Id
+
+	if exists {
+		provisioning.Id = existing.Id
+		_, err = sess.Id(existing.Id).MustCols("allow_ui_updates").Update(provisioning)
+	} else {
+		provisioning.Id = 0
+		_, err = sess.Insert(provisioning)
+	}
+
+	return err
+}
+
+func GetProvisionedDashboardData(query *m.GetProvisionedDashboardDataQuery) error {
+	var result []*m.DashboardProvisioning
+
+	if err := x.Where("name = ?", query.Name).Find(&result); err != nil {
+		return err
+	}
+
+	query.Result = result
+	return nil
+}
+
+func GetProvisionedDashboardDataById(query *m.GetProvisionedDashboardDataByIdQuery) error {
+	var result m.DashboardProvisioning
+
+	exists, err := x.Where("dashboard_id = ?", query.DashboardId).Get(&result)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		query.Result = &result
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDashboardProvisioning(t *testing.T) {
+	Convey("Testing Dashboard provisioning", t, func() {
+		InitTestDB(t)
+
+		saveDashboardCmd := &m.SaveDashboardCommand{
+			OrgId:     1,
+			Overwrite: true,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{
+				"id":    nil,
+				"title": "test dashboard",
+			}),
+		}
+
+		Convey("Saving dashboards with provisioning meta data", func() {
+			cmd := &m.SaveProvisionedDashboardCommand{
+				DashboardCmd: saveDashboardCmd,
+				DashboardProvisioning: &m.DashboardProvisioning{
+					Name:       "default",
+					ExternalId: "/var/grafana.json",
+					Updated:    1234,
+				},
+			}
+
+			err := SaveProvisionedDashboard(cmd)
+			So(err, ShouldBeNil)
+			So(cmd.Result, ShouldNotBeNil)
+			So(cmd.Result.Id, ShouldNotEqual, 0)
+			dashId := cmd.Result.Id
+
+			Convey("Can query for provisioned dashboards", func() {
+				query := &m.GetProvisionedDashboardDataQuery{Name: "default"}
+				err := GetProvisionedDashboardData(query)
+				So(err, ShouldBeNil)
+
+				So(len(query.Result), ShouldEqual, 1)
+				So(query.Result[0].DashboardId, ShouldEqual, dashId)
+				So(query.Result[0].Updated, ShouldEqual, 1234)
+			})
+
+			Convey("Saving again should update the existing entry", func() {
+				cmd.DashboardProvisioning.Updated = 5678
+				cmd.DashboardProvisioning.AllowUiUpdates = true
+				err := SaveProvisionedDashboard(cmd)
+				So(err, ShouldBeNil)
+				So(cmd.Result.Id, ShouldEqual, dashId)
+
+				query := &m.GetProvisionedDashboardDataByIdQuery{DashboardId: dashId}
+				err = GetProvisionedDashboardDataById(query)
+				So(err, ShouldBeNil)
+				So(query.Result.Updated, ShouldEqual, 5678)
+				So(query.Result.AllowUiUpdates, ShouldBeTrue)
+			})
+
+			Convey("Should not overwrite a dashboard that was not provisioned when renamed", func() {
+				other := insertTestDashboard("other dashboard", 1)
+
+				cmd.DashboardCmd = &m.SaveDashboardCommand{
+					OrgId:     1,
+					Overwrite: true,
+					Dashboard: simplejson.NewFromAny(map[string]interface{}{
+						"id":    dashId,
+						"title": "other dashboard",
+					}),
+				}
+
+				err := SaveProvisionedDashboard(cmd)
+				So(err, ShouldEqual, m.ErrDashboardNotProvisioned)
+
+				query := &m.GetProvisionedDashboardDataByIdQuery{DashboardId: other.Id}
+				err = GetProvisionedDashboardDataById(query)
+				So(err, ShouldBeNil)
+				So(query.Result, ShouldBeNil)
+			})
+
+			Convey("Should not take over another provisioned dashboard when renamed", func() {
+				otherCmd := &m.SaveProvisionedDashboardCommand{
+					DashboardCmd: &m.SaveDashboardCommand{
+						OrgId: 1,
+						Dashboard: simplejson.NewFromAny(map[string]interface{}{
+							"title": "other dashboard",
+						}),
+					},
+					DashboardProvisioning: &m.DashboardProvisioning{Name: "default", ExternalId: "/var/other.json"},
+				}
+				So(SaveProvisionedDashboard(otherCmd), ShouldBeNil)
+
+				cmd.DashboardCmd = &m.SaveDashboardCommand{
+					OrgId:     1,
+					Overwrite: true,
+					Dashboard: simplejson.NewFromAny(map[string]interface{}{
+						"id":    dashId,
+						"title": "other dashboard",
+					}),
+				}
+
+				err := SaveProvisionedDashboard(cmd)
+				So(err, ShouldEqual, m.ErrDashboardWithSameNameExists)
+			})
+
+			Convey("Deleting the dashboard should delete the provisioning entry", func() {
+				err := DeleteDashboard(&m.DeleteDashboardCommand{Slug: cmd.Result.Slug, OrgId: 1})
+				So(err, ShouldBeNil)
+
+				query := &m.GetProvisionedDashboardDataByIdQuery{DashboardId: dashId}
+				err = GetProvisionedDashboardDataById(query)
+				So(err, ShouldBeNil)
+				So(query.Result, ShouldBeNil)
+			})
+		})
+
+		Convey("Should not overwrite a dashboard that was not provisioned", func() {
+			err := SaveDashboard(&m.SaveDashboardCommand{
+				OrgId: 1,
+				Dashboard: simplejson.NewFromAny(map[string]interface{}{
+					"title": "test dashboard",
+				}),
+			})
+			So(err, ShouldBeNil)
+
+			cmd := &m.SaveProvisionedDashboardCommand{
+				DashboardCmd:          saveDashboardCmd,
+				DashboardProvisioning: &m.DashboardProvisioning{Name: "default", ExternalId: "/var/grafana.json"},
+			}
+
+			err = SaveProvisionedDashboard(cmd)
+			So(err, ShouldEqual, m.ErrDashboardNotProvisioned)
+
+			query := &m.GetProvisionedDashboardDataQuery{Name: "default"}
+			err = GetProvisionedDashboardData(query)
+			So(err, ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+		})
+	})
+}
@@ -27,6 +27,36 @@ func insertTestDashboard(title string, orgId int64, tags ...interface{}) *m.Dash
 	return cmd.Result
 }
 
+func insertTestFolder(title string, orgId int64) *m.Dashboard {
+	cmd := m.SaveDashboardCommand{
+		OrgId:    orgId,
+		IsFolder: true,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{
+			"title": title,
+		}),
+	}
+
+	err := SaveDashboard(&cmd)
+	So(err, ShouldBeNil)
+
+	return cmd.Result
+}
+
+func insertTestDashboardInFolder(title string, orgId int64, folderId int64) *m.Dashboard {
+	cmd := m.SaveDashboardCommand{
+		OrgId:    orgId,
+		FolderId: folderId,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{
+			"title": title,
+		}),
+	}
+
+	err := SaveDashboard(&cmd)
+	So(err, ShouldBeNil)
+
+	return cmd.Result
+}
+
 func TestDashboardDataAccess(t *testing.T) {
 
 	Convey("Testing DB", t, func() {
@@ -56,6 +86,17 @@ func TestDashboardDataAccess(t *testing.T) {
 				So(query.Result.Slug, ShouldEqual, "test-dash-23")
 			})
 
+			Convey("Should be able to get dashboard ref by id", func() {
+				query := m.GetDashboardRefByIdQuery{Id: savedDash.Id}
+
+				err := GetDashboardRefById(&query)
+				So(err, ShouldBeNil)
+
+				So(query.Result.Uid, ShouldEqual, savedDash.Uid)
+				So(query.Result.Slug, ShouldEqual, "test-dash-23")
+				So(query.Result.Title, ShouldEqual, "test dash 23")
+			})
+
 			Convey("Should be able to delete dashboard", func() {
 				insertTestDashboard("delete me", 1, "delete this")
 
@@ -102,6 +143,93 @@ func TestDashboardDataAccess(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 
+			Convey("Should generate a uid for new dashboards", func() {
+				So(savedDash.Uid, ShouldNotBeEmpty)
+				So(savedDash.Data.Get("uid").MustString(), ShouldEqual, savedDash.Uid)
+
+				query := m.GetDashboardQuery{Uid: savedDash.Uid, OrgId: 1}
+				err := GetDashboard(&query)
+				So(err, ShouldBeNil)
+				So(query.Result.Id, ShouldEqual, savedDash.Id)
+			})
+
+			Convey("Should keep the uid when saving without one", func() {
+				cmd := m.SaveDashboardCommand{
+					OrgId: 1,
+					Dashboard: simplejson.NewFromAny(map[string]interface{}{
+						"id":      float64(savedDash.Id),
+						"title":   "test dash 23 renamed",
+						"version": float64(savedDash.Version),
+					}),
+				}
+
+				err := SaveDashboard(&cmd)
+				So(err, ShouldBeNil)
+				So(cmd.Result.Uid, ShouldEqual, savedDash.Uid)
+			})
+
+			Convey("Should save a dashboard with a given uid", func() {
+				cmd := m.SaveDashboardCommand{
+					OrgId: 1,
+					Dashboard: simplejson.NewFromAny(map[string]interface{}{
+						"uid":   "staging-dash",
+						"title": "imported dash",
+					}),
+				}
+
+				err := SaveDashboard(&cmd)
+				So(err, ShouldBeNil)
+				So(cmd.Result.Uid, ShouldEqual, "staging-dash")
+
+				Convey("Should not save another dashboard with the same uid", func() {
+					cmd.Dashboard = simplejson.NewFromAny(map[string]interface{}{
+						"uid":   "staging-dash",
+						"title": "other dash",
+					})
+
+					err := SaveDashboard(&cmd)
+					So(err, ShouldEqual, m.ErrDashboardWithSameUIDExists)
+				})
+
+				Convey("Should overwrite the dashboard with the same uid", func() {
+					cmd.Overwrite = true
+					cmd.Dashboard = simplejson.NewFromAny(map[string]interface{}{
+						"uid":   "staging-dash",
+						"title": "imported dash renamed",
+					})
+
+					err := SaveDashboard(&cmd)
+					So(err, ShouldBeNil)
+
+					query := m.GetDashboardQuery{Uid: "staging-dash", OrgId: 1}
+					err = GetDashboard(&query)
+					So(err, ShouldBeNil)
+					So(query.Result.Title, ShouldEqual, "imported dash renamed")
+					So(query.Result.Version, ShouldEqual, 2)
+				})
+
+				Convey("Should not save the uid in another org", func() {
+					cmd.OrgId = 2
+					cmd.Overwrite = true
+
+					err := SaveDashboard(&cmd)
+					So(err, ShouldEqual, m.ErrDashboardUidInOtherOrg)
+				})
+			})
+
+			Convey("Should not save a dashboard with an invalid uid", func() {
+				cmd := m.SaveDashboardCommand{
+					OrgId: 1,
+					Dashboard: simplejson.NewFromAny(map[string]interface{}{
+						"uid":   "my/dash",
+						"title": "invalid uid",
+					}),
+				}
+
+				err := SaveDashboard(&cmd)
+				So(err, ShouldEqual, m.ErrDashboardInvalidUid)
+			})
+
 			Convey("Should be able to search for dashboard", func() {
 				query := search.FindPersistedDashboardsQuery{
 					Title: "test dash 23",
@@ -191,6 +319,108 @@ func TestDashboardDataAccess(t *testing.T) {
 					So(query.Result[0].Title, ShouldEqual, "starred dash")
 				})
 			})
+
+			Convey("Given a folder with a dashboard", func() {
+				folder := insertTestFolder("finance", 1)
+				childDash := insertTestDashboardInFolder("revenue", 1, folder.Id)
+
+				Convey("Should be able to get folders", func() {
+					query := m.GetFoldersQuery{OrgId: 1}
+					err := GetFolders(&query)
+					So(err, ShouldBeNil)
+					So(len(query.Result), ShouldEqual, 1)
+					So(query.Result[0].Title, ShouldEqual, "finance")
+				})
+
+				Convey("Should be able to search by folder", func() {
+					query := search.FindPersistedDashboardsQuery{OrgId: 1, FolderIds: []int64{folder.Id}}
+					err := SearchDashboards(&query)
+					So(err, ShouldBeNil)
+					So(len(query.Result), ShouldEqual, 1)
+					So(query.Result[0].Id, ShouldEqual, childDash.Id)
+					So(query.Result[0].FolderTitle, ShouldEqual, "finance")
+				})
+
+				Convey("Should return folders as folder hits", func() {
+					query := search.FindPersistedDashboardsQuery{OrgId: 1, Title: "finance"}
+					err := SearchDashboards(&query)
+					So(err, ShouldBeNil)
+					So(query.Result[0].Type, ShouldEqual, search.DashHitFolder)
+				})
+
+				Convey("Should not be able to save dashboard in missing folder", func() {
+					cmd := m.SaveDashboardCommand{
+						OrgId:     1,
+						FolderId:  savedDash.Id,
+						Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "no folder"}),
+					}
+					err := SaveDashboard(&cmd)
+					So(err, ShouldEqual, m.ErrFolderNotFound)
+				})
+
+				Convey("Should not be able to nest folders", func() {
+					cmd := m.SaveDashboardCommand{
+						OrgId:     1,
+						FolderId:  folder.Id,
+						IsFolder:  true,
+						Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "nested"}),
+					}
+					err := SaveDashboard(&cmd)
+					So(err, ShouldEqual, m.ErrDashboardFolderNesting)
+				})
+
+				Convey("Should not be able to overwrite a folder with a dashboard", func() {
+					cmd := m.SaveDashboardCommand{
+						OrgId:     1,
+						Overwrite: true,
+						Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "finance"}),
+					}
+					err := SaveDashboard(&cmd)
+					So(err, ShouldEqual, m.ErrDashboardWithSameNameExists)
+				})
+
+				Convey("Should be able to move dashboard out of folder and back", func() {
+					err := MoveDashboard(&m.MoveDashboardCommand{OrgId: 1, DashboardId: childDash.Id})
+					So(err, ShouldBeNil)
+
+					query := m.GetDashboardQuery{Id: childDash.Id, OrgId: 1}
+					GetDashboard(&query)
+					So(query.Result.FolderId, ShouldEqual, 0)
+
+					err = MoveDashboard(&m.MoveDashboardCommand{OrgId: 1, DashboardId: childDash.Id, FolderId: folder.Id})
+					So(err, ShouldBeNil)
+
+					GetDashboard(&query)
+					So(query.Result.FolderId, ShouldEqual, folder.Id)
+				})
+
+				Convey("Should move dashboard out of folder when saved without folder id", func() {
+					cmd := m.SaveDashboardCommand{
+						OrgId:    1,
+						FolderId: 0,
+						Dashboard: simplejson.NewFromAny(map[string]interface{}{
+							"id":      childDash.Id,
+							"title":   "revenue",
+							"version": childDash.Version,
+						}),
+					}
+					err := SaveDashboard(&cmd)
+					So(err, ShouldBeNil)
+
+					query := m.GetDashboardQuery{Id: childDash.Id, OrgId: 1}
+					GetDashboard(&query)
+					So(query.Result.FolderId, ShouldEqual, 0)
+				})
+
+				Convey("Should delete dashboards in folder with the folder", func() {
+					err := DeleteDashboard(&m.DeleteDashboardCommand{Slug: folder.Slug, OrgId: 1})
+					So(err, ShouldBeNil)
+
+					query := m.GetDashboardQuery{Id: childDash.Id, OrgId: 1}
+					err = GetDashboard(&query)
+					So(err, ShouldEqual, m.ErrDashboardNotFound)
+				})
+			})
 		})
 	})
 }
@@ -0,0 +1,77 @@
+package sqlstore
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", GetDashboardVersion)
+	bus.AddHandler("sql", GetDashboardVersions)
+}
+
+// GetDashboardVersion gets the dashboard version for the given dashboard id
+// and version number.
+func GetDashboardVersion(query *m.GetDashboardVersionQuery) error {
+	version := m.DashboardVersion{}
+	has, err := x.Table("dashboard_version").
+		Select("dashboard_version.*").
+		Join("LEFT", "dashboard", "dashboard.id = dashboard_version.dashboard_id").
+		Where("dashboard_version.dashboard_id=? AND dashboard_version.version=? AND dashboard.org_id=?", query.DashboardId, query.Version, query.OrgId).
+		Get(&version)
+
+	if err != nil {
+		return err
+	}
+
+	if !has {
+		return m.ErrDashboardVersionNotFound
+	}
+
+	version.Data.Set("id", version.DashboardId)
+	query.Result = &version
+	return nil
+}
+
+// GetDashboardVersions gets all dashboard versions for the given dashboard
+// id, newest first.
+func GetDashboardVersions(query *m.GetDashboardVersionsQuery) error {
+	if query.Limit == 0 {
+		query.Limit = 1000
+	}
+
+	query.Result = make([]*m.DashboardVersionDTO, 0)
+	err := x.Table("dashboard_version").
+		Select(fmt.Sprintf(`dashboard_version.id,
+				dashboard_version.dashboard_id,
+				dashboard_version.parent_version,
+				dashboard_version.restored_from,
+				dashboard_version.version,
+				dashboard_version.created,
+				dashboard_version.message,
+				%s.login as created_by`, dialect.Quote("user"))).
+		Join("LEFT", "user", fmt.Sprintf("dashboard_version.created_by = %s.id", dialect.Quote("user"))).
+		Join("LEFT", "dashboard", "dashboard.id = dashboard_version.dashboard_id").
+		Where("dashboard_version.dashboard_id=? AND dashboard.org_id=?", query.DashboardId, query.OrgId).
+		OrderBy("dashboard_version.version DESC").
+		Limit(query.Limit, query.Start).
+		Find(&query.Result)
+
+	if err != nil {
+		return err
+	}
+
+	if len(query.Result) < 1 {
+		return m.ErrNoVersionsForDashboardId
+	}
+
+	for _, version := range query.Result {
+		if version.CreatedBy == "" {
+			version.CreatedBy = "Anonymous"
+		}
+	}
+
+	return nil
+}
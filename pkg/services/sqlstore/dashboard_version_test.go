@@ -0,0 +1,97 @@
+package sqlstore
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func updateTestDashboard(dashboard *m.Dashboard, data map[string]interface{}) {
+	data["id"] = dashboard.Id
+	data["title"] = dashboard.Title
+	data["version"] = dashboard.Version
+
+	saveCmd := m.SaveDashboardCommand{
+		OrgId:     dashboard.OrgId,
+		Overwrite: true,
+		Message:   "changed title",
+		Dashboard: simplejson.NewFromAny(data),
+	}
+
+	err := SaveDashboard(&saveCmd)
+	So(err, ShouldBeNil)
+}
+
+func TestDashboardVersioning(t *testing.T) {
+	Convey("Testing dashboard versions", t, func() {
+		InitTestDB(t)
+
+		Convey("Saving a new dashboard should store the first version", func() {
+			savedDash := insertTestDashboard("test dash 26", 1, "diff")
+			So(savedDash.Version, ShouldEqual, 1)
+
+			query := m.GetDashboardVersionQuery{DashboardId: savedDash.Id, Version: 1, OrgId: 1}
+			err := GetDashboardVersion(&query)
+			So(err, ShouldBeNil)
+			So(query.Result.DashboardId, ShouldEqual, savedDash.Id)
+			So(query.Result.Data.Get("title").MustString(), ShouldEqual, "test dash 26")
+		})
+
+		Convey("Updating a dashboard should store a new version", func() {
+			savedDash := insertTestDashboard("test dash 27", 1, "diff")
+			updateTestDashboard(savedDash, map[string]interface{}{
+				"tags": "different-tag",
+			})
+
+			query := m.GetDashboardVersionQuery{DashboardId: savedDash.Id, Version: 2, OrgId: 1}
+			err := GetDashboardVersion(&query)
+			So(err, ShouldBeNil)
+			So(query.Result.ParentVersion, ShouldEqual, 1)
+			So(query.Result.Message, ShouldEqual, "changed title")
+			So(query.Result.Data.Get("tags").MustString(), ShouldEqual, "different-tag")
+
+			Convey("and list the versions newest first", func() {
+				query := m.GetDashboardVersionsQuery{DashboardId: savedDash.Id, OrgId: 1}
+				err := GetDashboardVersions(&query)
+				So(err, ShouldBeNil)
+				So(len(query.Result), ShouldEqual, 2)
+				So(query.Result[0].Version, ShouldEqual, 2)
+				So(query.Result[0].CreatedBy, ShouldEqual, "Anonymous")
+			})
+
+			Convey("and not return versions to another org", func() {
+				query := m.GetDashboardVersionQuery{DashboardId: savedDash.Id, Version: 2, OrgId: 2}
+				err := GetDashboardVersion(&query)
+				So(err, ShouldEqual, m.ErrDashboardVersionNotFound)
+			})
+
+			Convey("and delete the versions with the dashboard", func() {
+				err := DeleteDashboard(&m.DeleteDashboardCommand{Slug: savedDash.Slug, OrgId: 1})
+				So(err, ShouldBeNil)
+
+				query := m.GetDashboardVersionsQuery{DashboardId: savedDash.Id, OrgId: 1}
+				err = GetDashboardVersions(&query)
+				So(err, ShouldEqual, m.ErrNoVersionsForDashboardId)
+			})
+		})
+
+		Convey("Overwriting a dashboard with the same title should continue its versions", func() {
+			savedDash := insertTestDashboard("test dash 28", 1)
+
+			query := m.GetDashboardVersionsQuery{DashboardId: savedDash.Id, OrgId: 1}
+			err := SaveDashboard(&m.SaveDashboardCommand{
+				OrgId:     1,
+				Overwrite: true,
+				Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "test dash 28"}),
+			})
+			So(err, ShouldBeNil)
+
+			err = GetDashboardVersions(&query)
+			So(err, ShouldBeNil)
+			So(query.Result[0].Version, ShouldEqual, 2)
+		})
+	})
+}
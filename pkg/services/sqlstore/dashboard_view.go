@@ -0,0 +1,54 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", IncrementDashboardViews)
+	bus.AddHandler("sql", GetDashboardViews)
+}
+
+func IncrementDashboardViews(cmd *m.IncrementDashboardViewsCommand) error {
+	return inTransaction2(func(sess *session) error {
+		res, err := sess.Exec("UPDATE dashboard_view SET views = views + 1 WHERE dashboard_id = ?", cmd.DashboardId)
+		if err != nil {
+			return err
+		}
+
+		if rows, err := res.RowsAffected(); err != nil {
+			return err
+		} else if rows == 0 {
+			view := m.DashboardView{DashboardId: cmd.DashboardId, OrgId: cmd.OrgId, Views: 1}
+			if _, err := sess.Insert(&view); err != nil {
+				return err
+			}
+		}
+
+		sess.publishAfterCommit(&events.DashboardViewed{
+			Timestamp: time.Now(),
+			Id:        cmd.DashboardId,
+			OrgId:     cmd.OrgId,
+		})
+
+		return nil
+	})
+}
+
+func GetDashboardViews(query *m.GetDashboardViewsQuery) error {
+	var views []*m.DashboardView
+	if err := x.Find(&views); err != nil {
+		return err
+	}
+
+	query.Result = make(map[int64]int64)
+	for _, view := range views {
+		query.Result[view.DashboardId] = view.Views
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDashboardViewDataAccess(t *testing.T) {
+	var saved []*events.DashboardSaved
+	var deleted []*events.DashboardDeleted
+	var viewed []*events.DashboardViewed
+
+	bus.AddEventListener(func(evt *events.DashboardSaved) error {
+		saved = append(saved, evt)
+		return nil
+	})
+	bus.AddEventListener(func(evt *events.DashboardDeleted) error {
+		deleted = append(deleted, evt)
+		return nil
+	})
+	bus.AddEventListener(func(evt *events.DashboardViewed) error {
+		viewed = append(viewed, evt)
+		return nil
+	})
+
+	Convey("Testing dashboard views", t, func() {
+		InitTestDB(t)
+		saved, deleted, viewed = nil, nil, nil
+
+		folder := insertTestFolder("ops", 1)
+		dash := insertTestDashboardInFolder("servers", 1, folder.Id)
+
+		Convey("Saving should publish events", func() {
+			So(len(saved), ShouldEqual, 2)
+			So(saved[1].Id, ShouldEqual, dash.Id)
+			So(saved[1].Uid, ShouldEqual, dash.Uid)
+			So(saved[1].OrgId, ShouldEqual, 1)
+		})
+
+		Convey("Should count views", func() {
+			cmd := m.IncrementDashboardViewsCommand{DashboardId: dash.Id, OrgId: 1}
+			So(IncrementDashboardViews(&cmd), ShouldBeNil)
+			So(IncrementDashboardViews(&cmd), ShouldBeNil)
+
+			query := m.GetDashboardViewsQuery{}
+			So(GetDashboardViews(&query), ShouldBeNil)
+			So(query.Result[dash.Id], ShouldEqual, 2)
+			So(len(viewed), ShouldEqual, 2)
+
+			Convey("Deleting the dashboard should delete its views", func() {
+				err := DeleteDashboard(&m.DeleteDashboardCommand{Slug: dash.Slug, OrgId: 1})
+				So(err, ShouldBeNil)
+
+				query := m.GetDashboardViewsQuery{}
+				So(GetDashboardViews(&query), ShouldBeNil)
+				So(len(query.Result), ShouldEqual, 0)
+			})
+		})
+
+		Convey("Moving a dashboard should publish a saved event", func() {
+			err := MoveDashboard(&m.MoveDashboardCommand{DashboardId: dash.Id, OrgId: 1})
+			So(err, ShouldBeNil)
+			So(len(saved), ShouldEqual, 3)
+			So(saved[2].Id, ShouldEqual, dash.Id)
+		})
+
+		Convey("Deleting a folder should publish events for its dashboards", func() {
+			err := DeleteDashboard(&m.DeleteDashboardCommand{Slug: folder.Slug, OrgId: 1})
+			So(err, ShouldBeNil)
+			So(len(deleted), ShouldEqual, 2)
+			So(deleted[0].Id, ShouldEqual, dash.Id)
+			So(deleted[1].Id, ShouldEqual, folder.Id)
+		})
+
+		Convey("Should return all dashboards", func() {
+			query := m.GetAllDashboardsQuery{}
+			So(GetAllDashboards(&query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 2)
+		})
+
+		Convey("Should return the dashboards state", func() {
+			query := m.GetDashboardsStateQuery{}
+			So(GetDashboardsState(&query), ShouldBeNil)
+			So(query.Result.Count, ShouldEqual, 2)
+
+			dashQuery := m.GetDashboardQuery{Id: dash.Id, OrgId: 1}
+			So(GetDashboard(&dashQuery), ShouldBeNil)
+			So(query.Result.LastUpdated.Equal(dashQuery.Result.Updated), ShouldBeTrue)
+
+			Convey("Deleting a dashboard should change the state", func() {
+				err := DeleteDashboard(&m.DeleteDashboardCommand{Slug: dash.Slug, OrgId: 1})
+				So(err, ShouldBeNil)
+
+				query := m.GetDashboardsStateQuery{}
+				So(GetDashboardsState(&query), ShouldBeNil)
+				So(query.Result.Count, ShouldEqual, 1)
+			})
+		})
+	})
+}
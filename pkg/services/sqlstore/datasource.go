@@ -18,6 +18,8 @@ func init() {
 	bus.AddHandler("sql", UpdateDataSource)
 	bus.AddHandler("sql", GetDataSourceById)
 	bus.AddHandler("sql", GetDataSourceByName)
+	bus.AddHandler("sql", GetProvisionedDataSources)
+	bus.AddHandler("sql", ReleaseProvisionedDataSource)
 }
 
 func GetDataSourceById(query *m.GetDataSourceByIdQuery) error {
@@ -51,6 +53,11 @@ func GetDataSources(query *m.GetDataSourcesQuery) error {
 	return sess.Find(&query.Result)
 }
 
+func GetProvisionedDataSources(query *m.GetProvisionedDataSourcesQuery) error {
+	query.Result = make([]*m.DataSource, 0)
+	return x.Where("provisioned=?", dialect.BooleanStr(true)).Find(&query.Result)
+}
+
 func DeleteDataSourceById(cmd *m.DeleteDataSourceByIdCommand) error {
 	return inTransaction(func(sess *xorm.Session) error {
 		var rawSql = "DELETE FROM data_source WHERE id=? and org_id=?"
@@ -93,6 +100,8 @@ func AddDataSource(cmd *m.AddDataSourceCommand) error {
 			WithCredentials:   cmd.WithCredentials,
 			JsonData:          cmd.JsonData,
 			SecureJsonData:    securejsondata.GetEncryptedJsonData(cmd.SecureJsonData),
+			ReadOnly:          cmd.ReadOnly,
+			Provisioned:       cmd.Provisioned,
 			Created:           time.Now(),
 			Updated:           time.Now(),
 		}
@@ -140,12 +149,14 @@ func UpdateDataSource(cmd *m.UpdateDataSourceCommand) error {
 			WithCredentials:   cmd.WithCredentials,
 			JsonData:          cmd.JsonData,
 			SecureJsonData:    securejsondata.GetEncryptedJsonData(cmd.SecureJsonData),
+			ReadOnly:          cmd.ReadOnly,
 			Updated:           time.Now(),
 		}
 
 		sess.UseBool("is_default")
 		sess.UseBool("basic_auth")
 		sess.UseBool("with_credentials")
+		sess.UseBool("read_only")
 
 		_, err := sess.Where("id=? and org_id=?", ds.Id, ds.OrgId).Update(ds)
 		if err != nil {
@@ -156,3 +167,11 @@ func UpdateDataSource(cmd *m.UpdateDataSourceCommand) error {
 		return err
 	})
 }
+
+func ReleaseProvisionedDataSource(cmd *m.ReleaseProvisionedDataSourceCommand) error {
+	return inTransaction(func(sess *xorm.Session) error {
+		ds := &m.DataSource{Updated: time.Now()}
+		_, err := sess.Where("id=? and org_id=?", cmd.Id, cmd.OrgId).Cols("read_only", "provisioned", "updated").Update(ds)
+		return err
+	})
+}
@@ -105,6 +105,37 @@ func TestDataAccess(t *testing.T) {
 
 		})
 
+		Convey("Given a provisioned datasource", func() {
+			cmd := &m.AddDataSourceCommand{
+				OrgId:       10,
+				Name:        "provisioned",
+				Type:        m.DS_GRAPHITE,
+				Access:      m.DS_ACCESS_PROXY,
+				Url:         "http://test",
+				ReadOnly:    true,
+				Provisioned: true,
+			}
+			err := AddDataSource(cmd)
+			So(err, ShouldBeNil)
+
+			query := m.GetProvisionedDataSourcesQuery{}
+			err = GetProvisionedDataSources(&query)
+			So(err, ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.Result[0].Name, ShouldEqual, "provisioned")
+
+			Convey("Can release it", func() {
+				err := ReleaseProvisionedDataSource(&m.ReleaseProvisionedDataSourceCommand{Id: cmd.Result.Id, OrgId: 10})
+				So(err, ShouldBeNil)
+
+				byId := m.GetDataSourceByIdQuery{Id: cmd.Result.Id, OrgId: 10}
+				So(GetDataSourceById(&byId), ShouldBeNil)
+				So(byId.Result.ReadOnly, ShouldBeFalse)
+				So(byId.Result.Provisioned, ShouldBeFalse)
+				So(byId.Result.Url, ShouldEqual, "http://test")
+			})
+		})
+
 	})
 
 }
@@ -82,4 +82,52 @@ func addAlertMigrations(mg *Migrator) {
 		{Name: "type", Type: DB_NVarchar, Length: 255, Nullable: false},
 		{Name: "settings", Type: DB_Text, Nullable: false},
 	}))
+
+	mg.AddMigration("Add column acknowledged to alert", NewAddColumnMigration(alertV1, &Column{
+		Name: "acknowledged", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("Add column ack_login to alert", NewAddColumnMigration(alertV1, &Column{
+		Name: "ack_login", Type: DB_NVarchar, Length: 190, Nullable: true,
+	}))
+	mg.AddMigration("Add column ack_comment to alert", NewAddColumnMigration(alertV1, &Column{
+		Name: "ack_comment", Type: DB_Text, Nullable: true,
+	}))
+	mg.AddMigration("Add column ack_date to alert", NewAddColumnMigration(alertV1, &Column{
+		Name: "ack_date", Type: DB_DateTime, Nullable: true,
+	}))
+}
+
+func addAlertNotificationDeliveryMigrations(mg *Migrator) {
+	alertNotificationDeliveryV1 := Table{
+		Name: "alert_notification_delivery",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "notifier_id", Type: DB_BigInt, Nullable: false},
+			{Name: "state", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "status", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "attempts", Type: DB_Int, Nullable: false},
+			{Name: "last_error", Type: DB_Text, Nullable: true},
+			{Name: "payload", Type: DB_Text, Nullable: true},
+			{Name: "next_attempt", Type: DB_DateTime, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "notifier_id"}, Type: IndexType},
+			{Cols: []string{"status", "next_attempt"}, Type: IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_delivery table v1", NewAddTableMigration(alertNotificationDeliveryV1))
+	mg.AddMigration("add index alert_notification_delivery org_id & notifier_id", NewAddIndexMigration(alertNotificationDeliveryV1, alertNotificationDeliveryV1.Indices[0]))
+	mg.AddMigration("add index alert_notification_delivery status & next_attempt", NewAddIndexMigration(alertNotificationDeliveryV1, alertNotificationDeliveryV1.Indices[1]))
+
+	mg.AddMigration("Add column dedup_key to alert_notification_delivery", NewAddColumnMigration(alertNotificationDeliveryV1, &Column{
+		Name: "dedup_key", Type: DB_NVarchar, Length: 190, Nullable: true,
+	}))
+	mg.AddMigration("add unique index alert_notification_delivery notifier_id & dedup_key", NewAddIndexMigration(alertNotificationDeliveryV1, &Index{
+		Cols: []string{"notifier_id", "dedup_key"}, Type: UniqueIndex,
+	}))
 }
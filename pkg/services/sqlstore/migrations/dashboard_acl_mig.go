@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addDashboardAclMigrations(mg *Migrator) {
+	dashboardAclV1 := Table{
+		Name: "dashboard_acl",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt},
+			{Name: "dashboard_id", Type: DB_BigInt},
+			{Name: "user_id", Type: DB_BigInt, Nullable: true},
+			{Name: "role", Type: DB_Varchar, Length: 20, Nullable: true},
+			{Name: "permission", Type: DB_SmallInt, Default: "4"},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"dashboard_id"}},
+			{Cols: []string{"org_id", "user_id"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard acl table", NewAddTableMigration(dashboardAclV1))
+
+	//-------  indexes ------------------
+	mg.AddMigration("add index dashboard_acl_dashboard_id", NewAddIndexMigration(dashboardAclV1, dashboardAclV1.Indices[0]))
+	mg.AddMigration("add index dashboard_acl_org_id_user_id", NewAddIndexMigration(dashboardAclV1, dashboardAclV1.Indices[1]))
+
+	// teams as a principal in dashboard permissions
+	mg.AddMigration("add column team_id in dashboard_acl", NewAddColumnMigration(dashboardAclV1, &Column{
+		Name: "team_id", Type: DB_BigInt, Nullable: true,
+	}))
+	mg.AddMigration("add index dashboard_acl_team_id", NewAddIndexMigration(dashboardAclV1, &Index{
+		Cols: []string{"team_id"},
+	}))
+
+	// dashboards keep their ACL when all entries are removed
+	mg.AddMigration("add column has_acl in dashboard", NewAddColumnMigration(Table{Name: "dashboard"}, &Column{
+		Name: "has_acl", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("Update has_acl column values in dashboard", new(RawSqlMigration).
+		Sqlite("UPDATE dashboard SET has_acl=1 WHERE id IN (SELECT dashboard_id FROM dashboard_acl);").
+		Postgres("UPDATE dashboard SET has_acl=true WHERE id IN (SELECT dashboard_id FROM dashboard_acl);").
+		Mysql("UPDATE dashboard SET has_acl=1 WHERE id IN (SELECT dashboard_id FROM dashboard_acl);"))
+}
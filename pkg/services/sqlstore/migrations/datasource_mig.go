@@ -120,4 +120,19 @@ func addDataSourceMigration(mg *Migrator) {
 		{Name: "json_data", Type: DB_Text, Nullable: true},
 		{Name: "secure_json_data", Type: DB_Text, Nullable: true},
 	}))
+
+	// datasources added from provisioning config cannot be changed by the api
+	mg.AddMigration("Add read_only data column", NewAddColumnMigration(tableV2, &Column{
+		Name: "read_only", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+
+	// datasources keep track of being provisioned so they can be released
+	// when they are removed from the config
+	mg.AddMigration("Add provisioned column", NewAddColumnMigration(tableV2, &Column{
+		Name: "provisioned", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("Update provisioned column values", new(RawSqlMigration).
+		Sqlite("UPDATE data_source SET provisioned=read_only;").
+		Postgres("UPDATE data_source SET provisioned=read_only;").
+		Mysql("UPDATE data_source SET provisioned=read_only;"))
 }
@@ -1,18 +1,23 @@
 package migrations
 
-// // create table
-// 	mg.AddMigration("create alert table v1", NewAddTableMigration(alertV1))
-//
-// 	alert_heartbeat := Table{
-// 		Name: "alert_heartbeat",
-// 		Columns: []*Column{
-// 			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
-// 			{Name: "server_id", Type: DB_NVarchar, Length: 50, Nullable: false},
-// 			{Name: "created", Type: DB_DateTime, Nullable: false},
-// 			{Name: "updated", Type: DB_DateTime, Nullable: false},
-// 		},
-// 	}
-//
-// 	mg.AddMigration("create alert_heartbeat table v1", NewAddTableMigration(alert_heartbeat))
-//
-//
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addAlertHeartbeatMigrations(mg *Migrator) {
+	alertHeartbeatV1 := Table{
+		Name: "alert_heartbeat",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "server_id", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"server_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_heartbeat table v1", NewAddTableMigration(alertHeartbeatV1))
+	mg.AddMigration("add unique index alert_heartbeat server_id", NewAddIndexMigration(alertHeartbeatV1, alertHeartbeatV1.Indices[0]))
+}
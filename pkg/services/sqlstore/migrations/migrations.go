@@ -23,6 +23,11 @@ func AddMigrations(mg *Migrator) {
 	addPlaylistMigrations(mg)
 	addPreferencesMigrations(mg)
 	addAlertMigrations(mg)
+	addAlertNotificationDeliveryMigrations(mg)
+	addAlertHeartbeatMigrations(mg)
+	addDashboardVersionMigration(mg)
+	addDashboardAclMigrations(mg)
+	addTeamMigrations(mg)
 	addAnnotationMig(mg)
 	addTestDataMigrations(mg)
 }
@@ -191,6 +191,12 @@ func DeleteOrg(cmd *m.DeleteOrgCommand) error {
 		deletes := []string{
 			"DELETE FROM star WHERE EXISTS (SELECT 1 FROM dashboard WHERE org_id = ? AND star.dashboard_id = dashboard.id)",
 			"DELETE FROM dashboard_tag WHERE EXISTS (SELECT 1 FROM dashboard WHERE org_id = ? AND dashboard_tag.dashboard_id = dashboard.id)",
+			"DELETE FROM dashboard_version WHERE EXISTS (SELECT 1 FROM dashboard WHERE org_id = ? AND dashboard_version.dashboard_id = dashboard.id)",
+			"DELETE FROM dashboard_provisioning WHERE EXISTS (SELECT 1 FROM dashboard WHERE org_id = ? AND dashboard_provisioning.dashboard_id = dashboard.id)",
+			"DELETE FROM dashboard_view WHERE org_id = ?",
+			"DELETE FROM dashboard_acl WHERE org_id = ?",
+			"DELETE FROM team_member WHERE org_id = ?",
+			"DELETE FROM team WHERE org_id = ?",
 			"DELETE FROM dashboard WHERE org_id = ?",
 			"DELETE FROM api_key WHERE org_id = ?",
 			"DELETE FROM data_source WHERE org_id = ?",
@@ -206,6 +212,11 @@ func DeleteOrg(cmd *m.DeleteOrgCommand) error {
 			}
 		}
 
+		sess.publishAfterCommit(&events.OrgDeleted{
+			Timestamp: time.Now(),
+			Id:        cmd.Id,
+		})
+
 		return nil
 	})
 }
@@ -9,6 +9,13 @@ func init() {
 	bus.AddHandler("sql", GetSystemStats)
 	bus.AddHandler("sql", GetDataSourceStats)
 	bus.AddHandler("sql", GetAdminStats)
+	bus.AddHandler("sql", GetAlertStateStats)
+}
+
+func GetAlertStateStats(query *m.GetAlertStateStatsQuery) error {
+	var rawSql = `SELECT COUNT(*) as count, state FROM alert GROUP BY state`
+	query.Result = make([]*m.AlertStateStats, 0)
+	return x.Sql(rawSql).Find(&query.Result)
 }
 
 func GetDataSourceStats(query *m.GetDataSourceStatsQuery) error {
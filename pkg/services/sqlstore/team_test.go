@@ -0,0 +1,165 @@
+package sqlstore
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func TestTeamCommandsAndQueries(t *testing.T) {
+
+	Convey("Testing Team commands & queries", t, func() {
+		InitTestDB(t)
+
+		Convey("Given saved users and two teams", func() {
+			var userIds []int64
+			var orgId int64
+			for i := 0; i < 3; i++ {
+				userCmd := &m.CreateUserCommand{
+					Email: fmt.Sprint("user", i, "@test.com"),
+					Name:  fmt.Sprint("user", i),
+					Login: fmt.Sprint("loginuser", i),
+				}
+				err := CreateUser(userCmd)
+				So(err, ShouldBeNil)
+				userIds = append(userIds, userCmd.Result.Id)
+
+				if i == 0 {
+					orgId = userCmd.Result.OrgId
+				} else {
+					err = AddOrgUser(&m.AddOrgUserCommand{OrgId: orgId, UserId: userCmd.Result.Id, Role: m.ROLE_VIEWER})
+					So(err, ShouldBeNil)
+				}
+			}
+
+			group1 := m.CreateTeamCommand{OrgId: orgId, Name: "group1 name", Email: "test1@test.com"}
+			group2 := m.CreateTeamCommand{OrgId: orgId, Name: "group2 name", Email: "test2@test.com"}
+
+			err := CreateTeam(&group1)
+			So(err, ShouldBeNil)
+			err = CreateTeam(&group2)
+			So(err, ShouldBeNil)
+
+			Convey("Should be able to create teams and add users", func() {
+				query := &m.SearchTeamsQuery{OrgId: orgId, Name: "group1 name", Page: 1, Limit: 10}
+				err = SearchTeams(query)
+				So(err, ShouldBeNil)
+				So(query.Page, ShouldEqual, 1)
+
+				team1 := query.Result.Teams[0]
+				So(team1.Name, ShouldEqual, "group1 name")
+				So(team1.Email, ShouldEqual, "test1@test.com")
+
+				err = AddTeamMember(&m.AddTeamMemberCommand{OrgId: orgId, TeamId: team1.Id, UserId: userIds[0]})
+				So(err, ShouldBeNil)
+
+				q1 := &m.GetTeamMembersQuery{OrgId: orgId, TeamId: team1.Id}
+				err = GetTeamMembers(q1)
+				So(err, ShouldBeNil)
+				So(q1.Result[0].TeamId, ShouldEqual, team1.Id)
+				So(q1.Result[0].Login, ShouldEqual, "loginuser0")
+
+				err = AddTeamMember(&m.AddTeamMemberCommand{OrgId: orgId, TeamId: team1.Id, UserId: userIds[0]})
+				So(err, ShouldEqual, m.ErrTeamMemberAlreadyAdded)
+			})
+
+			Convey("Should not create a team with a taken name", func() {
+				err := CreateTeam(&m.CreateTeamCommand{OrgId: orgId, Name: "group1 name"})
+				So(err, ShouldEqual, m.ErrTeamNameTaken)
+			})
+
+			Convey("Should be able to search for teams", func() {
+				query := &m.SearchTeamsQuery{OrgId: orgId, Query: "group", Page: 1}
+				err = SearchTeams(query)
+				So(err, ShouldBeNil)
+				So(len(query.Result.Teams), ShouldEqual, 2)
+				So(query.Result.TotalCount, ShouldEqual, 2)
+
+				query2 := &m.SearchTeamsQuery{OrgId: orgId, Query: ""}
+				err = SearchTeams(query2)
+				So(err, ShouldBeNil)
+				So(len(query2.Result.Teams), ShouldEqual, 2)
+
+				query3 := &m.SearchTeamsQuery{OrgId: orgId, Query: "group", Page: 2, Limit: 1}
+				err = SearchTeams(query3)
+				So(err, ShouldBeNil)
+				So(len(query3.Result.Teams), ShouldEqual, 1)
+				So(query3.Result.Teams[0].Name, ShouldEqual, "group2 name")
+			})
+
+			Convey("Should be able to update a team", func() {
+				err := UpdateTeam(&m.UpdateTeamCommand{OrgId: orgId, Id: group1.Result.Id, Name: "group1 renamed"})
+				So(err, ShouldBeNil)
+
+				query := &m.GetTeamByIdQuery{OrgId: orgId, Id: group1.Result.Id}
+				err = GetTeamById(query)
+				So(err, ShouldBeNil)
+				So(query.Result.Name, ShouldEqual, "group1 renamed")
+				So(query.Result.Email, ShouldEqual, "")
+
+				err = UpdateTeam(&m.UpdateTeamCommand{OrgId: orgId, Id: group1.Result.Id, Name: "group2 name"})
+				So(err, ShouldEqual, m.ErrTeamNameTaken)
+			})
+
+			Convey("Should be able to return all teams a user is member of", func() {
+				groupId := group2.Result.Id
+				err := AddTeamMember(&m.AddTeamMemberCommand{OrgId: orgId, TeamId: groupId, UserId: userIds[0]})
+				So(err, ShouldBeNil)
+
+				query := &m.GetTeamsByUserQuery{OrgId: orgId, UserId: userIds[0]}
+				err = GetTeamsByUser(query)
+				So(err, ShouldBeNil)
+				So(len(query.Result), ShouldEqual, 1)
+				So(query.Result[0].Name, ShouldEqual, "group2 name")
+				So(query.Result[0].Email, ShouldEqual, "test2@test.com")
+			})
+
+			Convey("Should be able to remove users from a team", func() {
+				err = AddTeamMember(&m.AddTeamMemberCommand{OrgId: orgId, TeamId: group1.Result.Id, UserId: userIds[0]})
+				So(err, ShouldBeNil)
+
+				err = RemoveTeamMember(&m.RemoveTeamMemberCommand{OrgId: orgId, TeamId: group1.Result.Id, UserId: userIds[0]})
+				So(err, ShouldBeNil)
+
+				q1 := &m.GetTeamMembersQuery{OrgId: orgId, TeamId: group1.Result.Id}
+				err = GetTeamMembers(q1)
+				So(err, ShouldBeNil)
+				So(len(q1.Result), ShouldEqual, 0)
+			})
+
+			Convey("Should be able to delete a team with its members and permissions", func() {
+				groupId := group2.Result.Id
+				err := AddTeamMember(&m.AddTeamMemberCommand{OrgId: orgId, TeamId: groupId, UserId: userIds[1]})
+				So(err, ShouldBeNil)
+
+				dash := insertTestDashboard("team dash", orgId)
+				err = UpdateDashboardAcl(&m.UpdateDashboardAclCommand{
+					DashboardId: dash.Id,
+					OrgId:       orgId,
+					Items:       []*m.DashboardAcl{{TeamId: groupId, Permission: m.PERMISSION_EDIT}},
+				})
+				So(err, ShouldBeNil)
+
+				aclQuery := &m.GetDashboardAclInfoListQuery{DashboardId: dash.Id, OrgId: orgId}
+				err = GetDashboardAclInfoList(aclQuery)
+				So(err, ShouldBeNil)
+				So(len(aclQuery.Result), ShouldEqual, 1)
+				So(aclQuery.Result[0].Team, ShouldEqual, "group2 name")
+
+				err = DeleteTeam(&m.DeleteTeamCommand{OrgId: orgId, Id: groupId})
+				So(err, ShouldBeNil)
+
+				query := &m.GetTeamByIdQuery{OrgId: orgId, Id: groupId}
+				err = GetTeamById(query)
+				So(err, ShouldEqual, m.ErrTeamNotFound)
+
+				err = GetDashboardAclInfoList(aclQuery)
+				So(err, ShouldBeNil)
+				So(len(aclQuery.Result), ShouldEqual, 0)
+			})
+		})
+	})
+}
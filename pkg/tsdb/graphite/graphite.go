@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"golang.org/x/net/context/ctxhttp"
@@ -50,8 +51,8 @@ func (e *GraphiteExecutor) Execute(ctx context.Context, queries tsdb.QuerySlice,
 	result := &tsdb.BatchResult{}
 
 	formData := url.Values{
-		"from":          []string{"-" + formatTimeRange(context.TimeRange.From)},
-		"until":         []string{formatTimeRange(context.TimeRange.To)},
+		"from":          []string{formatFrom(context.TimeRange)},
+		"until":         []string{formatUntil(context.TimeRange)},
 		"format":        []string{"json"},
 		"maxDataPoints": []string{"500"},
 	}
@@ -144,6 +145,23 @@ func (e *GraphiteExecutor) createRequest(data url.Values) (*http.Request, error)
 	return req, err
 }
 
+// formatFrom and formatUntil send absolute time ranges as unix timestamps.
+func formatFrom(timeRange *tsdb.TimeRange) string {
+	if timeRange.IsAbsolute() {
+		return strconv.FormatInt(timeRange.GetFromAsMsEpoch()/1000, 10)
+	}
+
+	return "-" + formatTimeRange(timeRange.From)
+}
+
+func formatUntil(timeRange *tsdb.TimeRange) string {
+	if timeRange.IsAbsolute() {
+		return strconv.FormatInt(timeRange.GetToAsMsEpoch()/1000, 10)
+	}
+
+	return formatTimeRange(timeRange.To)
+}
+
 func formatTimeRange(input string) string {
 	if input == "now" {
 		return input
@@ -1,8 +1,10 @@
 package graphite
 
 import (
-	. "github.com/smartystreets/goconvey/convey"
 	"testing"
+
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
 )
 
 func TestGraphiteFunctions(t *testing.T) {
@@ -29,6 +31,18 @@ func TestGraphiteFunctions(t *testing.T) {
 
 		})
 
+		Convey("formatting relative time range", func() {
+			timeRange := tsdb.NewTimeRange("5m", "now")
+			So(formatFrom(timeRange), ShouldEqual, "-5min")
+			So(formatUntil(timeRange), ShouldEqual, "now")
+		})
+
+		Convey("formatting absolute time range as unix timestamps", func() {
+			timeRange := tsdb.NewTimeRange("1500000000000", "1500000300000")
+			So(formatFrom(timeRange), ShouldEqual, "1500000000")
+			So(formatUntil(timeRange), ShouldEqual, "1500000300")
+		})
+
 		Convey("fix interval format in query for 1m", func() {
 
 			timeRange := fixIntervalFormat("aliasByNode(hitcount(averageSeries(app.grafana.*.dashboards.views.count), '1m'), 4)")
@@ -105,6 +105,10 @@ func (query *Query) renderTags() []string {
 }
 
 func (query *Query) renderTimeFilter(queryContext *tsdb.QueryContext) string {
+	if queryContext.TimeRange.IsAbsolute() {
+		return fmt.Sprintf("time > %dms and time < %dms", queryContext.TimeRange.GetFromAsMsEpoch(), queryContext.TimeRange.GetToAsMsEpoch())
+	}
+
 	from := "now() - " + queryContext.TimeRange.From
 	to := ""
 
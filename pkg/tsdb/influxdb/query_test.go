@@ -109,6 +109,11 @@ func TestInfluxdbQueryBuilder(t *testing.T) {
 				queryContext := &tsdb.QueryContext{TimeRange: tsdb.NewTimeRange("10m", "now")}
 				So(query.renderTimeFilter(queryContext), ShouldEqual, "time > now() - 10m")
 			})
+
+			Convey("render absolute time range", func() {
+				queryContext := &tsdb.QueryContext{TimeRange: tsdb.NewTimeRange("1500000000000", "1500000300000")}
+				So(query.renderTimeFilter(queryContext), ShouldEqual, "time > 1500000000000ms and time < 1500000300000ms")
+			})
 		})
 
 		Convey("can build query from raw query", func() {
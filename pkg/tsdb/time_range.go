@@ -15,12 +15,30 @@ func NewTimeRange(from, to string) *TimeRange {
 	}
 }
 
+// NewAbsoluteTimeRange creates a time range between two points in time,
+// its From and To are epoch ms.
+func NewAbsoluteTimeRange(from, to time.Time) *TimeRange {
+	return &TimeRange{
+		From: strconv.FormatInt(from.UnixNano()/int64(time.Millisecond), 10),
+		To:   strconv.FormatInt(to.UnixNano()/int64(time.Millisecond), 10),
+		Now:  to,
+	}
+}
+
 type TimeRange struct {
 	From string
 	To   string
 	Now  time.Time
 }
 
+// IsAbsolute is true when From and To are epoch ms instead of being
+// relative to Now.
+func (tr *TimeRange) IsAbsolute() bool {
+	_, fromIsEpoch := tryParseUnixMsEpoch(tr.From)
+	_, toIsEpoch := tryParseUnixMsEpoch(tr.To)
+	return fromIsEpoch && toIsEpoch
+}
+
 func (tr *TimeRange) GetFromAsMsEpoch() int64 {
 	return tr.MustGetFrom().UnixNano() / int64(time.Millisecond)
 }
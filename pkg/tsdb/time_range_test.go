@@ -12,6 +12,16 @@ func TestTimeRange(t *testing.T) {
 
 		now := time.Now()
 
+		Convey("Can create absolute time range", func() {
+			from := time.Unix(1500000000, 0)
+			tr := NewAbsoluteTimeRange(from, from.Add(time.Hour))
+
+			So(tr.From, ShouldEqual, "1500000000000")
+			So(tr.To, ShouldEqual, "1500003600000")
+			So(tr.IsAbsolute(), ShouldBeTrue)
+			So(NewTimeRange("5m", "now").IsAbsolute(), ShouldBeFalse)
+		})
+
 		Convey("Can parse 5m, now", func() {
 			tr := TimeRange{
 				From: "5m",
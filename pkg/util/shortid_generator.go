@@ -0,0 +1,24 @@
+package util
+
+import (
+	"regexp"
+)
+
+const (
+	shortUidLength  = 9
+	MaxShortUidSize = 40
+)
+
+var validUidPattern = regexp.MustCompile(`^[a-zA-Z0-9\-\_]*$`).MatchString
+
+// GenerateShortUid returns a short random id that can be used as a stable
+// identifier in urls.
+func GenerateShortUid() string {
+	return GetRandomString(shortUidLength)
+}
+
+// IsValidShortUid checks that a uid only contains url safe characters and
+// is not longer than MaxShortUidSize.
+func IsValidShortUid(uid string) bool {
+	return len(uid) <= MaxShortUidSize && validUidPattern(uid)
+}
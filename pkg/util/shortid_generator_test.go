@@ -0,0 +1,23 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestShortUid(t *testing.T) {
+	Convey("Generated uids should be valid and differ", t, func() {
+		uid := GenerateShortUid()
+		So(len(uid), ShouldEqual, 9)
+		So(IsValidShortUid(uid), ShouldBeTrue)
+		So(GenerateShortUid(), ShouldNotEqual, uid)
+	})
+
+	Convey("Uids with invalid characters or too long should be invalid", t, func() {
+		So(IsValidShortUid("my-dash_1"), ShouldBeTrue)
+		So(IsValidShortUid("my/dash"), ShouldBeFalse)
+		So(IsValidShortUid(strings.Repeat("a", 41)), ShouldBeFalse)
+	})
+}
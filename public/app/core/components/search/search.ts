@@ -15,6 +15,7 @@ export class SearchCtrl {
   results: any;
   currentSearchId: number;
   tagsMode: boolean;
+  folderTitle: string;
   showImport: boolean;
   dismiss: any;
   ignoreClose: any;
@@ -39,7 +40,7 @@ export class SearchCtrl {
     this.giveSearchFocus = 0;
     this.selectedIndex = -1;
     this.results = [];
-    this.query = { query: '', tag: [], starred: false };
+    this.query = { query: '', tag: [], starred: false, folderIds: [] };
     this.currentSearchId = 0;
     this.ignoreClose = true;
 
@@ -82,6 +83,11 @@ export class SearchCtrl {
       }
 
       var selectedDash = this.results[this.selectedIndex];
+      if (selectedDash && selectedDash.type === 'dash-folder') {
+        this.filterByFolder(selectedDash, null);
+        return;
+      }
+
       if (selectedDash) {
         this.$location.search({});
         this.$location.path(selectedDash.url);
@@ -104,7 +110,9 @@ export class SearchCtrl {
       if (localSearchId < this.currentSearchId) { return; }
 
       this.results = _.map(results, function(dash) {
-        dash.url = 'dashboard/' + dash.uri;
+        if (dash.type !== 'dash-folder') {
+          dash.url = 'dashboard/' + dash.uri;
+        }
         return dash;
       });
 
@@ -116,7 +124,27 @@ export class SearchCtrl {
 
   queryHasNoFilters() {
     var query = this.query;
-    return query.query === '' && query.starred === false && query.tag.length === 0;
+    return query.query === '' && query.starred === false && query.tag.length === 0 && query.folderIds.length === 0;
+  }
+
+  filterByFolder(folder, evt) {
+    this.query.folderIds = [folder.id];
+    this.folderTitle = folder.title;
+    this.search();
+    this.giveSearchFocus = this.giveSearchFocus + 1;
+    if (evt) {
+      evt.stopPropagation();
+      evt.preventDefault();
+    }
+  }
+
+  removeFolder(evt) {
+    this.query.folderIds = [];
+    this.folderTitle = null;
+    this.search();
+    this.giveSearchFocus = this.giveSearchFocus + 1;
+    evt.stopPropagation();
+    evt.preventDefault();
   }
 
   filterByTag(tag, evt) {
@@ -24,8 +24,9 @@ var alertStateSortScore = {
   alerting: 1,
   no_data: 2,
   pending: 3,
-  ok: 4,
-  paused: 5,
+  inhibited: 4,
+  ok: 5,
+  paused: 6,
 };
 
 var evalFunctions = [
@@ -33,9 +34,28 @@ var evalFunctions = [
   {text: 'IS BELOW', value: 'lt'},
   {text: 'IS OUTSIDE RANGE', value: 'outside_range'},
   {text: 'IS WITHIN RANGE', value: 'within_range'},
-  {text: 'HAS NO VALUE' , value: 'no_value'}
+  {text: 'HAS NO VALUE' , value: 'no_value'},
+  {text: 'DEVIATES FROM BASELINE', value: 'baseline'},
+  {text: 'DEVIATES FROM MEAN', value: 'stddev'},
+  {text: 'FORECAST', value: 'forecast'},
 ];
 
+// evaluators that run extra queries, params are edited as text
+var queryEvaluators = {
+  baseline: {
+    defaults: ['1w', 20, 'outside'],
+    labels: ['SHIFTED', 'BY %', 'DIRECTION'],
+  },
+  stddev: {
+    defaults: ['6h', 3, 'outside'],
+    labels: ['OVER', 'BY STDDEV', 'DIRECTION'],
+  },
+  forecast: {
+    defaults: ['1h', '30m', 90, 'above'],
+    labels: ['TREND OVER', 'AHEAD', 'THRESHOLD', 'DIRECTION'],
+  },
+};
+
 var evalOperators = [
   {text: 'OR', value: 'or'},
   {text: 'AND', value: 'and'},
@@ -49,6 +69,13 @@ var reducerTypes = [
   {text: 'count()', value: 'count'},
   {text: 'last()', value: 'last'},
   {text: 'median()', value: 'median'},
+  {text: 'percentile()', value: 'percentile'},
+  {text: 'diff()', value: 'diff'},
+  {text: 'percent_diff()', value: 'percent_diff'},
+  {text: 'count_non_null()', value: 'count_non_null'},
+  {text: 'stddev()', value: 'stddev'},
+  {text: 'delta()', value: 'delta'},
+  {text: 'rate()', value: 'rate'},
 ];
 
 var noDataModes = [
@@ -65,6 +92,9 @@ var executionErrorModes = [
 
 function createReducerPart(model) {
   var def = new QueryPartDef({type: model.type, defaultParams: []});
+  if (model.type === 'percentile') {
+    def = new QueryPartDef({type: model.type, params: [{name: 'percentile', type: 'number'}], defaultParams: ['95']});
+  }
   return new QueryPart(model, def);
 }
 
@@ -113,6 +143,13 @@ function getStateDisplayModel(state) {
         stateClass: 'alert-state-warning'
       };
     }
+    case 'inhibited': {
+      return {
+        text: 'INHIBITED',
+        iconClass: "fa fa-chain",
+        stateClass: 'alert-state-paused'
+      };
+    }
   }
 }
 
@@ -136,6 +173,7 @@ export default {
   getStateDisplayModel: getStateDisplayModel,
   conditionTypes: conditionTypes,
   evalFunctions: evalFunctions,
+  queryEvaluators: queryEvaluators,
   evalOperators: evalOperators,
   noDataModes: noDataModes,
   executionErrorModes: executionErrorModes,
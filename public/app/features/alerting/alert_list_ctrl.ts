@@ -15,6 +15,7 @@ export class AlertListCtrl {
     {text: 'OK', value: 'ok'},
     {text: 'Alerting', value: 'alerting'},
     {text: 'No Data', value: 'no_data'},
+    {text: 'Inhibited', value: 'inhibited'},
     {text: 'Paused', value: 'paused'},
   ];
 
@@ -18,6 +18,7 @@ export class AlertTabCtrl {
   alert: any;
   conditionModels: any;
   evalFunctions: any;
+  queryEvaluators: any;
   evalOperators: any;
   noDataModes: any;
   executionErrorModes: any;
@@ -42,6 +43,7 @@ export class AlertTabCtrl {
     this.$scope.ctrl = this;
     this.subTabIndex = 0;
     this.evalFunctions = alertDef.evalFunctions;
+    this.queryEvaluators = alertDef.queryEvaluators;
     this.evalOperators = alertDef.evalOperators;
     this.conditionTypes = alertDef.conditionTypes;
     this.noDataModes = alertDef.noDataModes;
@@ -296,6 +298,7 @@ export class AlertTabCtrl {
     switch (evt.name) {
       case "action": {
         conditionModel.source.reducer.type = evt.action.value;
+        conditionModel.source.reducer.params = null;
         conditionModel.reducerPart = alertDef.createReducerPart(conditionModel.source.reducer);
         break;
       }
@@ -367,6 +370,13 @@ export class AlertTabCtrl {
       }
       case "no_value": {
         evaluator.params = [];
+        break;
+      }
+      case "baseline":
+      case "stddev":
+      case "forecast": {
+        evaluator.params = this.queryEvaluators[evaluator.type].defaults.slice();
+        break;
       }
     }
 
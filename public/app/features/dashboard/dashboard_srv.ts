@@ -34,6 +34,9 @@ export class DashboardSrv {
 
     var clone = this.dash.getSaveModelClone();
 
+    // keep the dashboard in its folder
+    options = Object.assign({folderId: this.dash.meta.folderId}, options);
+
     return this.backendSrv.saveDashboard(clone, options).then(data => {
       this.dash.version = data.version;
 
@@ -64,6 +67,21 @@ export class DashboardSrv {
       });
     }
 
+    if (err.data && err.data.status === "uid-exists") {
+      err.isHandled = true;
+
+      this.$rootScope.appEvent('confirm-modal', {
+        title: 'Conflict',
+        text: 'Dashboard with the same uid exists.',
+        text2: 'Would you still like to save this dashboard?',
+        yesText: "Save & Overwrite",
+        icon: "fa-warning",
+        onConfirm: () => {
+          this.saveDashboard({overwrite: true});
+        }
+      });
+    }
+
     if (err.data && err.data.status === "name-exists") {
       err.isHandled = true;
 
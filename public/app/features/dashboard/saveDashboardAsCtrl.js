@@ -10,6 +10,7 @@ function (angular) {
 
     $scope.init = function() {
       $scope.clone.id = null;
+      $scope.clone.uid = null;
       $scope.clone.editable = true;
       $scope.clone.title = $scope.clone.title + " Copy";
 